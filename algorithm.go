@@ -0,0 +1,81 @@
+package multiavatar
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Algorithm is a version of the input-to-parts selection algorithm.
+type Algorithm int
+
+const (
+	// AlgorithmV1 is the selection of the original JavaScript library: the
+	// hex SHA-256 digest is stripped of non-digits and every part takes two
+	// digits, scaled from 0..99 to 0..47. The scaling picks some versions
+	// and themes up to three times as often as others. It is the default.
+	AlgorithmV1 Algorithm = 1
+	// AlgorithmV2 reads the digest bytes directly, so every version and
+	// theme of every part is equally likely. Inputs get different avatars
+	// than under AlgorithmV1.
+	AlgorithmV2 Algorithm = 2
+)
+
+// knownAlgorithms lists the selection algorithm versions this package implements.
+var knownAlgorithms = []int{int(AlgorithmV1), int(AlgorithmV2)}
+
+// WithAlgorithm selects the selection algorithm. Switching changes the
+// avatar of existing inputs; use MigrateSeed to keep them. Unknown versions
+// are ignored and reported as errors by the error-returning APIs.
+func WithAlgorithm(a Algorithm) Option {
+	return func(c *config) {
+		if !IsKnownAlgorithm(int(a)) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown algorithm %d", int(a)))
+			return
+		}
+		c.algorithm = a
+	}
+}
+
+// hashSlot is the hash-derived choice for one part: nr is the 0..47
+// version/theme slot and val the raw value that indexes allowed lists.
+type hashSlot struct {
+	nr, val int
+}
+
+// hashSlots derives the choice of every part, in partNames order.
+func (cfg *config) hashSlots(input string) [6]hashSlot {
+	sum := cfg.inputSum(input)
+	var slots [6]hashSlot
+
+	if cfg.algorithm == AlgorithmV2 {
+		// 48^6 < 2^64: successive base-48 digits of the first 8 bytes,
+		// uniform up to a bias below 1e-9
+		n := binary.BigEndian.Uint64(sum[:8])
+		for i := range slots {
+			slots[i].nr = int(n % 48)
+			n /= 48
+			slots[i].val = int(binary.BigEndian.Uint16(sum[8+2*i:]))
+		}
+		return slots
+	}
+
+	// Remove non-digits (mimicking JS replace(/\D/g, '')) and take the first 12 digits
+	hashStr := stripNonDigits(hex.EncodeToString(sum[:]))
+	if len(hashStr) > 12 {
+		hashStr = hashStr[:12]
+	}
+	for i := range slots {
+		// Take 2 digits and scale to 0-47 range
+		val, _ := strconv.Atoi(hashStr[i*2 : i*2+2])
+		slots[i] = hashSlot{nr: scaleSlot(val), val: val}
+	}
+	return slots
+}
+
+// scaleSlot scales a 0..99 AlgorithmV1 value to its 0..47 slot.
+func scaleSlot(val int) int {
+	return int(math.Round(float64(val) * 47 / 100))
+}
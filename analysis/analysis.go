@@ -0,0 +1,190 @@
+// Package analysis measures how evenly multiavatar spreads inputs over its
+// part versions and themes, and estimates how often users share an avatar.
+//
+//	r := analysis.Distribution(userIDs)
+//	fmt.Printf("%.2f%% chance of a duplicate among 10k users\n", 100*r.CollisionProbability(10000))
+package analysis
+
+import (
+	"math"
+	"strings"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// Parts lists the part names in selection order.
+var Parts = []string{"env", "clo", "head", "mouth", "eyes", "top"}
+
+// themes are the theme names, in the order of their slots.
+var themes = []string{"A", "B", "C"}
+
+// Slots is the number of (version, theme) combinations a part can take.
+const Slots = 48
+
+// Report is the selection distribution of a set of inputs.
+type Report struct {
+	// Inputs is the number of non-empty inputs analyzed.
+	Inputs int
+	// Parts holds the statistics of every part, keyed by part name.
+	Parts map[string]*PartStats
+	// Distinct is the number of different avatars among the inputs.
+	Distinct int
+	// Collisions is the number of inputs whose avatar equals that of an
+	// earlier input.
+	Collisions int
+}
+
+// PartStats counts the selections of one part.
+type PartStats struct {
+	// Versions counts selections of "00".."15".
+	Versions [16]int
+	// Themes counts selections of "A", "B" and "C".
+	Themes map[string]int
+	// Slots counts selections of every (version, theme) pair, indexed by
+	// theme*16 + version; this is the 0..47 value derived from the hash.
+	Slots [Slots]int
+	// ChiSquare is Pearson's statistic of Slots against a uniform
+	// distribution, with 47 degrees of freedom: about 47 is even, values
+	// far above it reveal banding.
+	ChiSquare float64
+	// Entropy is the Shannon entropy of Slots in bits; the maximum is
+	// log2(48) ≈ 5.585.
+	Entropy float64
+	// MaxMinRatio is the share of the most frequent slot divided by that of
+	// the least frequent one; +Inf if a slot was never selected.
+	MaxMinRatio float64
+}
+
+// Distribution resolves every input under the default algorithm and
+// reports the selection statistics. Empty inputs are skipped.
+func Distribution(inputs []string) *Report {
+	return DistributionFor(inputs, multiavatar.AlgorithmV1)
+}
+
+// DistributionFor is like Distribution for the given selection algorithm,
+// e.g. to compare multiavatar.AlgorithmV2 against the default. An unknown
+// algorithm gives an empty report.
+func DistributionFor(inputs []string, algorithm multiavatar.Algorithm) *Report {
+	r := &Report{Parts: make(map[string]*PartStats, len(Parts))}
+	for _, p := range Parts {
+		r.Parts[p] = &PartStats{Themes: make(map[string]int, len(themes))}
+	}
+	seen := make(map[string]bool, len(inputs))
+	var key strings.Builder
+	for _, in := range inputs {
+		if in == "" {
+			continue
+		}
+		spec, err := multiavatar.MigrateSeed(in, int(algorithm), int(algorithm))
+		if err != nil {
+			return r
+		}
+		r.Inputs++
+		key.Reset()
+		for _, p := range Parts {
+			ps := spec.Parts[p]
+			v := version(ps.Version)
+			t := themeIndex(ps.Theme)
+			st := r.Parts[p]
+			st.Versions[v]++
+			st.Themes[ps.Theme]++
+			st.Slots[t*16+v]++
+			key.WriteString(ps.Version)
+			key.WriteString(ps.Theme)
+		}
+		if seen[key.String()] {
+			r.Collisions++
+		} else {
+			seen[key.String()] = true
+		}
+	}
+	r.Distinct = len(seen)
+	for _, st := range r.Parts {
+		st.summarize(r.Inputs)
+	}
+	return r
+}
+
+// summarize computes the derived statistics from the slot counts.
+func (st *PartStats) summarize(n int) {
+	if n == 0 {
+		return
+	}
+	expected := float64(n) / Slots
+	lo, hi := math.MaxInt, 0
+	for _, c := range st.Slots {
+		d := float64(c) - expected
+		st.ChiSquare += d * d / expected
+		if c > 0 {
+			p := float64(c) / float64(n)
+			st.Entropy -= p * math.Log2(p)
+		}
+		lo, hi = min(lo, c), max(hi, c)
+	}
+	if lo == 0 {
+		st.MaxMinRatio = math.Inf(1)
+	} else {
+		st.MaxMinRatio = float64(hi) / float64(lo)
+	}
+}
+
+// matchProbability estimates the probability that two random inputs get
+// the same avatar, treating parts as independent.
+func (r *Report) matchProbability() float64 {
+	if r.Inputs == 0 {
+		return 0
+	}
+	q := 1.0
+	for _, st := range r.Parts {
+		var s float64
+		for _, c := range st.Slots {
+			p := float64(c) / float64(r.Inputs)
+			s += p * p
+		}
+		q *= s
+	}
+	return q
+}
+
+// EffectiveCombinations is the number of equally likely avatars that would
+// give the same chance of two users matching as the observed distribution.
+// A perfectly even selection reaches 48^6 ≈ 1.22e10.
+func (r *Report) EffectiveCombinations() float64 {
+	q := r.matchProbability()
+	if q == 0 {
+		return 0
+	}
+	return 1 / q
+}
+
+// CollisionProbability estimates the chance that at least two of users
+// random users share an avatar, from the observed per-part distribution
+// (birthday bound). Reliable estimates need enough inputs to populate every
+// slot, e.g. 10,000 or more.
+func (r *Report) CollisionProbability(users int) float64 {
+	if users < 2 {
+		return 0
+	}
+	n := float64(users)
+	return -math.Expm1(-n * (n - 1) / 2 * r.matchProbability())
+}
+
+func version(v string) int {
+	if len(v) != 2 {
+		return 0
+	}
+	n := int(v[0]-'0')*10 + int(v[1]-'0')
+	if n < 0 || n > 15 {
+		return 0
+	}
+	return n
+}
+
+func themeIndex(t string) int {
+	for i, name := range themes {
+		if name == t {
+			return i
+		}
+	}
+	return 0
+}
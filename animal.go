@@ -0,0 +1,149 @@
+package multiavatar
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StyleAnimal draws a cat, dog, fox or owl face, for kid-focused products
+// and anonymous commenters. The species follows the head version, the ears
+// the top version, and the eyes, mouth and collar the versions of the
+// matching parts, so the same input always gets the same animal. Fur comes
+// from a palette per species and head theme; WithPartColors("head", fur,
+// light) overrides it and WithPartColors("clo", ...) colors the collar.
+const StyleAnimal = "animal"
+
+func init() {
+	registerComposer(StyleAnimal, true, writeAnimal)
+}
+
+// animalSpecies is the art and palette of one species. Its pieces use the
+// placeholders #1 fur, #2 light fur, #3 inner ear, #4 dark fur, #5 nose
+// and #6 beak.
+type animalSpecies struct {
+	ears [2]string
+	face string
+	// palette holds the fur and light fur colors for themes A, B and C
+	palette [3][2]string
+	// beak replaces the mouth
+	beak bool
+}
+
+var animalSpeciesList = []animalSpecies{
+	{ // cat
+		ears: [2]string{
+			`<path d="M60,104L66,38L112,72Z" style="fill:#1;"/><path d="M171,104L165,38L119,72Z" style="fill:#1;"/><path d="M71,90L74,54L100,74Z" style="fill:#3;"/><path d="M160,90L157,54L131,74Z" style="fill:#3;"/>`,
+			`<path d="M58,102Q54,52 80,52Q100,56 110,72Z" style="fill:#1;"/><path d="M173,102Q177,52 151,52Q131,56 121,72Z" style="fill:#1;"/>`,
+		},
+		face: `<ellipse cx="115.5" cy="124" rx="64" ry="56" style="fill:#1;"/><ellipse cx="115.5" cy="147" rx="26" ry="18" style="fill:#2;"/>` +
+			`<path d="M66,140H92M68,152L92,147M165,140H139M163,152L139,147" style="fill:none;stroke:#4;stroke-width:2"/><path d="M108,137H123L115.5,145Z" style="fill:#3;"/>`,
+		palette: [3][2]string{{"#f2a65a", "#fde3c4"}, {"#8d8f99", "#e4e4ea"}, {"#3b3b45", "#9a9aa6"}},
+	},
+	{ // dog
+		ears: [2]string{
+			`<path d="M64,84C38,86 32,146 54,158C72,152 80,108 76,88Z" style="fill:#4;"/><path d="M167,84C193,86 199,146 177,158C159,152 151,108 155,88Z" style="fill:#4;"/>`,
+			`<path d="M62,104L70,44L108,76Z" style="fill:#4;"/><path d="M169,104L161,44L123,76Z" style="fill:#4;"/>`,
+		},
+		face:    `<ellipse cx="115.5" cy="122" rx="58" ry="60" style="fill:#1;"/><ellipse cx="115.5" cy="149" rx="32" ry="24" style="fill:#2;"/><ellipse cx="115.5" cy="139" rx="10" ry="7" style="fill:#5;"/>`,
+		palette: [3][2]string{{"#b07a4f", "#f1dcc0"}, {"#e3b46b", "#fff3dc"}, {"#f4f1ea", "#d8c4ae"}},
+	},
+	{ // fox
+		ears: [2]string{
+			`<path d="M56,100L58,30L106,70Z" style="fill:#1;"/><path d="M175,100L173,30L125,70Z" style="fill:#1;"/><path d="M58,52L58,30L76,45Z" style="fill:#5;"/><path d="M173,52L173,30L155,45Z" style="fill:#5;"/>`,
+			`<path d="M60,100L66,50L104,72Z" style="fill:#1;"/><path d="M171,100L165,50L127,72Z" style="fill:#1;"/><path d="M70,86L72,62L92,74Z" style="fill:#2;"/><path d="M161,86L159,62L139,74Z" style="fill:#2;"/>`,
+		},
+		face: `<path d="M48,96Q115.5,58 183,96Q178,148 115.5,182Q53,148 48,96Z" style="fill:#1;"/>` +
+			`<path d="M52,112Q84,122 102,152Q110,170 115.5,182Q68,160 52,112Z" style="fill:#2;"/><path d="M179,112Q147,122 129,152Q121,170 115.5,182Q163,160 179,112Z" style="fill:#2;"/><ellipse cx="115.5" cy="140" rx="8" ry="6" style="fill:#5;"/>`,
+		palette: [3][2]string{{"#e8692c", "#fff7ee"}, {"#f0f0f4", "#d5d9e4"}, {"#9aa3b5", "#f4f6fa"}},
+	},
+	{ // owl
+		ears: [2]string{
+			`<path d="M64,86L56,38L98,70Z" style="fill:#4;"/><path d="M167,86L175,38L133,70Z" style="fill:#4;"/>`,
+			``,
+		},
+		face:    `<ellipse cx="115.5" cy="126" rx="64" ry="60" style="fill:#1;"/><circle cx="93" cy="116" r="25" style="fill:#2;"/><circle cx="138" cy="116" r="25" style="fill:#2;"/><path d="M108,134H123L115.5,150Z" style="fill:#6;"/>`,
+		palette: [3][2]string{{"#8a5a3c", "#e9d3b5"}, {"#f3f1ea", "#d8d2c2"}, {"#6f7684", "#d7dbe3"}},
+		beak:    true,
+	},
+}
+
+// animalEyes use #1 for pupils and #2 for irises, by eyes version.
+var animalEyes = []string{
+	`<circle cx="95" cy="116" r="9" style="fill:#1;"/><circle cx="136" cy="116" r="9" style="fill:#1;"/><circle cx="98" cy="113" r="3" style="fill:#fff;"/><circle cx="139" cy="113" r="3" style="fill:#fff;"/>`,
+	`<circle cx="95" cy="116" r="11" style="fill:#2;"/><circle cx="136" cy="116" r="11" style="fill:#2;"/><circle cx="95" cy="116" r="5" style="fill:#1;"/><circle cx="136" cy="116" r="5" style="fill:#1;"/><circle cx="98" cy="112" r="2.5" style="fill:#fff;"/><circle cx="139" cy="112" r="2.5" style="fill:#fff;"/>`,
+	`<path d="M85,119Q95,106 105,119M126,119Q136,106 146,119" style="fill:none;stroke:#1;stroke-width:4;stroke-linecap:round"/>`,
+	`<ellipse cx="95" cy="116" rx="9" ry="12" style="fill:#2;"/><ellipse cx="136" cy="116" rx="9" ry="12" style="fill:#2;"/><ellipse cx="95" cy="116" rx="2.5" ry="10" style="fill:#1;"/><ellipse cx="136" cy="116" rx="2.5" ry="10" style="fill:#1;"/>`,
+}
+
+// animalMouths use #1 for lines and #2 for the tongue, by mouth version.
+var animalMouths = []string{
+	`<path d="M115.5,145V150M103,150Q109,157 115.5,150Q122,157 128,150" style="fill:none;stroke:#1;stroke-width:3;stroke-linecap:round"/>`,
+	`<path d="M104,151Q115.5,170 127,151Z" style="fill:#1;"/><path d="M109,159Q115.5,168 122,159Q115.5,155 109,159Z" style="fill:#2;"/>`,
+	`<path d="M102,151Q115.5,162 129,151" style="fill:none;stroke:#1;stroke-width:3;stroke-linecap:round"/>`,
+}
+
+// animalBody is the chest below the face, in fur color.
+const animalBody = `<path d="M56,214Q60,178 115.5,174Q171,178 175,214A115.5,115.5,0,0,1,56,214Z" style="fill:#1;"/>`
+
+// animalCollars use #1 for the collar and #2 for its tag, by clo version;
+// the first is none.
+var animalCollars = []string{
+	``,
+	`<path d="M78,182Q115.5,198 153,182" style="fill:none;stroke:#1;stroke-width:8;stroke-linecap:round"/><circle cx="115.5" cy="199" r="6" style="fill:#2;"/>`,
+	`<path d="M115.5,189L95,178V200ZM115.5,189L136,178V200Z" style="fill:#1;"/><circle cx="115.5" cy="189" r="5" style="fill:#1;"/>`,
+	`<path d="M74,180Q115.5,204 157,180L160,193Q115.5,217 71,193Z" style="fill:#1;"/>`,
+}
+
+// Colors of pupils and mouths, inner ears and tongues, collar tags and
+// beaks.
+const (
+	animalDark  = "#2b2326"
+	animalPink  = "#f29bab"
+	animalGold  = "#f7c325"
+	animalBeak  = "#f0a030"
+	animalIris  = "#6bb84c"
+	animalShade = 0.25
+)
+
+// writeAnimal draws the animal for the selected parts.
+func writeAnimal(cfg *config, b svgWriter, selected []selectedPart) {
+	byName := make(map[string]selectedPart, len(selected))
+	for _, p := range selected {
+		byName[p.name] = p
+	}
+	head := byName["head"]
+	v, _ := strconv.Atoi(head.version)
+	sp := animalSpeciesList[v%len(animalSpeciesList)]
+
+	theme := max(0, strings.Index("ABC", head.theme))
+	fur, light := cfg.filterColor(sp.palette[theme][0]), cfg.filterColor(sp.palette[theme][1])
+	if len(cfg.overrideColors["head"]) > 0 {
+		fur = partColor(head, 0, fur)
+		light = partColor(head, 1, light)
+	}
+	dark, pink := cfg.filterColor(animalDark), cfg.filterColor(animalPink)
+	pieceColors := []string{fur, light, pink, shade(fur, animalShade), dark, cfg.filterColor(animalBeak)}
+
+	if !cfg.disabledParts["env"] {
+		art := *cfg
+		art.pack = nil
+		art.writeLayer(b, byName["env"])
+	}
+	draw := func(part, svg string, colors ...string) {
+		if cfg.disabledParts[part] || svg == "" {
+			return
+		}
+		b.WriteString(cfg.fadePart(part, fillColors(svg, colors)))
+	}
+	clo := byName["clo"]
+	draw("clo", animalBody, fur)
+	draw("clo", pick(animalCollars, clo.version), partColor(clo, 0, cfg.filterColor(animalGold)), cfg.filterColor(animalGold))
+	top := byName["top"]
+	draw("top", pick(sp.ears[:], top.version), pieceColors...)
+	draw("head", sp.face, pieceColors...)
+	eyes := byName["eyes"]
+	draw("eyes", pick(animalEyes, eyes.version), dark, vividColor(eyes.colors, cfg.filterColor(animalIris)))
+	if !sp.beak {
+		draw("mouth", pick(animalMouths, byName["mouth"].version), dark, pink)
+	}
+}
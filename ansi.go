@@ -0,0 +1,84 @@
+package multiavatar
+
+import (
+	"context"
+	"image"
+	"strconv"
+	"strings"
+)
+
+// maxANSICols bounds the width of GenerateANSI output.
+const maxANSICols = 512
+
+// GenerateANSI renders a blocky approximation of the avatar for terminals,
+// cols characters wide and cols/2 lines tall, using 24-bit ANSI colors.
+// Every character shows two pixels stacked with the "▀" half block, so the
+// avatar looks square in fonts about twice as tall as wide. Pixels outside
+// the avatar keep the terminal's background. Each line ends with a reset
+// and a newline. It returns "" for empty input or cols outside 1..512.
+func GenerateANSI(input string, cols int, opts ...Option) string {
+	if input == "" || cols <= 0 || cols > maxANSICols {
+		return ""
+	}
+	cfg := newConfig(opts)
+	var svg strings.Builder
+	cfg.writeSVG(&svg, cfg.selectParts(input))
+	rows := (cols + 1) / 2
+	img, err := cfg.rasterizeAvatar(context.Background(), svg.String(), cols, rows*2)
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.Grow(rows * cols * 40)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			top, topOK := ansiPixel(img, x, 2*y)
+			bottom, bottomOK := ansiPixel(img, x, 2*y+1)
+			switch {
+			case topOK && bottomOK:
+				writeANSIColor(&b, "38", top)
+				writeANSIColor(&b, "48", bottom)
+				b.WriteString("▀")
+			case topOK:
+				b.WriteString("\x1b[49m")
+				writeANSIColor(&b, "38", top)
+				b.WriteString("▀")
+			case bottomOK:
+				b.WriteString("\x1b[49m")
+				writeANSIColor(&b, "38", bottom)
+				b.WriteString("▄")
+			default:
+				b.WriteString("\x1b[0m ")
+			}
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	return b.String()
+}
+
+// ansiPixel returns the unpremultiplied color at x, y, and false when the
+// pixel is mostly transparent.
+func ansiPixel(img *image.RGBA, x, y int) ([3]uint8, bool) {
+	i := img.PixOffset(x, y)
+	px := img.Pix[i : i+4]
+	if px[3] < 128 {
+		return [3]uint8{}, false
+	}
+	r, g, b := unpremultiply(px)
+	return [3]uint8{r, g, b}, true
+}
+
+// writeANSIColor writes an SGR truecolor sequence; layer is "38" for the
+// foreground and "48" for the background.
+func writeANSIColor(b *strings.Builder, layer string, c [3]uint8) {
+	b.WriteString("\x1b[")
+	b.WriteString(layer)
+	b.WriteString(";2;")
+	b.WriteString(strconv.Itoa(int(c[0])))
+	b.WriteByte(';')
+	b.WriteString(strconv.Itoa(int(c[1])))
+	b.WriteByte(';')
+	b.WriteString(strconv.Itoa(int(c[2])))
+	b.WriteByte('m')
+}
@@ -0,0 +1,138 @@
+package multiavatar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Avatar is an avatar whose parts have been selected once, for rendering
+// the same input repeatedly, e.g. in several sizes on one page. It is safe
+// for concurrent use.
+type Avatar struct {
+	input    string
+	cfg      *config
+	selected []selectedPart
+	err      error
+
+	bodyOnce sync.Once
+	body     string // the document without the <svg> start tag
+
+	treeOnce sync.Once
+	tree     *svgNode
+	treeErr  error
+}
+
+// Resolve hashes input and selects its parts and colors once. The returned
+// Avatar renders without repeating that work. Invalid options and an empty
+// input are reported by Err and by the methods that return errors.
+func Resolve(input string, opts ...Option) *Avatar {
+	cfg := newConfig(opts)
+	a := &Avatar{input: input, cfg: cfg, err: cfg.err()}
+	if input == "" {
+		a.err = ErrEmptyInput
+		return a
+	}
+	a.selected = cfg.selectParts(input)
+	return a
+}
+
+// Err reports invalid options or an empty input.
+func (a *Avatar) Err() error { return a.err }
+
+// SVG returns the SVG document, as Generate would.
+func (a *Avatar) SVG() string {
+	if a.selected == nil {
+		return ""
+	}
+	return a.svgStart(a.cfg.size) + a.svgBody()
+}
+
+// SizedSVG returns the SVG document with width and height set to px pixels.
+func (a *Avatar) SizedSVG(px int) string {
+	if a.selected == nil {
+		return ""
+	}
+	return a.svgStart(px) + a.svgBody()
+}
+
+// DataURI returns the SVG as a base64 data URI for an <img> src or a CSS
+// url(), so no separate request is needed.
+func (a *Avatar) DataURI() string {
+	return a.SafeSVG().DataURI()
+}
+
+// Image renders the avatar as a size×size image, like GenerateImage.
+func (a *Avatar) Image(size int) (image.Image, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	if size <= 0 || size > maxImageSize {
+		return nil, fmt.Errorf("multiavatar: image size %d out of range 1..%d", size, maxImageSize)
+	}
+	if a.cfg.rasterizer != nil {
+		return a.cfg.rasterize(context.Background(), a.SVG(), size, size)
+	}
+	a.treeOnce.Do(func() {
+		a.tree, a.treeErr = parseSVGTree(strings.NewReader(a.SVG()))
+	})
+	if a.treeErr != nil {
+		return nil, a.treeErr
+	}
+	c := newRasterCanvas(size, size, a.tree.viewBox())
+	drawSVG(a.tree, c)
+	a.cfg.drawTexture(c.img, c.img.Bounds())
+	return c.img, nil
+}
+
+// PNG encodes the avatar as a size×size PNG.
+func (a *Avatar) PNG(size int) ([]byte, error) {
+	img, err := a.Image(size)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Spec returns the version and theme selected for every part.
+func (a *Avatar) Spec() AvatarSpec {
+	algo := a.cfg.algorithm
+	if algo == 0 {
+		algo = AlgorithmV1
+	}
+	spec := AvatarSpec{Seed: a.input, Algorithm: int(algo), Parts: make(map[string]PartSpec, len(a.selected))}
+	for _, p := range a.selected {
+		spec.Parts[p.name] = PartSpec{Version: p.version, Theme: p.theme}
+	}
+	return spec
+}
+
+// svgStart returns the <svg> start tag, sized when px > 0.
+func (a *Avatar) svgStart(px int) string {
+	start := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="` + a.cfg.viewBoxAttr() + `"`
+	if px > 0 {
+		n := strconv.Itoa(px)
+		start += ` width="` + n + `" height="` + n + `"`
+	}
+	return start + a.cfg.rootAttrString() + ">"
+}
+
+// svgBody renders the layers and the end tag once.
+func (a *Avatar) svgBody() string {
+	a.bodyOnce.Do(func() {
+		var b strings.Builder
+		a.cfg.writeBody(&b, a.selected)
+		b.WriteString(`</svg>`)
+		a.body = b.String()
+	})
+	return a.body
+}
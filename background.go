@@ -0,0 +1,74 @@
+package multiavatar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// gradient describes a two-stop background gradient.
+type gradient struct {
+	from, to string
+	// angle in degrees for linear gradients: 0 runs left to right, 90 top to bottom
+	angle  float64
+	radial bool
+}
+
+// WithBackgroundGradient replaces the flat background color with a linear
+// gradient from one color to another. angle is in degrees: 0 runs left to
+// right, 90 top to bottom. Invalid colors and a NaN or infinite angle keep
+// the flat background and are reported by the error-returning APIs.
+func WithBackgroundGradient(from, to string, angle float64) Option {
+	return func(c *config) {
+		c.setGradient(&gradient{from: strings.TrimSpace(from), to: strings.TrimSpace(to), angle: angle})
+	}
+}
+
+// WithRadialBackgroundGradient replaces the flat background color with a
+// radial gradient from the center color to the edge color.
+func WithRadialBackgroundGradient(center, edge string) Option {
+	return func(c *config) {
+		c.setGradient(&gradient{from: strings.TrimSpace(center), to: strings.TrimSpace(edge), radial: true})
+	}
+}
+
+// setGradient installs g if both of its colors are valid, as for
+// WithPartColors, and its angle is finite.
+func (c *config) setGradient(g *gradient) {
+	if math.IsNaN(g.angle) || math.IsInf(g.angle, 0) {
+		c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid background gradient angle %v", g.angle))
+		return
+	}
+	for _, col := range []string{g.from, g.to} {
+		if err := checkColor(col); err != nil {
+			c.errs = append(c.errs, fmt.Errorf("%w in background gradient", err))
+			return
+		}
+	}
+	g.from, g.to = cssColor(g.from), cssColor(g.to)
+	c.bgGradient = g
+}
+
+// key describes the gradient for defID.
+func (g *gradient) key() string {
+	return fmt.Sprintf("%s %s %v %v", g.from, g.to, g.angle, g.radial)
+}
+
+// writeDef writes the gradient definition with the given id.
+func (g *gradient) writeDef(b svgWriter, id string) {
+	if g.radial {
+		b.WriteString(`<radialGradient id="` + id + `" cx="0.5" cy="0.5" r="0.5">`)
+	} else {
+		rad := g.angle * math.Pi / 180
+		dx, dy := math.Cos(rad)/2, math.Sin(rad)/2
+		b.WriteString(`<linearGradient id="` + id + `" x1="` + formatFloat(0.5-dx) + `" y1="` + formatFloat(0.5-dy) +
+			`" x2="` + formatFloat(0.5+dx) + `" y2="` + formatFloat(0.5+dy) + `">`)
+	}
+	b.WriteString(`<stop offset="0" stop-color="` + safeColor(g.from) + `"/>`)
+	b.WriteString(`<stop offset="1" stop-color="` + safeColor(g.to) + `"/>`)
+	if g.radial {
+		b.WriteString(`</radialGradient>`)
+	} else {
+		b.WriteString(`</linearGradient>`)
+	}
+}
@@ -0,0 +1,34 @@
+package multiavatar_test
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestBackgroundGradientAngle(t *testing.T) {
+	for _, angle := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		opt := multiavatar.WithBackgroundGradient("#ff0000", "#0000ff", angle)
+		if err := multiavatar.GenerateTo(io.Discard, "alice", opt); err == nil {
+			t.Errorf("GenerateTo with angle %v: got nil error", angle)
+		}
+		svg := multiavatar.Generate("alice", opt)
+		if strings.Contains(svg, "NaN") || strings.Contains(svg, "Inf") {
+			t.Errorf("Generate with angle %v writes a non-finite coordinate", angle)
+		}
+		if svg != multiavatar.Generate("alice") {
+			t.Errorf("Generate with angle %v does not keep the flat background", angle)
+		}
+	}
+
+	opt := multiavatar.WithBackgroundGradient("#ff0000", "#0000ff", 45)
+	if err := multiavatar.GenerateTo(io.Discard, "alice", opt); err != nil {
+		t.Fatalf("GenerateTo with angle 45: %v", err)
+	}
+	if svg := multiavatar.Generate("alice", opt); !strings.Contains(svg, "<linearGradient") {
+		t.Error("Generate with angle 45 has no linear gradient")
+	}
+}
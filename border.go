@@ -0,0 +1,45 @@
+package multiavatar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// border is a ring drawn along the edge of the avatar.
+type border struct {
+	color string
+	width float64
+}
+
+// WithBorder draws a ring of the given color and width (in avatar units)
+// along the edge of the avatar, inside the background shape, e.g. for
+// "live" or "story" states. It is drawn above the artwork and below any
+// badge, and also appears without a background. Invalid colors and widths
+// outside (0, 115.5] are reported as errors by the error-returning APIs.
+func WithBorder(color string, width float64) Option {
+	return func(c *config) {
+		color = strings.TrimSpace(color)
+		if err := checkColor(color); err != nil {
+			c.errs = append(c.errs, fmt.Errorf("%w for border", err))
+			return
+		}
+		if !(width > 0 && width <= canvasSize/2) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid border width %v", width))
+			return
+		}
+		c.border = &border{color: cssColor(color), width: width}
+	}
+}
+
+// render returns the ring following the background shape, with its stroke
+// kept inside the canvas.
+func (bd *border) render(filter func(string) string, shape BackgroundShape) string {
+	if shape == ShapeSquircle {
+		return `<path d="` + squirclePath(bd.width/2) + `" style="fill:none;stroke:` +
+			safeColor(filter(bd.color)) + `;stroke-width:` + formatFloat(bd.width) + `;"/>`
+	}
+	r := canvasSize/2 - bd.width/2
+	return `<circle cx="115.5" cy="115.5" r="` + formatFloat(math.Max(r, 0)) + `" style="fill:none;stroke:` +
+		safeColor(filter(bd.color)) + `;stroke-width:` + formatFloat(bd.width) + `;"/>`
+}
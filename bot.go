@@ -0,0 +1,178 @@
+package multiavatar
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// StyleBot draws a robot with a chassis, head, antennae, visor and speaker
+// grille, so bots and integrations are told apart from people at a glance.
+// Each piece is chosen from the version of the matching part (clo, head,
+// top, eyes and mouth) and takes its colors, so the same options apply:
+// WithPartVersion, WithPartColors, WithoutPart and the rest.
+const StyleBot = "bot"
+
+func init() {
+	registerComposer(StyleBot, true, writeBot)
+}
+
+// botChassis are the panels (#2) and lights (#3) drawn on the chassis, by
+// clo version.
+var botChassis = []string{
+	`<rect x="93" y="190" width="45" height="22" rx="4" style="fill:#2;"/><circle cx="102" cy="201" r="3" style="fill:#3;"/><circle cx="115.5" cy="201" r="3" style="fill:#3;"/><circle cx="129" cy="201" r="3" style="fill:#3;"/>`,
+	`<rect x="90" y="190" width="7" height="26" rx="3" style="fill:#2;"/><rect x="112" y="190" width="7" height="26" rx="3" style="fill:#2;"/><rect x="134" y="190" width="7" height="26" rx="3" style="fill:#2;"/>`,
+	`<circle cx="115.5" cy="203" r="13" style="fill:#2;"/><circle cx="115.5" cy="203" r="6" style="fill:#3;"/>`,
+	`<rect x="52" y="184" width="26" height="14" rx="5" style="fill:#2;"/><rect x="153" y="184" width="26" height="14" rx="5" style="fill:#2;"/><circle cx="115.5" cy="198" r="4" style="fill:#3;"/>`,
+}
+
+// botHeads are the head shapes, by head version.
+var botHeads = []string{
+	`<rect x="65" y="62" width="101" height="104" rx="18" style="fill:#1;"/>`,
+	`<rect x="56" y="72" width="119" height="90" rx="10" style="fill:#1;"/>`,
+	`<path d="M66,158V112A49.5,49.5,0,0,1,165,112V158Q165,166,157,166H74Q66,166,66,158Z" style="fill:#1;"/>`,
+	`<path d="M90,64H141L166,89V140L141,166H90L65,140V89Z" style="fill:#1;"/>`,
+}
+
+// botAntennae are drawn behind the head, by top version; the first is none.
+var botAntennae = []string{
+	``,
+	`<path d="M115.5,80V38" style="fill:none;stroke:#2;stroke-width:4"/><circle cx="115.5" cy="34" r="8" style="fill:#1;"/>`,
+	`<path d="M96,80L82,36M135,80L149,36" style="fill:none;stroke:#2;stroke-width:4"/><circle cx="81" cy="33" r="7" style="fill:#1;"/><circle cx="150" cy="33" r="7" style="fill:#1;"/>`,
+	`<rect x="48" y="96" width="16" height="36" rx="5" style="fill:#1;"/><rect x="167" y="96" width="16" height="36" rx="5" style="fill:#1;"/><path d="M115.5,80V48" style="fill:none;stroke:#2;stroke-width:4"/>`,
+	`<path d="M82,80L90,44L101,72L115.5,36L130,72L141,44L149,80Z" style="fill:#1;"/>`,
+}
+
+// botVisors are the eyes, by eyes version.
+var botVisors = []string{
+	`<rect x="74" y="92" width="83" height="28" rx="14" style="fill:#1;"/><rect x="81" y="99" width="69" height="14" rx="7" style="fill:#2;"/>`,
+	`<circle cx="94" cy="105" r="14" style="fill:#1;"/><circle cx="137" cy="105" r="14" style="fill:#1;"/><circle cx="94" cy="105" r="7" style="fill:#2;"/><circle cx="137" cy="105" r="7" style="fill:#2;"/>`,
+	`<circle cx="115.5" cy="105" r="19" style="fill:#1;"/><circle cx="115.5" cy="105" r="10" style="fill:#2;"/><circle cx="119" cy="101" r="3" style="fill:#fff;"/>`,
+	`<rect x="80" y="98" width="27" height="13" rx="3" style="fill:#2;"/><rect x="124" y="98" width="27" height="13" rx="3" style="fill:#2;"/>`,
+}
+
+// botMouths are the speaker grilles, by mouth version.
+var botMouths = []string{
+	`<rect x="92" y="134" width="47" height="18" rx="4" style="fill:#1;"/><path d="M104,137V149M115.5,137V149M127,137V149" style="fill:none;stroke:#2;stroke-width:3"/>`,
+	`<path d="M95,136Q115.5,154,136,136" style="fill:none;stroke:#2;stroke-width:5;stroke-linecap:round"/>`,
+	`<circle cx="102" cy="139" r="3" style="fill:#1;"/><circle cx="115.5" cy="139" r="3" style="fill:#1;"/><circle cx="129" cy="139" r="3" style="fill:#1;"/><circle cx="108.75" cy="148" r="3" style="fill:#1;"/><circle cx="122.25" cy="148" r="3" style="fill:#1;"/>`,
+	`<rect x="96" y="140" width="39" height="7" rx="3.5" style="fill:#1;"/>`,
+}
+
+// botChassisBase is the body, following the bottom of the avatar circle.
+const botChassisBase = `<path d="M52,212V196Q52,180,68,180H163Q179,180,179,196V212A115.5,115.5,0,0,1,52,212Z" style="fill:#1;"/>`
+
+// botNeck joins the head and the chassis.
+const botNeck = `<rect x="100" y="162" width="31" height="22" style="fill:#1;"/><path d="M100,170H131M100,177H131" style="fill:none;stroke:#2;stroke-width:2"/>`
+
+// Colors of recesses such as visors and grilles, of lights when no part
+// color is vivid enough, and of the metal when the head has no color.
+const (
+	botDark  = "#1b1f2a"
+	botGlow  = "#35e0ff"
+	botMetal = "#9aa4b1"
+)
+
+// writeBot draws the robot for the selected parts.
+func writeBot(cfg *config, b svgWriter, selected []selectedPart) {
+	byName := make(map[string]selectedPart, len(selected))
+	for _, p := range selected {
+		byName[p.name] = p
+	}
+	dark := cfg.filterColor(botDark)
+	head := byName["head"]
+	metal := cfg.filterColor(botMetal)
+	if len(head.colors) > 0 {
+		metal = metalColor(head.colors[0])
+	}
+	glow := vividColor(byName["eyes"].colors, cfg.filterColor(botGlow))
+
+	if !cfg.disabledParts["env"] {
+		art := *cfg
+		art.pack = nil
+		art.writeLayer(b, byName["env"])
+	}
+	draw := func(part, svg string, colors ...string) {
+		if cfg.disabledParts[part] || svg == "" {
+			return
+		}
+		b.WriteString(cfg.fadePart(part, fillColors(svg, colors)))
+	}
+	clo := byName["clo"]
+	body := partColor(clo, 0, metal)
+	draw("clo", botChassisBase+pick(botChassis, clo.version), body, shade(body, 0.3), glow)
+	draw("head", botNeck, shade(metal, 0.25), dark)
+	top := byName["top"]
+	draw("top", pick(botAntennae, top.version), partColor(top, 0, glow), shade(metal, 0.25))
+	draw("head", pick(botHeads, head.version), metal)
+	draw("eyes", pick(botVisors, byName["eyes"].version), dark, glow)
+	mouth := byName["mouth"]
+	draw("mouth", pick(botMouths, mouth.version), dark, vividColor(mouth.colors, glow))
+}
+
+// pick returns the variant for a part version.
+func pick(variants []string, version string) string {
+	v, _ := strconv.Atoi(version)
+	return variants[v%len(variants)]
+}
+
+// partColor returns color i of p, or fallback.
+func partColor(p selectedPart, i int, fallback string) string {
+	if i < len(p.colors) && p.colors[i] != "" {
+		return colorOnly(p.colors[i])
+	}
+	return fallback
+}
+
+// colorOnly drops the style suffix of a built-in theme color.
+func colorOnly(s string) string {
+	c, _, _ := strings.Cut(s, ";")
+	return c
+}
+
+// fillColors replaces the numbered "#1;", "#2;", ... placeholders of a piece.
+func fillColors(svg string, colors []string) string {
+	for i, c := range colors {
+		svg = strings.ReplaceAll(svg, "#"+strconv.Itoa(i+1)+";", safeColor(c)+";")
+	}
+	return svg
+}
+
+// metalColor turns a skin color into a muted metal tone, neither too
+// light nor too dark to read against the background.
+func metalColor(skin string) string {
+	c, ok := parseColor(colorOnly(skin))
+	if !ok {
+		return botMetal
+	}
+	h, s, l := multiavatarcolor.ToHSL(saturate(0.3)(c))
+	return formatColor(multiavatarcolor.FromHSL(h, s, clamp(l, 0.45, 0.8)))
+}
+
+// shade darkens a color by amount (0..1).
+func shade(s string, amount float64) string {
+	c, ok := parseColor(s)
+	if !ok {
+		return s
+	}
+	mix := func(v uint8) uint8 { return uint8(float64(v) * (1 - amount)) }
+	return formatColor(color.NRGBA{mix(c.R), mix(c.G), mix(c.B), c.A})
+}
+
+// vividColor returns the most saturated of colors, for lights and glows,
+// or fallback if none is saturated enough.
+func vividColor(colors []string, fallback string) string {
+	best, bestSat := fallback, 0.25
+	for _, s := range colors {
+		c, ok := parseColor(colorOnly(s))
+		if !ok || c.A == 0 {
+			continue
+		}
+		if _, sat, l := multiavatarcolor.ToHSL(c); sat > bestSat && l > 0.2 && l < 0.85 {
+			best, bestSat = formatColor(c), sat
+		}
+	}
+	return best
+}
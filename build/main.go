@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,8 +11,9 @@ import (
 )
 
 const (
-	svgDir     = "svg"
-	outputFile = "parts.go" // Output to the current directory
+	svgDir         = "svg"
+	outputFile     = "parts.go" // Output to the current directory
+	compressedFile = "parts.svg.gz"
 )
 
 func main() {
@@ -84,38 +87,35 @@ func main() {
 		finalParts[intID]["top"] = partsMerged["top"]
 	}
 
+	data := strings.Join(partsToStrings(finalParts), "\n")
+
+	// Minimal builds (TinyGo, multiavatar_minimal) compile the art in as a
+	// string constant, avoiding the gzip decoder.
 	var sb strings.Builder
 	sb.WriteString("// Code generated by go run build/main.go; DO NOT EDIT.\n\n")
+	sb.WriteString("//go:build tinygo || multiavatar_minimal\n\n")
 	sb.WriteString("package multiavatar\n\n")
-	sb.WriteString("import (\n\t \"strings\"\n)\n")
-
-	// Generate parts data as a single large string constant for simplicity and to avoid complex types.
-	sb.WriteString("const partsData = `" +
-		strings.Join(partsToStrings(finalParts), "\n") +
-		"`\n\n")
-
-	sb.WriteString(`
-var parts [][]string
-
-func init() {
-	parts = make([][]string, 16)
-	lines := strings.Split(strings.TrimSpace(partsData), "\n")
-	for i, line := range lines {
-		id := i / 6
-		if parts[id] == nil {
-			parts[id] = make([]string, 0, 6)
-		}
-		parts[id] = append(parts[id], line)
-	}
-}
-`)
+	sb.WriteString("const partsData = `" + data + "`\n\n")
+	sb.WriteString("// loadPartsData returns the part templates, one per line.\n")
+	sb.WriteString("func loadPartsData() string { return partsData }\n")
 
 	err = os.WriteFile(outputFile, []byte(sb.String()), 0644)
 	if err != nil {
 		panic(fmt.Errorf("failed to write to %s: %w", outputFile, err))
 	}
 
-	fmt.Printf("Successfully generated %s\n", outputFile)
+	// Other builds embed the art gzip-compressed and inflate it on first use.
+	var gz bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	zw.Write([]byte(data))
+	if err := zw.Close(); err != nil {
+		panic(fmt.Errorf("failed to compress parts: %w", err))
+	}
+	if err := os.WriteFile(compressedFile, gz.Bytes(), 0644); err != nil {
+		panic(fmt.Errorf("failed to write to %s: %w", compressedFile, err))
+	}
+
+	fmt.Printf("Successfully generated %s and %s\n", outputFile, compressedFile)
 }
 
 func partsToStrings(parts [16]map[string]string) []string {
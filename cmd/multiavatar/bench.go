@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// benchPresets are the named option profiles accepted by --opts preset=NAME.
+var benchPresets = map[string][]multiavatar.Option{
+	"default": nil,
+	"custom": {
+		multiavatar.WithGender("female"),
+		multiavatar.WithTheme("B"),
+		multiavatar.WithSkinColor("#f2c280"),
+		multiavatar.WithTopColors("#333", "#ff0"),
+		multiavatar.WithBackgroundGradient("#ff2f2b", "#0079b1", 45),
+		multiavatar.WithSize(256),
+	},
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	n := fs.Int("n", 100000, "number of avatars to generate")
+	cpuProfile := fs.String("profile", "", "write a CPU profile to this file")
+	memProfile := fs.String("memprofile", "", "write a heap profile to this file")
+	optsSpec := fs.String("opts", "preset=default", "option profile: preset=default|custom, or json=<Options JSON>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *n <= 0 {
+		return fmt.Errorf("--n must be positive")
+	}
+
+	opts, err := parseBenchOpts(*optsSpec)
+	if err != nil {
+		return err
+	}
+
+	seeds := make([]string, *n)
+	for i := range seeds {
+		seeds[i] = fmt.Sprintf("bench-seed-%d", i)
+	}
+
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+	}
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var bytesOut int
+	for _, seed := range seeds {
+		bytesOut += len(multiavatar.Generate(seed, opts...))
+	}
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	if *cpuProfile != "" {
+		pprof.StopCPUProfile()
+	}
+
+	if *memProfile != "" {
+		f, err := os.Create(*memProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return err
+		}
+	}
+
+	ops := float64(*n)
+	fmt.Printf("go:        %s %s/%s, %d CPUs\n", runtime.Version(), runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
+	fmt.Printf("opts:      %s\n", *optsSpec)
+	fmt.Printf("avatars:   %d in %s\n", *n, elapsed.Round(time.Millisecond))
+	fmt.Printf("ns/op:     %.0f\n", float64(elapsed.Nanoseconds())/ops)
+	fmt.Printf("ops/s:     %.0f\n", ops/elapsed.Seconds())
+	fmt.Printf("B/op:      %.0f\n", float64(after.TotalAlloc-before.TotalAlloc)/ops)
+	fmt.Printf("allocs/op: %.1f\n", float64(after.Mallocs-before.Mallocs)/ops)
+	fmt.Printf("svg bytes: %.0f avg\n", float64(bytesOut)/ops)
+	return nil
+}
+
+// parseBenchOpts resolves the --opts flag into an option list.
+func parseBenchOpts(spec string) ([]multiavatar.Option, error) {
+	key, val, ok := strings.Cut(spec, "=")
+	if !ok {
+		return nil, fmt.Errorf("--opts: want preset=NAME or json=DOC, got %q", spec)
+	}
+	switch key {
+	case "preset":
+		opts, ok := benchPresets[val]
+		if !ok {
+			return nil, fmt.Errorf("--opts: unknown preset %q (want default or custom)", val)
+		}
+		return opts, nil
+	case "json":
+		return multiavatar.FromJSON([]byte(val))
+	default:
+		return nil, fmt.Errorf("--opts: unknown key %q", key)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// worktreeRev selects the current working tree instead of a git revision.
+const worktreeRev = "WORKTREE"
+
+// renderProgram renders seeds read from stdin with the library version it is
+// built against, printing one JSON object per line. It only uses Generate,
+// which every release provides.
+const renderProgram = `package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func main() {
+	sc := bufio.NewScanner(os.Stdin)
+	enc := json.NewEncoder(os.Stdout)
+	for sc.Scan() {
+		seed := sc.Text()
+		_ = enc.Encode(map[string]string{"seed": seed, "svg": multiavatar.Generate(seed)})
+	}
+}
+`
+
+func runCompareRelease(args []string) error {
+	fs := flag.NewFlagSet("compare-release", flag.ContinueOnError)
+	oldRev := fs.String("old", "", "git revision of the baseline release (e.g. v1.2.0)")
+	newRev := fs.String("new", "HEAD", "git revision to compare, or "+worktreeRev+" for uncommitted changes")
+	seedsPath := fs.String("seeds", "", "file with one seed per line")
+	outPath := fs.String("out", "report.html", "HTML report output file")
+	repo := fs.String("repo", ".", "path inside the multiavatar-go git repository")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *oldRev == "" || *seedsPath == "" {
+		return errors.New("--old and --seeds are required")
+	}
+
+	seeds, err := readSeeds(*seedsPath)
+	if err != nil {
+		return err
+	}
+	root, err := gitOutput(*repo, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+	root = strings.TrimSpace(root)
+
+	tmp, err := os.MkdirTemp("", "multiavatar-compare-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	oldOut, err := renderRevision(root, *oldRev, filepath.Join(tmp, "old"), seeds)
+	if err != nil {
+		return fmt.Errorf("render %s: %w", *oldRev, err)
+	}
+	newOut, err := renderRevision(root, *newRev, filepath.Join(tmp, "new"), seeds)
+	if err != nil {
+		return fmt.Errorf("render %s: %w", *newRev, err)
+	}
+
+	report := compareReport{Old: *oldRev, New: *newRev, Total: len(seeds)}
+	for _, seed := range seeds {
+		o, n := oldOut[seed], newOut[seed]
+		if o == n {
+			continue
+		}
+		report.Changed = append(report.Changed, changedSeed{Seed: seed, Old: svgDataURI(o), New: svgDataURI(n)})
+	}
+
+	f, err := os.Create(*outPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := reportTemplate.Execute(f, report); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d of %d seeds changed between %s and %s; report written to %s\n",
+		len(report.Changed), len(seeds), *oldRev, *newRev, *outPath)
+	return nil
+}
+
+// renderRevision checks out rev into dir and renders seeds with it.
+func renderRevision(root, rev, dir string, seeds []string) (map[string]string, error) {
+	libDir := root
+	if rev != worktreeRev {
+		libDir = filepath.Join(dir, "lib")
+		if err := exportRevision(root, rev, libDir); err != nil {
+			return nil, err
+		}
+	}
+
+	progDir := filepath.Join(dir, "render")
+	if err := os.MkdirAll(progDir, 0o755); err != nil {
+		return nil, err
+	}
+	gomod := "module multiavatarcompare\n\ngo 1.21\n\nrequire github.com/changzee/multiavatar-go v0.0.0\n\nreplace github.com/changzee/multiavatar-go => " + libDir + "\n"
+	if err := os.WriteFile(filepath.Join(progDir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(progDir, "main.go"), []byte(renderProgram), 0o644); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("go", "run", "-mod=mod", ".")
+	cmd.Dir = progDir
+	cmd.Stdin = strings.NewReader(strings.Join(seeds, "\n") + "\n")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, stderr.String())
+	}
+
+	out := make(map[string]string, len(seeds))
+	dec := json.NewDecoder(&stdout)
+	for {
+		var rec struct{ Seed, SVG string }
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		out[rec.Seed] = rec.SVG
+	}
+	return out, nil
+}
+
+// exportRevision extracts the tree of rev into dir using git archive.
+func exportRevision(root, rev, dir string) error {
+	cmd := exec.Command("git", "archive", "--format=tar", rev)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	tr := tar.NewReader(stdout)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			f, err := os.Create(target)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git archive %s: %v: %s", rev, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// readSeeds reads non-empty, de-duplicated lines from path.
+func readSeeds(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var seeds []string
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		s := strings.TrimSpace(sc.Text())
+		if s != "" && !seen[s] {
+			seen[s] = true
+			seeds = append(seeds, s)
+		}
+	}
+	return seeds, sc.Err()
+}
+
+func svgDataURI(svg string) template.URL {
+	if svg == "" {
+		return ""
+	}
+	return template.URL("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg)))
+}
+
+type compareReport struct {
+	Old, New string
+	Total    int
+	Changed  []changedSeed
+}
+
+type changedSeed struct {
+	Seed     string
+	Old, New template.URL
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>multiavatar: {{.Old}} → {{.New}}</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 24px; color: #222; }
+  table { border-collapse: collapse; }
+  th, td { border-bottom: 1px solid #ddd; padding: 8px 12px; text-align: left; }
+  img { width: 96px; height: 96px; }
+  .empty { color: #999; }
+</style>
+</head>
+<body>
+<h1>Visual changes: {{.Old}} → {{.New}}</h1>
+<p>{{len .Changed}} of {{.Total}} seeds render differently.</p>
+{{if .Changed}}
+<table>
+<tr><th>Seed</th><th>{{.Old}}</th><th>{{.New}}</th></tr>
+{{range .Changed}}<tr>
+<td><code>{{.Seed}}</code></td>
+<td>{{if .Old}}<img src="{{.Old}}" alt="old">{{else}}<span class="empty">(empty)</span>{{end}}</td>
+<td>{{if .New}}<img src="{{.New}}" alt="new">{{else}}<span class="empty">(empty)</span>{{end}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// compatCase is one line of a golden corpus rendered by the JavaScript
+// library, e.g. with
+//
+//	const multiavatar = require('@multiavatar/multiavatar')
+//	for (const input of inputs)
+//	  console.log(JSON.stringify({input, sansEnv: false, svg: multiavatar(input)}))
+type compatCase struct {
+	Input   string `json:"input"`
+	SansEnv bool   `json:"sansEnv"`
+	SVG     string `json:"svg"`
+}
+
+func runCompatCheck(args []string) error {
+	fs := flag.NewFlagSet("compat-check", flag.ContinueOnError)
+	goldenPath := fs.String("golden", "", "NDJSON corpus rendered by the JavaScript library (default stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if *goldenPath != "" {
+		f, err := os.Open(*goldenPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(in))
+	total, failed := 0, 0
+	for {
+		var c compatCase
+		if err := dec.Decode(&c); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("golden line %d: %w", total+1, err)
+		}
+		total++
+		opts := []multiavatar.Option{multiavatar.WithCompatV1()}
+		if c.SansEnv {
+			opts = append(opts, multiavatar.WithoutBackground())
+		}
+		if got := multiavatar.Generate(c.Input, opts...); got != c.SVG {
+			failed++
+			fmt.Fprintf(os.Stderr, "mismatch for %q (sansEnv=%v)\n", c.Input, c.SansEnv)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d of %d cases match\n", total-failed, total)
+	if failed > 0 {
+		return errors.New("output differs from the JavaScript library")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+
+	"github.com/changzee/multiavatar-go"
+	"github.com/changzee/multiavatar-go/analysis"
+)
+
+func runDistribution(args []string) error {
+	fs := flag.NewFlagSet("distribution", flag.ContinueOnError)
+	seedsPath := fs.String("seeds", "", "file with one seed per line (default: random seeds)")
+	n := fs.Int("n", 100000, "number of random seeds when --seeds is not given")
+	algo := fs.Int("algorithm", 1, "selection algorithm version")
+	users := fs.Int("users", 10000, "user count for the collision estimate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var inputs []string
+	if *seedsPath != "" {
+		var err error
+		if inputs, err = readSeeds(*seedsPath); err != nil {
+			return err
+		}
+	} else {
+		inputs = make([]string, *n)
+		for i := range inputs {
+			inputs[i] = strconv.FormatUint(rand.Uint64(), 36)
+		}
+	}
+
+	if !multiavatar.IsKnownAlgorithm(*algo) {
+		return fmt.Errorf("unknown algorithm version %d", *algo)
+	}
+
+	r := analysis.DistributionFor(inputs, multiavatar.Algorithm(*algo))
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	fmt.Fprintf(w, "inputs %d, distinct avatars %d, collisions %d\n\n", r.Inputs, r.Distinct, r.Collisions)
+	fmt.Fprintf(w, "%-6s %10s %8s %10s  %s\n", "part", "chi2(47)", "entropy", "max/min", "themes A/B/C")
+	for _, p := range analysis.Parts {
+		st := r.Parts[p]
+		fmt.Fprintf(w, "%-6s %10.1f %8.3f %10.2f  %d/%d/%d\n", p, st.ChiSquare, st.Entropy, st.MaxMinRatio,
+			st.Themes["A"], st.Themes["B"], st.Themes["C"])
+	}
+	fmt.Fprintf(w, "\neffective combinations %.3g (uniform: %.3g)\n", r.EffectiveCombinations(), 1.2230590464e10)
+	fmt.Fprintf(w, "P(collision among %d users) %.4f%%\n", *users, 100*r.CollisionProbability(*users))
+	return nil
+}
@@ -0,0 +1,67 @@
+// Command multiavatar is a command-line companion to the multiavatar-go library.
+//
+// Usage:
+//
+//	multiavatar <command> [flags]
+//
+// Commands:
+//
+//	bench    measure generation throughput and allocations
+//	migrate  capture seeds as specs before switching algorithm versions
+//	compare-release
+//	         report seeds whose rendering changed between two revisions
+//	compat-check
+//	         verify output against a corpus rendered by the JavaScript library
+//	show     print avatars in the terminal
+//	distribution
+//	         report how evenly seeds are spread over part versions
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command is a multiavatar subcommand.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "bench", usage: "measure generation throughput and allocations", run: runBench},
+	{name: "migrate", usage: "capture seeds as specs before switching algorithm versions", run: runMigrate},
+	{name: "compare-release", usage: "report seeds whose rendering changed between two revisions", run: runCompareRelease},
+	{name: "compat-check", usage: "verify output against a corpus rendered by the JavaScript library", run: runCompatCheck},
+	{name: "show", usage: "print avatars in the terminal", run: runShow},
+	{name: "distribution", usage: "report how evenly seeds are spread over part versions", run: runDistribution},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name == name {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "multiavatar %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "multiavatar: unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: multiavatar <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", c.name, c.usage)
+	}
+}
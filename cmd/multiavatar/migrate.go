@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	from := fs.Int("from", 1, "algorithm version currently in use")
+	to := fs.Int("to", 1, "algorithm version being switched to")
+	seedsPath := fs.String("seeds", "", "file with one seed per line (default stdin)")
+	outPath := fs.String("out", "", "NDJSON output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, v := range []int{*from, *to} {
+		if !multiavatar.IsKnownAlgorithm(v) {
+			return fmt.Errorf("unknown algorithm version %d", v)
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if *seedsPath != "" {
+		f, err := os.Open(*seedsPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	bw := bufio.NewWriter(out)
+	enc := json.NewEncoder(bw)
+	sc := bufio.NewScanner(in)
+	n := 0
+	for sc.Scan() {
+		seed := strings.TrimSpace(sc.Text())
+		if seed == "" {
+			continue
+		}
+		spec, err := multiavatar.MigrateSeed(seed, *from, *to)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(spec); err != nil {
+			return err
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "migrated %d seeds\n", n)
+	return nil
+}
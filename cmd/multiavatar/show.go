@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// runShow prints avatars to the terminal with 24-bit ANSI colors.
+func runShow(args []string) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	cols := fs.Int("cols", 32, "width in terminal columns")
+	transparent := fs.Bool("transparent", false, "omit the background")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: multiavatar show [--cols 32] [--transparent] <name>...")
+	}
+	var opts []multiavatar.Option
+	if *transparent {
+		opts = append(opts, multiavatar.WithoutBackground())
+	}
+	for _, name := range fs.Args() {
+		out := multiavatar.GenerateANSI(strings.TrimSpace(name), *cols, opts...)
+		if out == "" {
+			return fmt.Errorf("cannot render %q at %d columns", name, *cols)
+		}
+		if fs.NArg() > 1 {
+			fmt.Fprintln(os.Stdout, name)
+		}
+		fmt.Fprint(os.Stdout, out)
+	}
+	return nil
+}
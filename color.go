@@ -0,0 +1,64 @@
+package multiavatar
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// parseColor parses a CSS color as used in the art and in color overrides;
+// see multiavatarcolor.Parse for the syntaxes.
+func parseColor(s string) (color.NRGBA, bool) {
+	c, err := multiavatarcolor.Parse(s)
+	return c, err == nil
+}
+
+// checkColor validates a caller-supplied color. Only the syntaxes accepted
+// by parseColor are allowed, so values taken from untrusted input cannot
+// break out of the style attribute they are written into.
+func checkColor(s string) error {
+	if _, ok := parseColor(s); !ok {
+		return ErrInvalidColor{Value: s}
+	}
+	return nil
+}
+
+// cssColor returns s in a form every SVG renderer understands: translucent
+// hex colors (#rgba, #rrggbbaa) become rgba(), since CSS Color 4 hex alpha
+// is missing from older renderers such as librsvg, and hsl() colors become
+// hex or rgba() for renderers without them. Other colors are returned
+// unchanged.
+func cssColor(s string) string {
+	hexAlpha := strings.HasPrefix(s, "#") && (len(s) == 5 || len(s) == 9)
+	if !hexAlpha && !strings.HasPrefix(strings.ToLower(s), "hsl") {
+		return s
+	}
+	c, ok := parseColor(s)
+	if !ok {
+		return s
+	}
+	return formatColor(c)
+}
+
+// safeColor neutralizes a color that could end its CSS declaration or the
+// surrounding attribute, replacing it with "none". Colors checked with
+// checkColor never contain such characters; this guards colors supplied by
+// theme packs. (The built-in themes deliberately append declarations such
+// as ";opacity:0.67" and are trusted.)
+func safeColor(s string) string {
+	if strings.ContainsAny(s, "\"'<>&;{}\\\n\r") {
+		return "none"
+	}
+	return s
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,121 @@
+package multiavatar
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// colorFilter maps one resolved color to another.
+type colorFilter func(color.NRGBA) color.NRGBA
+
+// WithSaturation scales the saturation of every avatar color, including
+// background gradients and built-in badges. 0 renders in grayscale, 1 leaves
+// colors unchanged and values above 1 intensify them. Custom logo and badge
+// fragments keep their own colors. Negative values are reported as errors by
+// the error-returning APIs.
+func WithSaturation(s float64) Option {
+	return func(c *config) {
+		if s < 0 || math.IsNaN(s) || math.IsInf(s, 0) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid saturation %v", s))
+			return
+		}
+		c.colorFilters = append(c.colorFilters, saturate(s))
+	}
+}
+
+// WithGrayscale renders the avatar in shades of gray, e.g. to show a muted
+// avatar for deactivated accounts that still reflects the same identity.
+func WithGrayscale() Option { return WithSaturation(0) }
+
+// WithMonochrome recolors every part with shades and tints of baseColor's
+// hue, e.g. for brand-consistent avatars in a navbar or placeholder state.
+// Each color keeps its lightness, so the features stay distinguishable. An
+// invalid baseColor is reported as an error by the error-returning APIs.
+func WithMonochrome(baseColor string) Option {
+	return func(c *config) {
+		if err := checkColor(baseColor); err != nil {
+			c.errs = append(c.errs, err)
+			return
+		}
+		base, ok := parseColor(baseColor)
+		if !ok || base.A == 0 {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid monochrome color %q", baseColor))
+			return
+		}
+		c.colorFilters = append(c.colorFilters, monochrome(base))
+	}
+}
+
+// monochrome returns a filter that moves colors onto the hue and saturation
+// of base, using their luma as HSL lightness.
+func monochrome(base color.NRGBA) colorFilter {
+	h, s, _ := multiavatarcolor.ToHSL(base)
+	return func(c color.NRGBA) color.NRGBA {
+		l := (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+		out := multiavatarcolor.FromHSL(h, s, l)
+		out.A = c.A
+		return out
+	}
+}
+
+// saturate returns the filter computed by the CSS saturate() function and
+// the SVG feColorMatrix "saturate" type.
+func saturate(s float64) colorFilter {
+	m := [9]float64{
+		0.2126 + 0.7874*s, 0.7152 - 0.7152*s, 0.0722 - 0.0722*s,
+		0.2126 - 0.2126*s, 0.7152 + 0.2848*s, 0.0722 - 0.0722*s,
+		0.2126 - 0.2126*s, 0.7152 - 0.7152*s, 0.0722 + 0.9278*s,
+	}
+	return func(c color.NRGBA) color.NRGBA {
+		r, g, b := float64(c.R), float64(c.G), float64(c.B)
+		return color.NRGBA{
+			R: uint8(math.Round(clamp(m[0]*r+m[1]*g+m[2]*b, 0, 255))),
+			G: uint8(math.Round(clamp(m[3]*r+m[4]*g+m[5]*b, 0, 255))),
+			B: uint8(math.Round(clamp(m[6]*r+m[7]*g+m[8]*b, 0, 255))),
+			A: c.A,
+		}
+	}
+}
+
+// filterColor applies the configured color filters to a CSS color. Colors
+// that do not parse, and fully transparent ones such as "none", are
+// returned unchanged. A trailing style suffix of the built-in themes, as in
+// "#008;opacity:0.67", is kept.
+func (cfg *config) filterColor(s string) string {
+	if len(cfg.colorFilters) == 0 {
+		return s
+	}
+	suffix := ""
+	if i := strings.IndexByte(s, ';'); i >= 0 {
+		s, suffix = s[:i], s[i:]
+	}
+	c, ok := parseColor(s)
+	if !ok || c.A == 0 {
+		return s + suffix
+	}
+	for _, f := range cfg.colorFilters {
+		c = f(c)
+	}
+	return formatColor(c) + suffix
+}
+
+// filterColors is filterColor for a part's color list. The input is not modified.
+func (cfg *config) filterColors(colors []string) []string {
+	if len(cfg.colorFilters) == 0 {
+		return colors
+	}
+	out := make([]string, len(colors))
+	for i, s := range colors {
+		out[i] = cfg.filterColor(s)
+	}
+	return out
+}
+
+// formatColor formats c as #rrggbb, or as rgba() when it is translucent.
+func formatColor(c color.NRGBA) string {
+	return multiavatarcolor.Format(c)
+}
@@ -0,0 +1,162 @@
+package multiavatar
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// TotalCombinations returns the number of distinct avatars the selection
+// can produce with opts: the product, over the drawn parts, of the
+// version and theme pairs the hash can reach for each. It follows forced
+// and allowed versions and themes, WithoutPart, WithPart and the selection
+// algorithm, so a policy can be checked to still yield enough distinct
+// avatars for a user base. Colors derived from the input, e.g. by
+// WithHarmoniousColors, are not counted.
+//
+// Allowed lists are indexed by a hash value shared by the version and the
+// theme of a part, so restricting both may reach fewer pairs than the
+// product of their lengths; the count is exact either way.
+func TotalCombinations(opts ...Option) uint64 {
+	cfg := newConfig(opts)
+	total := uint64(1)
+	for _, choices := range cfg.combinations() {
+		total *= uint64(len(choices.pairs))
+	}
+	return total
+}
+
+// ForEachCombination calls fn with the spec of every avatar counted by
+// TotalCombinations, in a fixed order, until fn returns false. Specs list
+// the drawn parts only and have no seed; render one with
+//
+//	multiavatar.Generate("preview", append(opts, spec.ToOptions()...)...)
+//
+// The count grows quickly, to over 10^10 without restrictions, so
+// enumerate only restricted policies or stop early.
+func ForEachCombination(fn func(AvatarSpec) bool, opts ...Option) {
+	cfg := newConfig(opts)
+	algo := cfg.algorithm
+	if algo == 0 {
+		algo = AlgorithmV1
+	}
+	parts := cfg.combinations()
+	idx := make([]int, len(parts))
+	for {
+		spec := AvatarSpec{Algorithm: int(algo), Parts: make(map[string]PartSpec, len(parts))}
+		for i, p := range parts {
+			spec.Parts[p.name] = p.pairs[idx[i]]
+		}
+		if !fn(spec) {
+			return
+		}
+		// advance the last part first, like an odometer
+		i := len(parts) - 1
+		for ; i >= 0; i-- {
+			if idx[i]++; idx[i] < len(parts[i].pairs) {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
+
+// partCombinations are the version and theme pairs one part can take.
+type partCombinations struct {
+	name  string
+	pairs []PartSpec
+}
+
+// combinations returns the reachable pairs of every drawn part, in drawing
+// order, each sorted by version and theme. Every hash value that indexes
+// the allowed lists differently is replayed through resolveChoice.
+func (cfg *config) combinations() []partCombinations {
+	var out []partCombinations
+	background := !cfg.withoutBackground && cfg.bgShape != ShapeNone
+	for _, name := range partNames {
+		if cfg.disabledParts[name] || name == "env" && !background {
+			continue
+		}
+		seen := make(map[PartSpec]bool)
+		period := cfg.allowedPeriod(name)
+		for _, jitter := range cfg.jitterChoices() {
+			if cfg.algorithm == AlgorithmV2 {
+				// the slot and the value are independent digest bytes
+				for nr := range 48 {
+					for val := range min(period, 1<<16) {
+						partV, theme := slotPart(nr)
+						seen[cfg.resolveChoice(name, partV, cmp.Or(jitter, theme), val)] = true
+					}
+				}
+				continue
+			}
+			// AlgorithmV1 scales the two digits that also index the lists
+			for val := range 100 {
+				partV, theme := slotPart(scaleSlot(val))
+				seen[cfg.resolveChoice(name, partV, cmp.Or(jitter, theme), val)] = true
+			}
+		}
+		out = append(out, partCombinations{name: name, pairs: sortedPairs(seen)})
+	}
+	for _, x := range extraParts {
+		if !cfg.extraParts[x.name] || cfg.disabledParts[x.name] {
+			continue
+		}
+		seen := make(map[PartSpec]bool)
+		n := len(x.versions)
+		period := lcm(3*n, cfg.allowedPeriod(x.name))
+		for _, jitter := range cfg.jitterChoices() {
+			for val := range min(period, 1<<16) {
+				partV, theme := fmt.Sprintf("%02d", val%n), string("ABC"[val/n%3])
+				seen[cfg.resolveChoice(x.name, partV, cmp.Or(jitter, theme), val)] = true
+			}
+		}
+		out = append(out, partCombinations{name: x.name, pairs: sortedPairs(seen)})
+	}
+	return out
+}
+
+// jitterChoices returns the themes WithDeterministicJitter can give a
+// part, or a single "" without jitter.
+func (cfg *config) jitterChoices() []string {
+	if cfg.jitter == "" {
+		return []string{""}
+	}
+	return []string{"A", "B", "C"}
+}
+
+// allowedPeriod returns the period of the hash value modulo the lengths of
+// the allowed lists of part that resolveChoice uses.
+func (cfg *config) allowedPeriod(part string) int {
+	period := 1
+	if _, forced := cfg.partTheme[part]; !forced && len(cfg.allowedThemes[part]) > 0 {
+		period = lcm(period, len(cfg.allowedThemes[part]))
+	}
+	if forced, ok := cfg.forcePartV[part]; !(ok && len(forced) == 2) && len(cfg.allowedVersions[part]) > 0 {
+		period = lcm(period, len(cfg.allowedVersions[part]))
+	}
+	return period
+}
+
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
+
+// sortedPairs returns the keys of seen sorted by version, then theme.
+func sortedPairs(seen map[PartSpec]bool) []PartSpec {
+	pairs := make([]PartSpec, 0, len(seen))
+	for p := range seen {
+		pairs = append(pairs, p)
+	}
+	slices.SortFunc(pairs, func(a, b PartSpec) int {
+		return cmp.Or(cmp.Compare(a.Version, b.Version), cmp.Compare(a.Theme, b.Theme))
+	})
+	return pairs
+}
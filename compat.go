@@ -0,0 +1,22 @@
+package multiavatar
+
+// WithCompatV1 guarantees output byte-identical to the reference
+// multiavatar JavaScript library (npm @multiavatar/multiavatar 1.x) for the
+// same input, so services migrating from Node keep every existing avatar.
+//
+// It pins the original algorithm and markup: WithoutBackground is honored
+// as the JavaScript sansEnv argument, WithHashedInput still accepts
+// digests, and every other option is ignored,
+// including ones added in later releases that would change the output by
+// default. Use the compat-check command of cmd/multiavatar to verify a
+// corpus rendered by the JavaScript library.
+func WithCompatV1() Option {
+	return func(c *config) {
+		c.compatV1 = true
+	}
+}
+
+// compatConfig strips cfg down to what WithCompatV1 honors.
+func compatConfig(cfg *config) *config {
+	return &config{withoutBackground: cfg.withoutBackground, compatV1: true, normalization: NormalizationNone, hashedInput: cfg.hashedInput}
+}
@@ -0,0 +1,56 @@
+package multiavatar_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+	"github.com/changzee/multiavatar-go/multiavatartest"
+)
+
+// compatCorpus is rendered by the reference JavaScript library with
+// testdata/compat_v1.js.
+const compatCorpus = "testdata/compat_v1.ndjson"
+
+func TestCompatV1(t *testing.T) {
+	f, err := os.Open(compatCorpus)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("missing corpus; render it with: node testdata/compat_v1.js > " + compatCorpus)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for n := 1; ; n++ {
+		var c struct {
+			Input   string `json:"input"`
+			SansEnv bool   `json:"sansEnv"`
+			SVG     string `json:"svg"`
+		}
+		if err := dec.Decode(&c); err == io.EOF {
+			if n == 1 {
+				t.Fatal("empty corpus")
+			}
+			break
+		} else if err != nil {
+			t.Fatalf("line %d: %v", n, err)
+		}
+		opts := []multiavatar.Option{multiavatar.WithCompatV1()}
+		if c.SansEnv {
+			opts = append(opts, multiavatar.WithoutBackground())
+		}
+		if got := multiavatar.Generate(c.Input, opts...); got != c.SVG {
+			diffs, _ := multiavatartest.Diff(c.SVG, got)
+			t.Errorf("line %d: Generate(%q, WithCompatV1()) with sansEnv=%v differs from the JavaScript library: %s",
+				n, c.Input, c.SansEnv, strings.Join(diffs, "; "))
+		}
+	}
+}
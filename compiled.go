@@ -0,0 +1,62 @@
+package multiavatar
+
+import "context"
+
+// CompiledOptions is an option set validated and applied once by
+// CompileOptions. It is immutable and safe for concurrent use.
+type CompiledOptions struct {
+	opts []Option
+	cfg  *config
+}
+
+// CompileOptions validates opts and applies them once, for generating many
+// avatars with the same settings:
+//
+//	compiled, err := multiavatar.CompileOptions(multiavatar.WithSize(64), multiavatar.WithoutBackground())
+//	...
+//	svg := multiavatar.Generate(name, compiled.Option())
+//
+// Passed alone, or first, the compiled option copies the ready settings
+// instead of running every option and allocating their maps again. Options
+// passed after it still apply, at the cost of applying the compiled
+// options one by one. Invalid options are reported as an error, like the
+// error-returning APIs do.
+func CompileOptions(opts ...Option) (*CompiledOptions, error) {
+	cfg := newConfig(opts)
+	if err := cfg.checkOptions(context.Background(), "CompileOptions"); err != nil {
+		return nil, err
+	}
+	return &CompiledOptions{opts: append([]Option(nil), opts...), cfg: cfg}, nil
+}
+
+// Option returns the compiled options as an Option for Generate and the
+// other APIs.
+func (co *CompiledOptions) Option() Option {
+	return func(c *config) {
+		if c.applied == 0 {
+			// c is new: share the compiled settings until another option
+			// needs to change them; see applyOptions
+			*c = *co.cfg
+			c.compiled = co
+			return
+		}
+		for _, opt := range co.opts {
+			opt(c)
+		}
+	}
+}
+
+// applyOptions applies opts to a new config.
+func applyOptions(opts []Option) *config {
+	cfg := &config{}
+	for _, opt := range opts {
+		for cfg.compiled != nil {
+			// opt may change the maps cfg shares with the compiled
+			// options: apply them to a config of its own instead
+			cfg = applyOptions(cfg.compiled.opts)
+		}
+		opt(cfg)
+		cfg.applied++
+	}
+	return cfg
+}
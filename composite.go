@@ -0,0 +1,144 @@
+package multiavatar
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GenerateComposite creates an SVG avatar from several fields of a record
+// instead of a single input, with chosen fields driving chosen aspects of
+// it: WithStructureField names the field whose hash picks the versions of
+// some parts, WithColorField the field whose hash picks their themes and
+// harmonious hues. The avatar then keeps those aspects while the other
+// fields change, e.g. its face when a user changes their email, or its
+// palette when they rename themselves:
+//
+//	svg := multiavatar.GenerateComposite(
+//		map[string]string{"email": user.Email, "username": user.Name},
+//		multiavatar.WithStructureField("username"),
+//		multiavatar.WithColorField("email"),
+//		multiavatar.WithHarmoniousColors(),
+//	)
+//
+// Each version draws its own colors for a theme, so without
+// WithHarmoniousColors a new version keeps the theme, not the exact
+// colors, of the part it replaces. Aspects no field is assigned to are
+// derived from all fields together, so they change whenever any field
+// does. A field missing from fields hashes as "". With a single field, or
+// when one field drives everything, the avatar is Generate of that field's
+// value. An empty fields map gives an empty result.
+func GenerateComposite(fields map[string]string, opts ...Option) string {
+	cfg := newConfig(opts)
+	if len(fields) == 0 {
+		return ""
+	}
+	selected := cfg.selectComposite(fields)
+	buf := getBuffer(cfg.estimateSize(selected))
+	defer putBuffer(buf)
+	cfg.writeSVG(buf, selected)
+	return buf.String()
+}
+
+// WithStructureField makes the GenerateComposite field named field choose
+// the versions of parts, or of every part, including enabled optional
+// parts, when none are given. A later call for the same part replaces the
+// field. Other APIs ignore it.
+func WithStructureField(field string, parts ...string) Option {
+	return func(c *config) {
+		if len(parts) == 0 {
+			parts = slices.Clone(partNames)
+			for _, x := range extraParts {
+				parts = append(parts, x.name)
+			}
+		}
+		for _, p := range parts {
+			pn := strings.TrimSpace(p)
+			switch pn {
+			case "env", "clo", "head", "mouth", "eyes", "top", "hat", "accessory":
+				if c.structureFields == nil {
+					c.structureFields = make(map[string]string)
+				}
+				c.structureFields[pn] = field
+			default:
+				c.errs = append(c.errs, fmt.Errorf("%w for structure field %q", ErrInvalidPart{Name: p}, field))
+			}
+		}
+	}
+}
+
+// WithColorField makes the GenerateComposite field named field choose the
+// theme of every part, along with the hues derived by WithHarmoniousColors
+// and the themes mixed by WithDeterministicJitter. Other APIs ignore it.
+func WithColorField(field string) Option {
+	return func(c *config) { c.colorField = field }
+}
+
+// selectComposite is selectParts for GenerateComposite: each part takes
+// its version from the hash of its structure field and its theme from the
+// hash of the color field, each falling back to all fields combined.
+func (cfg *config) selectComposite(fields map[string]string) []selectedPart {
+	combined := compositeInput(fields)
+	source := func(field string, ok bool) string {
+		if !ok {
+			return cfg.normalizeInput(combined)
+		}
+		return cfg.normalizeInput(fields[field])
+	}
+	colorInput := source(cfg.colorField, cfg.colorField != "")
+	structureInput := func(part string) string {
+		field, ok := cfg.structureFields[part]
+		return source(field, ok)
+	}
+
+	colorSlots := cfg.hashSlots(colorInput)
+	var jittered []string
+	if cfg.jitter != "" {
+		jittered = cfg.jitterThemes(colorInput)
+	}
+	selected := make([]selectedPart, 0, len(partNames))
+	for i, name := range partNames {
+		slot := cfg.hashSlots(structureInput(name))[i]
+		partV, _ := slotPart(slot.nr)
+		_, theme := slotPart(colorSlots[i].nr)
+		selected = append(selected, cfg.resolvePart(name, partV, jitteredTheme(jittered, i, theme), slot.val))
+	}
+	if cfg.harmonious {
+		cfg.harmonize(cfg.colorSum(colorInput), selected)
+	}
+	cfg.ensureContrast(selected)
+
+	colorSum := cfg.inputSum(colorInput)
+	for i, x := range extraParts {
+		if !cfg.extraParts[x.name] {
+			continue
+		}
+		val, partV, _ := x.hashPart(cfg.inputSum(structureInput(x.name)), i)
+		_, _, theme := x.hashPart(colorSum, i)
+		selected = append(selected, cfg.resolvePart(x.name, partV, jitteredTheme(jittered, len(partNames)+i, theme), val))
+	}
+	return selected
+}
+
+// compositeInput combines fields into one input: the value of a single
+// field, or every "key=value" pair in key order, NUL-separated.
+func compositeInput(fields map[string]string) string {
+	if len(fields) == 1 {
+		for _, v := range fields {
+			return v
+		}
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		b.WriteString(k + "=" + fields[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package multiavatar
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// WithMinContrast keeps the background distinguishable from the clothes
+// and hair: when the WCAG contrast ratio between the env color and the
+// main color of either falls below ratio, the env takes another of its
+// theme colors that meets it, or else is lightened or darkened until it
+// does. Ratios run from 1 to 21; 1.5 keeps parts from blending into the
+// background, 3 is the WCAG minimum for graphics. When no background
+// meets ratio against both parts, the one with the highest contrast is
+// used. Backgrounds set with WithEnvColor, WithBackgroundGradient or
+// WithBackgroundPattern are left as they are. Ratios outside [1, 21] are
+// reported as errors by the error-returning APIs.
+func WithMinContrast(ratio float64) Option {
+	return func(c *config) {
+		if !(ratio >= 1 && ratio <= 21) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: minimum contrast %v out of range [1, 21]", ratio))
+			return
+		}
+		c.minContrast = ratio
+	}
+}
+
+// ensureContrast changes the env color of selected to meet cfg.minContrast.
+func (cfg *config) ensureContrast(selected []selectedPart) {
+	if cfg.minContrast <= 1 || cfg.withoutBackground || cfg.bgGradient != nil || cfg.bgPattern != nil ||
+		len(cfg.overrideColors["env"]) > 0 {
+		return
+	}
+	var env *selectedPart
+	var fg []color.NRGBA
+	for i := range selected {
+		p := &selected[i]
+		switch {
+		case p.name == "env":
+			env = p
+		case cfg.disabledParts[p.name]:
+		case p.name == "clo" || p.name == "top":
+			if c, ok := mainColor(p.colors); ok {
+				fg = append(fg, c)
+			}
+		}
+	}
+	if env == nil || len(env.colors) == 0 || len(fg) == 0 {
+		return
+	}
+	base, suffix, ok := splitColor(env.colors[0])
+	if !ok {
+		return
+	}
+	score := func(c color.NRGBA) float64 {
+		worst := 21.0
+		for _, f := range fg {
+			worst = min(worst, multiavatarcolor.ContrastRatio(c, f))
+		}
+		return worst
+	}
+	if score(base) >= cfg.minContrast {
+		return
+	}
+
+	// another theme color of the env version
+	for _, theme := range []string{"A", "B", "C"} {
+		if theme == env.theme {
+			continue
+		}
+		colors := cfg.filterColors(cfg.partColors("env", env.version, theme))
+		if len(colors) == 0 {
+			continue
+		}
+		if c, _, ok := splitColor(colors[0]); ok && score(c) >= cfg.minContrast {
+			env.theme, env.colors = theme, colors
+			return
+		}
+	}
+
+	// the smallest change in lightness that meets the ratio, else the best
+	best, bestScore := base, score(base)
+	for step := 0.02; step <= 1; step += 0.02 {
+		for _, c := range []color.NRGBA{multiavatarcolor.Lighten(base, step), multiavatarcolor.Darken(base, step)} {
+			if s := score(c); s > bestScore {
+				best, bestScore = c, s
+			}
+		}
+		if bestScore >= cfg.minContrast {
+			break
+		}
+	}
+	colors := append([]string(nil), env.colors...)
+	colors[0] = formatColor(best) + suffix
+	env.colors = colors
+}
+
+// mainColor returns the first visible color of a part.
+func mainColor(colors []string) (color.NRGBA, bool) {
+	for _, s := range colors {
+		if c, _, ok := splitColor(s); ok && c.A > 0 {
+			return c, true
+		}
+	}
+	return color.NRGBA{}, false
+}
+
+// splitColor parses a theme color, separating the style suffix some
+// built-in themes append, as in "#008;opacity:0.67".
+func splitColor(s string) (color.NRGBA, string, bool) {
+	suffix := ""
+	if i := strings.IndexByte(s, ';'); i >= 0 {
+		s, suffix = s[:i], s[i:]
+	}
+	c, ok := parseColor(s)
+	return c, suffix, ok
+}
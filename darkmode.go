@@ -0,0 +1,73 @@
+package multiavatar
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// WithDarkModeColors sets the colors a part takes when the viewer prefers a
+// dark color scheme, e.g. WithDarkModeColors("env", "#1e1e2e") for a
+// darker background. The SVG carries a <style> block with a
+// @media (prefers-color-scheme: dark) rule, so one file adapts to the
+// viewer's theme. Colors are checked as for WithPartColors; invalid colors
+// drop the override and are reported by the error-returning APIs,
+// as are unknown parts.
+// Rasterized output uses the light colors.
+func WithDarkModeColors(part string, colors ...string) Option {
+	return func(c *config) {
+		pn := strings.TrimSpace(part)
+		if !c.checkPartName(pn) {
+			return
+		}
+		cp := make([]string, len(colors))
+		for i := range colors {
+			cp[i] = strings.TrimSpace(colors[i])
+			if err := checkColor(cp[i]); err != nil {
+				c.errs = append(c.errs, fmt.Errorf("%w for dark mode part %q", err, pn))
+				return
+			}
+			cp[i] = cssColor(cp[i])
+		}
+		if c.darkColors == nil {
+			c.darkColors = make(map[string][]string)
+		}
+		c.darkColors[pn] = cp
+	}
+}
+
+// darkVar returns the name of the custom property holding dark color i of part.
+func darkVar(part string, i int) string {
+	return "--multiavatar-" + part + "-" + strconv.Itoa(i)
+}
+
+// themedColor returns color i of part, switchable to its dark mode color.
+// color may carry a ";opacity:..." suffix like the built-in theme colors.
+func (cfg *config) themedColor(part string, i int, color string) string {
+	if i >= len(cfg.darkColors[part]) {
+		return color
+	}
+	suffix := ""
+	if j := strings.IndexByte(color, ';'); j >= 0 {
+		color, suffix = color[:j], color[j:]
+	}
+	return "var(" + darkVar(part, i) + "," + color + ")" + suffix
+}
+
+// writeDarkModeStyle writes the dark mode rule and returns the class that
+// scopes it. The class is derived from the rule, so avatars inlined in the
+// same page do not override each other's colors.
+func (cfg *config) writeDarkModeStyle(b svgWriter) string {
+	var decls strings.Builder
+	for _, part := range sortedKeys(cfg.darkColors) {
+		for i, c := range cfg.darkColors[part] {
+			decls.WriteString(darkVar(part, i) + ":" + safeColor(cfg.filterColor(c)) + ";")
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(decls.String()))
+	class := fmt.Sprintf("multiavatar-dark-%08x", h.Sum32())
+	b.WriteString(`<style>@media (prefers-color-scheme: dark){.` + class + `{` + decls.String() + `}}</style>`)
+	return class
+}
@@ -0,0 +1,111 @@
+package multiavatar
+
+import (
+	"slices"
+)
+
+// AvatarDescriptor describes the drawn parts of an avatar: the version,
+// theme and colors of each. Store it to show users what changed when they
+// edit their avatar, comparing it with Diff.
+type AvatarDescriptor struct {
+	Parts map[string]PartDescriptor `json:"parts"`
+}
+
+// PartDescriptor is the resolved version, theme and colors of one part.
+type PartDescriptor struct {
+	Version string   `json:"version"`
+	Theme   string   `json:"theme"`
+	Colors  []string `json:"colors"`
+}
+
+// PartChange describes a part that differs between two avatars.
+type PartChange struct {
+	Part string `json:"part"`
+	// Old and New are the part in the first and second avatar; Old is nil
+	// for an added part and New for a removed one.
+	Old *PartDescriptor `json:"old,omitempty"`
+	New *PartDescriptor `json:"new,omitempty"`
+	// Version, Theme and Colors report which of them differ when the part
+	// is in both avatars.
+	Version bool `json:"version,omitempty"`
+	Theme   bool `json:"theme,omitempty"`
+	Colors  bool `json:"colors,omitempty"`
+}
+
+// Describe returns the descriptor of the avatar Generate draws for input.
+// It is empty for an empty input.
+func Describe(input string, opts ...Option) AvatarDescriptor {
+	return Resolve(input, opts...).Descriptor()
+}
+
+// Descriptor returns the version, theme and colors of every drawn part;
+// parts left out with WithoutPart are not listed.
+func (a *Avatar) Descriptor() AvatarDescriptor {
+	d := AvatarDescriptor{Parts: make(map[string]PartDescriptor, len(a.selected))}
+	for _, p := range a.selected {
+		if a.cfg.disabledParts[p.name] {
+			continue
+		}
+		d.Parts[p.name] = PartDescriptor{Version: p.version, Theme: p.theme, Colors: slices.Clone(p.colors)}
+	}
+	return d
+}
+
+// Diff returns the parts that differ between a and b, in drawing order:
+// the original parts, then the optional ones. Parts missing from a are
+// reported as added, parts missing from b as removed.
+//
+//	before := multiavatar.Describe(user.ID, oldOpts...)
+//	after := multiavatar.Describe(user.ID, newOpts...)
+//	for _, c := range multiavatar.Diff(before, after) { ... }
+func Diff(a, b AvatarDescriptor) []PartChange {
+	var changes []PartChange
+	for _, name := range descriptorOrder(a, b) {
+		pa, inA := a.Parts[name]
+		pb, inB := b.Parts[name]
+		c := PartChange{Part: name}
+		switch {
+		case !inB:
+			c.Old = &pa
+		case !inA:
+			c.New = &pb
+		default:
+			c.Version = pa.Version != pb.Version
+			c.Theme = pa.Theme != pb.Theme
+			c.Colors = !slices.Equal(pa.Colors, pb.Colors)
+			if !c.Version && !c.Theme && !c.Colors {
+				continue
+			}
+			c.Old, c.New = &pa, &pb
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// descriptorOrder returns the part names of a and b in drawing order, with
+// names this package does not know sorted last.
+func descriptorOrder(a, b AvatarDescriptor) []string {
+	names := slices.Clone(partNames)
+	for _, x := range extraParts {
+		names = append(names, x.name)
+	}
+	var unknown []string
+	for _, d := range []AvatarDescriptor{a, b} {
+		for name := range d.Parts {
+			if !slices.Contains(names, name) && !slices.Contains(unknown, name) {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	slices.Sort(unknown)
+	out := names[:0]
+	for _, name := range names {
+		_, inA := a.Parts[name]
+		_, inB := b.Parts[name]
+		if inA || inB {
+			out = append(out, name)
+		}
+	}
+	return append(out, unknown...)
+}
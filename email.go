@@ -0,0 +1,29 @@
+package multiavatar
+
+import "strings"
+
+// WithEmailNormalization canonicalizes the input as an email address before
+// hashing (see NormalizeEmail), so "Alice@Example.com " and
+// "alice+news@example.com" produce the same avatar.
+func WithEmailNormalization() Option {
+	return func(c *config) {
+		c.normalizeEmail = true
+	}
+}
+
+// NormalizeEmail trims and lowercases an email address, as Gravatar does,
+// and drops plus-addressing tags from the local part: "Alice+news@Example.com"
+// becomes "alice@example.com". Strings without an "@" are only trimmed and
+// lowercased.
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at:]
+	if plus := strings.IndexByte(local, '+'); plus > 0 {
+		local = local[:plus]
+	}
+	return local + domain
+}
@@ -0,0 +1,52 @@
+package multiavatar
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The error-returning APIs report the cause of a failure with the errors
+// below, possibly wrapped or joined with others, so callers can branch on
+// it with errors.Is and errors.As:
+//
+//	var perr multiavatar.ErrInvalidPart
+//	if errors.As(err, &perr) {
+//		log.Printf("no such part %q", perr.Name)
+//	}
+
+// ErrEmptyInput is returned for an empty input string.
+var ErrEmptyInput = errors.New("multiavatar: empty input")
+
+// ErrInvalidPart reports an unknown part name.
+type ErrInvalidPart struct {
+	// Name is the part as given, e.g. "hair".
+	Name string
+}
+
+func (e ErrInvalidPart) Error() string {
+	return fmt.Sprintf("multiavatar: unknown part %q", e.Name)
+}
+
+// ErrInvalidColor reports a color that is not one of the syntaxes
+// accepted by the color options.
+type ErrInvalidColor struct {
+	// Value is the color as given.
+	Value string
+}
+
+func (e ErrInvalidColor) Error() string {
+	return fmt.Sprintf("multiavatar: invalid color %q", e.Value)
+}
+
+// ErrInvalidVersion reports a version, or version name, that the part
+// does not have.
+type ErrInvalidVersion struct {
+	// Part is the part name, e.g. "top".
+	Part string
+	// Version is the version as given, e.g. "42" or "mohawk".
+	Version string
+}
+
+func (e ErrInvalidVersion) Error() string {
+	return fmt.Sprintf("multiavatar: unknown %s version %q", e.Part, e.Version)
+}
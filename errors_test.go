@@ -0,0 +1,68 @@
+package multiavatar_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestPartOptionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     multiavatar.Option
+		part    string // want ErrInvalidPart with this name, if set
+		version string // want ErrInvalidVersion of "eyes" with this version, if set
+	}{
+		{"WithPartVersion part", multiavatar.WithPartVersion("eye", "01"), "eye", ""},
+		{"WithPartVersion version", multiavatar.WithPartVersion("eyes", "99"), "", "99"},
+		{"WithPartColors", multiavatar.WithPartColors("eye", []string{"#fff"}), "eye", ""},
+		{"WithPartTheme", multiavatar.WithPartTheme("eye", "A"), "eye", ""},
+		{"WithoutPart", multiavatar.WithoutPart("eye"), "eye", ""},
+		{"WithAllowedVersions part", multiavatar.WithAllowedVersions("eye", []string{"01"}), "eye", ""},
+		{"WithAllowedVersions version", multiavatar.WithAllowedVersions("eyes", []string{"01", "99"}), "", "99"},
+		{"WithAllowedThemes", multiavatar.WithAllowedThemes("eye", []string{"A"}), "eye", ""},
+		{"WithDarkModeColors", multiavatar.WithDarkModeColors("eye", "#000"), "eye", ""},
+		{"WithOpacity", multiavatar.WithOpacity("eye", 0.5), "eye", ""},
+		{"WithPartTransform", multiavatar.WithPartTransform("eye", 1, 0, 0, 0), "eye", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := multiavatar.GenerateTo(io.Discard, "alice", tt.opt)
+			if err == nil {
+				t.Fatal("GenerateTo: got nil error")
+			}
+			if tt.part != "" {
+				var perr multiavatar.ErrInvalidPart
+				if !errors.As(err, &perr) || perr.Name != tt.part {
+					t.Errorf("GenerateTo: got %v, want ErrInvalidPart{%q}", err, tt.part)
+				}
+			}
+			if tt.version != "" {
+				var verr multiavatar.ErrInvalidVersion
+				if !errors.As(err, &verr) || verr.Part != "eyes" || verr.Version != tt.version {
+					t.Errorf("GenerateTo: got %v, want ErrInvalidVersion{eyes, %q}", err, tt.version)
+				}
+			}
+		})
+	}
+}
+
+func TestPartOptionsValid(t *testing.T) {
+	opts := []multiavatar.Option{
+		multiavatar.WithPartVersion("eyes", "11"),
+		multiavatar.WithPartVersion("head", "16"),
+		multiavatar.WithPartColors("top", []string{"#333"}),
+		multiavatar.WithPartTheme("clo", "B"),
+		multiavatar.WithoutPart("env"),
+		multiavatar.WithAllowedVersions("mouth", []string{"03", "07"}),
+		multiavatar.WithAllowedThemes("top", []string{"A", "C"}),
+		multiavatar.WithDarkModeColors("env", "#1e1e2e"),
+		multiavatar.WithOpacity("clo", 0.5),
+		multiavatar.WithPartTransform("eyes", 1.2, 0, 4, 0),
+	}
+	if err := multiavatar.GenerateTo(io.Discard, "alice", opts...); err != nil {
+		t.Fatalf("GenerateTo: %v", err)
+	}
+}
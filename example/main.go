@@ -5,9 +5,10 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"strings"
 
-	"github.com/changzee/multiavatar-go"
+	"github.com/changzee/multiavatar-go/multiavatarhttp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
@@ -15,7 +16,19 @@ func main() {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", handleIndex)
-	mux.HandleFunc("/avatar", handleAvatar)
+	avatars := multiavatarhttp.NewHandler()
+	mux.Handle("/avatar", avatars)
+	// Gravatar-compatible: /avatar/{md5-or-sha256}?s=200&d=404
+	gravatar := multiavatarhttp.NewGravatarHandler()
+	mux.Handle("/avatar/", gravatar)
+	// NDJSON batches: POST newline-delimited names to /avatars
+	mux.Handle("POST /avatars", multiavatarhttp.NewBatchHandler())
+	mux.Handle("/openapi.yaml", multiavatarhttp.OpenAPIHandler())
+
+	reg := prometheus.NewRegistry()
+	prometheus.WrapRegistererWith(prometheus.Labels{"handler": "avatar"}, reg).MustRegister(avatars.Collector())
+	prometheus.WrapRegistererWith(prometheus.Labels{"handler": "gravatar"}, reg).MustRegister(gravatar.Collector())
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
 
 	addr := ":8080"
 	log.Printf("Multiavatar demo server listening on %s\n", addr)
@@ -30,177 +43,6 @@ func handleIndex(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, htmlIndex)
 }
 
-func handleAvatar(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
-	name := strings.TrimSpace(q.Get("name"))
-	if name == "" {
-		http.Error(w, "missing required 'name' parameter", http.StatusBadRequest)
-		return
-	}
-
-	var opts []multiavatar.Option
-
-	// transparent => WithoutBackground
-	if parseBool(q.Get("transparent")) {
-		opts = append(opts, multiavatar.WithoutBackground())
-	}
-
-	// Global theme
-	if t := strings.TrimSpace(q.Get("theme")); t != "" {
-		opts = append(opts, multiavatar.WithTheme(t))
-	}
-	// Gender preset: male/female/unisex
-	if g := strings.TrimSpace(q.Get("gender")); g != "" {
-		opts = append(opts, multiavatar.WithGender(g))
-	}
-
-	// Per-part theme: eyes:C,top:A
-	for part, val := range parseKVComma(q.Get("partTheme")) {
-		opts = append(opts, multiavatar.WithPartTheme(part, val))
-	}
-
-	// Allowed themes per part: top:A|C
-	for part, list := range parseKVList(q.Get("allowedThemes")) {
-		opts = append(opts, multiavatar.WithAllowedThemes(part, list))
-	}
-
-	// Force versions per part: eyes:11,top:07
-	for part, val := range parseKVComma(q.Get("partVersion")) {
-		opts = append(opts, multiavatar.WithPartVersion(part, val))
-	}
-
-	// Allowed versions per part: eyes:03|11,top:01|03|07
-	for part, list := range parseKVList(q.Get("allowedVersions")) {
-		opts = append(opts, multiavatar.WithAllowedVersions(part, list))
-	}
-
-	// Color overrides: env,clo,mouth,head,eyes,top with '|' separated values
-	addColorOverrides(&opts, "env", q.Get("env"))
-	addColorOverrides(&opts, "clo", q.Get("clo"))
-	addColorOverrides(&opts, "mouth", q.Get("mouth"))
-	if v := strings.TrimSpace(q.Get("head")); v != "" {
-		opts = append(opts, multiavatar.WithSkinColor(v))
-	}
-	addColorOverrides(&opts, "eyes", q.Get("eyes"))
-	addColorOverrides(&opts, "top", q.Get("top"))
-
-	// Disable parts: top|eyes|clo|mouth|head|env
-	for _, p := range splitList(q.Get("withoutPart")) {
-		switch p {
-		case "env", "clo", "head", "mouth", "eyes", "top":
-			opts = append(opts, multiavatar.WithoutPart(p))
-		}
-	}
-
-	svg := multiavatar.Generate(name, opts...)
-	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(svg))
-}
-
-// Helpers
-
-func parseBool(s string) bool {
-	switch strings.ToLower(strings.TrimSpace(s)) {
-	case "1", "true", "yes", "y", "on":
-		return true
-	default:
-		return false
-	}
-}
-
-// Parse "key:val,key2:val2" into map[key]val
-func parseKVComma(s string) map[string]string {
-	res := make(map[string]string)
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return res
-	}
-	parts := strings.Split(s, ",")
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p == "" {
-			continue
-		}
-		kv := strings.SplitN(p, ":", 2)
-		if len(kv) != 2 {
-			continue
-		}
-		key := strings.TrimSpace(kv[0])
-		val := strings.TrimSpace(kv[1])
-		if key != "" && val != "" {
-			res[key] = val
-		}
-	}
-	return res
-}
-
-// Parse "key:a|b|c,key2:x|y" into map[key][]string
-func parseKVList(s string) map[string][]string {
-	res := make(map[string][]string)
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return res
-	}
-	parts := strings.Split(s, ",")
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p == "" {
-			continue
-		}
-		kv := strings.SplitN(p, ":", 2)
-		if len(kv) != 2 {
-			continue
-		}
-		key := strings.TrimSpace(kv[0])
-		raw := strings.TrimSpace(kv[1])
-		list := splitList(raw)
-		if key != "" && len(list) > 0 {
-			res[key] = list
-		}
-	}
-	return res
-}
-
-func splitList(s string) []string {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return nil
-	}
-	items := strings.Split(s, "|")
-	var out []string
-	for _, it := range items {
-		it = strings.TrimSpace(it)
-		if it != "" {
-			out = append(out, it)
-		}
-	}
-	return out
-}
-
-func addColorOverrides(opts *[]multiavatar.Option, part string, raw string) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return
-	}
-	colors := splitList(raw)
-	if len(colors) == 0 {
-		return
-	}
-	switch part {
-	case "env":
-		*opts = append(*opts, multiavatar.WithEnvColor(colors[0]))
-	case "clo":
-		*opts = append(*opts, multiavatar.WithClothesColors(colors...))
-	case "mouth":
-		*opts = append(*opts, multiavatar.WithMouthColors(colors...))
-	case "eyes":
-		*opts = append(*opts, multiavatar.WithEyesColors(colors...))
-	case "top":
-		*opts = append(*opts, multiavatar.WithTopColors(colors...))
-	}
-}
-
 const htmlIndex = `<!doctype html>
 <html lang="zh-cn">
 <head>
@@ -0,0 +1,235 @@
+package multiavatar
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Rule names the step of the selection that made a choice in an
+// Explanation.
+type Rule string
+
+const (
+	// RuleHash is the choice derived from the input hash alone.
+	RuleHash Rule = "hash"
+	// RuleJitter is a theme derived from WithDeterministicJitter.
+	RuleJitter Rule = "jitter"
+	// RuleTheme is the theme set for every part with WithTheme.
+	RuleTheme Rule = "theme"
+	// RuleForced is a version or theme forced for the part, e.g. with
+	// WithPartVersion or WithPartTheme.
+	RuleForced Rule = "forced"
+	// RuleAllowed is an entry of an allowed list, such as one set with
+	// WithAllowedVersions, picked by the hash value modulo its length.
+	RuleAllowed Rule = "allowed"
+	// RuleOverride is colors set for the part, e.g. with WithPartColors.
+	RuleOverride Rule = "override"
+	// RuleHarmonious is colors derived by WithHarmoniousColors.
+	RuleHarmonious Rule = "harmonious"
+	// RuleContrast is a background color changed by WithMinContrast.
+	RuleContrast Rule = "contrast"
+)
+
+// Explanation reports how Explain's input selected its avatar, step by
+// step, for debugging why an option did or did not change an avatar.
+type Explanation struct {
+	// Input is the hashed input, after Unicode and email normalization.
+	Input     string `json:"input"`
+	Algorithm int    `json:"algorithm"`
+	// Digest is the hex SHA-256 digest of Input.
+	Digest string `json:"digest"`
+	// Digits are the digest digits AlgorithmV1 reads, two per part; empty
+	// for AlgorithmV2.
+	Digits string            `json:"digits,omitempty"`
+	Parts  []PartExplanation `json:"parts"`
+	// Err reports invalid options, which were ignored.
+	Err error `json:"-"`
+}
+
+// PartExplanation reports how one part was chosen.
+type PartExplanation struct {
+	Part string `json:"part"`
+	// Value is the hash value of the part, which indexes allowed lists.
+	Value int `json:"value"`
+	// Slot is the 0..47 version and theme slot of the six original parts,
+	// scaled from Value under AlgorithmV1, or -1 for optional parts.
+	Slot    int      `json:"slot"`
+	Version Choice   `json:"version"`
+	Theme   Choice   `json:"theme"`
+	Colors  []string `json:"colors"`
+	// ColorRule is RuleHash for the theme colors of the version and theme.
+	ColorRule Rule `json:"colorRule"`
+	// Disabled reports a part left out with WithoutPart.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Choice is a selected version or theme and the rule that chose it.
+type Choice struct {
+	Value string `json:"value"`
+	Rule  Rule   `json:"rule"`
+	// Detail describes the step, e.g. "value 57 % 2 = 1 of [03 11]".
+	Detail string `json:"detail"`
+}
+
+// Explain reports, step by step, how the avatar for input is selected
+// with opts: the hash digits, the scaled slot of every part, and which
+// rule chose its version, theme and colors. Invalid options are reported
+// in Err. An empty input, for which Generate returns "", gives an
+// explanation without a digest or parts.
+//
+//	fmt.Print(multiavatar.Explain("alice", multiavatar.WithAllowedVersions("eyes", []string{"03", "11"})))
+func Explain(input string, opts ...Option) Explanation {
+	cfg := newConfig(opts)
+	if input == "" {
+		return Explanation{Err: cfg.err()}
+	}
+	input = cfg.normalizeInput(input)
+	algo := cfg.algorithm
+	if algo == 0 {
+		algo = AlgorithmV1
+	}
+	sum := cfg.inputSum(input)
+	e := Explanation{Input: input, Algorithm: int(algo), Digest: hex.EncodeToString(sum[:]), Err: cfg.err()}
+	if algo == AlgorithmV1 {
+		e.Digits = stripNonDigits(e.Digest)
+		e.Digits = e.Digits[:min(len(e.Digits), 12)]
+	}
+
+	// replay the steps of selectParts to tell the rules apart; the final
+	// values come from selectParts itself
+	final := cfg.selectParts(input)
+	slots := cfg.hashSlots(input)
+	var jittered []string
+	if cfg.jitter != "" {
+		jittered = cfg.jitterThemes(input)
+	}
+	resolved := make([]selectedPart, 0, len(final))
+	for i, name := range partNames {
+		partV, theme := slotPart(slots[i].nr)
+		detail := "slot " + strconv.Itoa(slots[i].nr) + " = theme " + theme + ", version " + partV
+		if algo == AlgorithmV1 {
+			detail = "digits " + e.Digits[2*i:2*i+2] + " scaled to " + detail
+		}
+		p := cfg.explainPart(name, partV, theme, slots[i].val, detail, jittered, i)
+		p.Slot = slots[i].nr
+		e.Parts = append(e.Parts, p)
+		resolved = append(resolved, cfg.resolvePart(name, partV, jitteredTheme(jittered, i, theme), slots[i].val))
+	}
+	harmonized := slices.Clone(resolved)
+	if cfg.harmonious {
+		cfg.harmonize(cfg.colorSum(input), harmonized)
+	}
+	for i := range resolved {
+		p := &e.Parts[i]
+		switch {
+		case !slices.Equal(harmonized[i].colors, final[i].colors):
+			p.ColorRule = RuleContrast
+		case !slices.Equal(resolved[i].colors, harmonized[i].colors):
+			p.ColorRule = RuleHarmonious
+		}
+	}
+
+	for i, x := range extraParts {
+		if !cfg.extraParts[x.name] {
+			continue
+		}
+		val, partV, theme := x.hashPart(sum, i)
+		detail := "value " + strconv.Itoa(val) + " % " + strconv.Itoa(len(x.versions)) + " = version " + partV +
+			", theme " + theme
+		p := cfg.explainPart(x.name, partV, theme, val, detail, jittered, len(partNames)+i)
+		p.Slot = -1
+		e.Parts = append(e.Parts, p)
+	}
+	for i := range e.Parts {
+		e.Parts[i].Colors = final[i].colors
+	}
+	return e
+}
+
+// jitteredTheme returns the jittered theme of part i, or theme without
+// jitter.
+func jitteredTheme(jittered []string, i int, theme string) string {
+	if jittered != nil {
+		return jittered[i]
+	}
+	return theme
+}
+
+// explainPart reports the rules resolvePart applies to the hash-derived
+// choice of a part; hash describes that choice. The checks mirror
+// resolvePart in order.
+func (cfg *config) explainPart(name, partV, theme string, val int, hash string, jittered []string, i int) PartExplanation {
+	p := PartExplanation{
+		Part:      name,
+		Value:     val,
+		Version:   Choice{Value: partV, Rule: RuleHash, Detail: hash},
+		Theme:     Choice{Value: theme, Rule: RuleHash, Detail: hash},
+		ColorRule: RuleHash,
+		Disabled:  cfg.disabledParts[name],
+	}
+	if jittered != nil {
+		p.Theme = Choice{Value: jittered[i], Rule: RuleJitter, Detail: "jitter discriminator " + strconv.Quote(cfg.jitter)}
+	}
+	if cfg.selectedTheme != nil {
+		p.Theme = Choice{Value: *cfg.selectedTheme, Rule: RuleTheme, Detail: "theme of every part"}
+	}
+	if pt, ok := cfg.partTheme[name]; ok {
+		p.Theme = Choice{Value: pt, Rule: RuleForced, Detail: "theme forced for " + name}
+	} else if allowed := cfg.allowedThemes[name]; len(allowed) > 0 {
+		p.Theme = allowedChoice(val, allowed)
+	}
+
+	if forced, ok := cfg.forcePartV[name]; ok && len(forced) == 2 {
+		p.Version = Choice{Value: forced, Rule: RuleForced, Detail: "version forced for " + name}
+		if len(cfg.allowedVersions[name]) > 0 {
+			p.Version.Detail += ", ignoring the allowed versions"
+		}
+	} else if allowed := cfg.allowedVersions[name]; len(allowed) > 0 {
+		p.Version = allowedChoice(val, allowed)
+	}
+
+	if len(cfg.overrideColors[name]) > 0 {
+		p.ColorRule = RuleOverride
+	}
+	return p
+}
+
+// allowedChoice returns the entry of allowed that val picks.
+func allowedChoice(val int, allowed []string) Choice {
+	i := val % len(allowed)
+	return Choice{
+		Value:  allowed[i],
+		Rule:   RuleAllowed,
+		Detail: "value " + strconv.Itoa(val) + " % " + strconv.Itoa(len(allowed)) + " = " + strconv.Itoa(i) + " of [" + strings.Join(allowed, " ") + "]",
+	}
+}
+
+// String formats the explanation as an indented report, one line per step.
+func (e Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "input %q, algorithm %d\n", e.Input, e.Algorithm)
+	fmt.Fprintf(&b, "sha256 %s\n", e.Digest)
+	if e.Digits != "" {
+		fmt.Fprintf(&b, "digits %s\n", e.Digits)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, "ignored options: %v\n", e.Err)
+	}
+	for _, p := range e.Parts {
+		fmt.Fprintf(&b, "%s: value %d", p.Part, p.Value)
+		if p.Slot >= 0 {
+			fmt.Fprintf(&b, ", slot %d", p.Slot)
+		}
+		if p.Disabled {
+			b.WriteString(", disabled")
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "  version %s (%s: %s)\n", p.Version.Value, p.Version.Rule, p.Version.Detail)
+		fmt.Fprintf(&b, "  theme %s (%s: %s)\n", p.Theme.Value, p.Theme.Rule, p.Theme.Detail)
+		fmt.Fprintf(&b, "  colors %s (%s)\n", strings.Join(p.Colors, " "), p.ColorRule)
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package multiavatar_test
+
+import (
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestExplainEmptyInput(t *testing.T) {
+	e := multiavatar.Explain("")
+	if e.Digest != "" || len(e.Parts) != 0 || e.Err != nil {
+		t.Errorf("Explain(\"\") = %+v, want an empty explanation", e)
+	}
+	if d := multiavatar.Describe(""); len(d.Parts) != 0 {
+		t.Errorf("Describe(\"\") has %d parts, want none", len(d.Parts))
+	}
+	if e := multiavatar.Explain("", multiavatar.WithAlgorithm(9)); e.Err == nil {
+		t.Error("Explain(\"\") drops the invalid option error")
+	}
+}
+
+func TestExplainParts(t *testing.T) {
+	e := multiavatar.Explain("alice")
+	if len(e.Digest) != 64 || len(e.Parts) != 6 {
+		t.Errorf("Explain(\"alice\"): got digest %q and %d parts", e.Digest, len(e.Parts))
+	}
+	d := multiavatar.Describe("alice")
+	for _, p := range e.Parts {
+		if got := d.Parts[p.Part]; got.Version != p.Version.Value || got.Theme != p.Theme.Value {
+			t.Errorf("Explain(\"alice\") part %s is %s%s, Describe gives %s%s", p.Part, p.Version.Value, p.Theme.Value, got.Version, got.Theme)
+		}
+	}
+}
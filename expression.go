@@ -0,0 +1,103 @@
+package multiavatar
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Expression is a mood conveyed through the eyes and mouth.
+type Expression int
+
+const (
+	Neutral Expression = iota
+	Happy
+	Sad
+	Angry
+	Surprised
+)
+
+// expressionPreset is a curated set of eye and mouth versions for one mood.
+type expressionPreset struct {
+	eyes, mouth []string
+	// flipped lists the mouth versions drawn upside down, e.g. smiles turned
+	// into frowns, since the art has no frowning mouths of its own.
+	flipped []string
+}
+
+var expressionPresets = map[Expression]expressionPreset{
+	Neutral:   {eyes: []string{"11", "13", "15"}, mouth: []string{"02", "09", "11"}},
+	Happy:     {eyes: []string{"01", "04", "08", "14"}, mouth: []string{"00", "03", "06", "15"}},
+	Sad:       {eyes: []string{"11", "13"}, mouth: []string{"05", "09", "12"}, flipped: []string{"05", "09", "12"}},
+	Angry:     {eyes: []string{"03", "06"}, mouth: []string{"09", "11", "12"}, flipped: []string{"09", "12"}},
+	Surprised: {eyes: []string{"11", "13"}, mouth: []string{"06", "14"}, flipped: []string{"06"}},
+}
+
+// flipMouth wraps a rendered mouth so it is drawn upside down if its
+// version is one of the preset's flipped versions.
+func (cfg *config) flipMouth(version, svg string) string {
+	for _, v := range cfg.flippedMouths {
+		if v != version {
+			continue
+		}
+		id, err := strconv.Atoi(version)
+		if err != nil || id < 0 || id >= len(mouthCenters) || svg == "" {
+			return svg
+		}
+		return `<g transform="matrix(1,0,0,-1,0,` + formatFloat(2*mouthCenters[id]) + `)">` + svg + `</g>`
+	}
+	return svg
+}
+
+// mouthCenters holds the vertical center of each mouth version, in avatar
+// units; flipped mouths are mirrored about it so they stay in place.
+var mouthCenters = [16]float64{
+	147.75, 147.75, 152.25, 153.75, 153, 153.25, 154.25, 166,
+	153.5, 150.75, 147, 162.5, 155.75, 153.25, 156, 152,
+}
+
+// WithExpression restricts the eyes and mouth to versions that convey the
+// given mood, e.g. a sad avatar on error pages. Selection stays
+// deterministic within each set. Like WithGender it replaces the allowed
+// eye and mouth versions; WithPartVersion still takes precedence.
+// Unknown expressions are ignored.
+func WithExpression(e Expression) Option {
+	return func(c *config) {
+		preset, ok := expressionPresets[e]
+		if !ok {
+			return
+		}
+		if c.allowedVersions == nil {
+			c.allowedVersions = make(map[string][]string)
+		}
+		c.allowedVersions["eyes"] = preset.eyes
+		c.allowedVersions["mouth"] = preset.mouth
+		c.flippedMouths = preset.flipped
+	}
+}
+
+// String returns the expression name, e.g. "happy".
+func (e Expression) String() string {
+	switch e {
+	case Neutral:
+		return "neutral"
+	case Happy:
+		return "happy"
+	case Sad:
+		return "sad"
+	case Angry:
+		return "angry"
+	case Surprised:
+		return "surprised"
+	}
+	return "Expression(" + strconv.Itoa(int(e)) + ")"
+}
+
+// ParseExpression returns the expression named s, ignoring case.
+func ParseExpression(s string) (Expression, bool) {
+	for e := Neutral; e <= Surprised; e++ {
+		if strings.EqualFold(s, e.String()) {
+			return e, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,214 @@
+package multiavatar
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// extraPart is an optional part drawn on top of the original six. It is
+// only selected when enabled with WithPart, from hash bytes of its own, so
+// enabling it leaves the rest of the avatar unchanged.
+type extraPart struct {
+	name string
+	// above is the part it is stacked right on top of; "" draws it above all parts
+	above string
+	// versions are indexed by version code; version 00 draws nothing
+	versions []extraVersion
+}
+
+// extraVersion is the art of one version of an extra part and its colors
+// per theme.
+type extraVersion struct {
+	name   string
+	tmpl   partTemplate
+	themes map[string][]string
+}
+
+// extraParts lists the optional parts; the position of each decides which
+// hash bytes select it.
+var extraParts = []*extraPart{
+	{
+		name:  "hat",
+		above: "top",
+		versions: []extraVersion{
+			{name: "none"},
+			{
+				name: "beanie",
+				tmpl: extraTemplate(`<path d="M48,86C48,44 78,26 115.5,26S183,44 183,86Z" style="fill:#01;"/><rect x="44" y="72" width="143" height="20" rx="10" style="fill:#02;"/><circle cx="115.5" cy="24" r="11" style="fill:#03;"/>`),
+				themes: map[string][]string{
+					"A": {"#d4363c", "#b52a30", "#fff"},
+					"B": {"#2e4a7d", "#253c66", "#f8c13a"},
+					"C": {"#4b8a4f", "#3b6f3f", "#e8e8e8"},
+				},
+			},
+			{
+				name: "cap",
+				tmpl: extraTemplate(`<path d="M52,86C52,46 80,30 115.5,30S179,46 179,86Z" style="fill:#01;"/><path d="M115.5,32V84" style="fill:none;stroke:#03;stroke-width:2"/><path d="M46,88C70,72 161,72 185,88C170,100 61,100 46,88Z" style="fill:#02;"/><circle cx="115.5" cy="31" r="5" style="fill:#02;"/>`),
+				themes: map[string][]string{
+					"A": {"#1d6fd6", "#134d96", "#0d3a73"},
+					"B": {"#e04e1b", "#222", "#a83812"},
+					"C": {"#3c3c3c", "#f2c21b", "#222"},
+				},
+			},
+			{
+				name: "crown",
+				tmpl: extraTemplate(`<path d="M74,66L70,24L93,44L115.5,14L138,44L161,24L157,66Z" style="fill:#01;"/><rect x="70" y="56" width="91" height="14" rx="3" style="fill:#02;"/><circle cx="93" cy="63" r="4" style="fill:#03;"/><circle cx="115.5" cy="63" r="4" style="fill:#03;"/><circle cx="138" cy="63" r="4" style="fill:#03;"/>`),
+				themes: map[string][]string{
+					"A": {"#f7c325", "#e0a210", "#d4363c"},
+					"B": {"#d9d9e3", "#b0b0c0", "#2e7ad1"},
+					"C": {"#f7c325", "#e0a210", "#2fa35a"},
+				},
+			},
+			{
+				name: "headband",
+				tmpl: extraTemplate(`<path d="M56,94C62,50 169,50 175,94" style="fill:none;stroke:#01;stroke-width:12;stroke-linecap:round"/><path d="M56,94C62,50 169,50 175,94" style="fill:none;stroke:#02;stroke-width:3;stroke-linecap:round"/>`),
+				themes: map[string][]string{
+					"A": {"#e23c6b", "#fff"},
+					"B": {"#18a999", "#0b6e63"},
+					"C": {"#f39c12", "#fff"},
+				},
+			},
+		},
+	},
+	{
+		name: "accessory",
+		versions: []extraVersion{
+			{name: "none"},
+			{
+				name: "earrings",
+				tmpl: extraTemplate(`<circle cx="54" cy="140" r="7" style="fill:none;stroke:#01;stroke-width:3"/><circle cx="177" cy="140" r="7" style="fill:none;stroke:#01;stroke-width:3"/><circle cx="54" cy="131" r="3" style="fill:#02;"/><circle cx="177" cy="131" r="3" style="fill:#02;"/>`),
+				themes: map[string][]string{
+					"A": {"#f7c325", "#e0a210"},
+					"B": {"#c9ccd6", "#9aa0b0"},
+					"C": {"#e23c6b", "#f7c325"},
+				},
+			},
+			{
+				name: "piercings",
+				tmpl: extraTemplate(`<circle cx="57" cy="110" r="2.5" style="fill:#01;"/><circle cx="56" cy="119" r="2.5" style="fill:#01;"/><circle cx="56" cy="128" r="2.5" style="fill:#02;"/><circle cx="175" cy="124" r="2.5" style="fill:#01;"/>`),
+				themes: map[string][]string{
+					"A": {"#c9ccd6", "#2e7ad1"},
+					"B": {"#f7c325", "#d4363c"},
+					"C": {"#222", "#c9ccd6"},
+				},
+			},
+			{
+				name: "headphones",
+				tmpl: extraTemplate(`<path d="M50,116C50,26 181,26 181,116" style="fill:none;stroke:#01;stroke-width:8;stroke-linecap:round"/><rect x="38" y="100" width="22" height="40" rx="9" style="fill:#02;"/><rect x="171" y="100" width="22" height="40" rx="9" style="fill:#02;"/><rect x="52" y="106" width="8" height="28" rx="4" style="fill:#03;"/><rect x="171" y="106" width="8" height="28" rx="4" style="fill:#03;"/>`),
+				themes: map[string][]string{
+					"A": {"#333", "#222", "#d4363c"},
+					"B": {"#f2f2f2", "#ddd", "#1d6fd6"},
+					"C": {"#e23c6b", "#b52a55", "#333"},
+				},
+			},
+		},
+	},
+}
+
+func init() {
+	for _, x := range extraParts {
+		for v, ev := range x.versions {
+			RegisterPartAlias(x.name, ev.name, fmt.Sprintf("%02d", v))
+		}
+	}
+}
+
+func extraTemplate(svg string) partTemplate {
+	return partTemplate{svg: svg, placeholders: findPlaceholders(svg)}
+}
+
+// extraPartByName returns the optional part called name, or nil.
+func extraPartByName(name string) *extraPart {
+	for _, x := range extraParts {
+		if x.name == name {
+			return x
+		}
+	}
+	return nil
+}
+
+// version returns the art of version code v, or false if it does not exist.
+func (x *extraPart) version(v string) (extraVersion, bool) {
+	if !validVersion(v) {
+		return extraVersion{}, false
+	}
+	i := int(v[0]-'0')*10 + int(v[1]-'0')
+	if i >= len(x.versions) {
+		return extraVersion{}, false
+	}
+	return x.versions[i], true
+}
+
+// WithPart adds an optional part to the avatar:
+//
+//   - "hat", drawn above the hair: "none", "beanie", "cap", "crown" or "headband"
+//   - "accessory", drawn above every other part: "none", "earrings",
+//     "piercings" or "headphones"
+//
+// The part is chosen from the input hash like the others and accepts the
+// same per-part options, e.g. WithPartVersion("hat", "03"),
+// WithNamedPart("hat", "crown") or WithoutPart("accessory"); the other
+// parts do not change. Unknown parts are reported as errors by the
+// error-returning APIs.
+func WithPart(part string) Option {
+	return func(c *config) {
+		pn := strings.TrimSpace(part)
+		if extraPartByName(pn) == nil {
+			c.errs = append(c.errs, ErrInvalidPart{Name: part})
+			return
+		}
+		if c.extraParts == nil {
+			c.extraParts = make(map[string]bool)
+		}
+		c.extraParts[pn] = true
+	}
+}
+
+// selectExtraParts picks the enabled optional parts. Each reads two hash
+// bytes from the end of the digest, which the algorithms spend last or
+// not at all.
+func (cfg *config) selectExtraParts(input string, selected []selectedPart) []selectedPart {
+	if len(cfg.extraParts) == 0 {
+		return selected
+	}
+	sum := cfg.inputSum(input)
+	var jittered []string
+	if cfg.jitter != "" {
+		jittered = cfg.jitterThemes(input)[len(partNames):]
+	}
+	for i, x := range extraParts {
+		if !cfg.extraParts[x.name] {
+			continue
+		}
+		val, partV, theme := x.hashPart(sum, i)
+		if jittered != nil {
+			theme = jittered[i]
+		}
+		selected = append(selected, cfg.resolvePart(x.name, partV, theme, val))
+	}
+	return selected
+}
+
+// hashPart returns the hash value, version and theme of the optional part
+// at index i of extraParts, read from the digest sum.
+func (x *extraPart) hashPart(sum [sha256.Size]byte, i int) (val int, partV, theme string) {
+	val = int(binary.BigEndian.Uint16(sum[len(sum)-2*(i+1):]))
+	n := len(x.versions)
+	return val, fmt.Sprintf("%02d", val%n), string("ABC"[val/n%3])
+}
+
+// writeExtraParts draws the enabled optional parts stacked on top of part.
+func (cfg *config) writeExtraParts(b svgWriter, byName map[string]selectedPart, part string) {
+	for _, x := range extraParts {
+		if x.above != part || cfg.disabledParts[x.name] {
+			continue
+		}
+		if p, ok := byName[x.name]; ok {
+			cfg.beginLayer(b, x.name)
+			b.WriteString(cfg.fadePart(p.name, cfg.transformPart(p, cfg.renderPart(p))))
+			cfg.endLayer(b)
+		}
+	}
+}
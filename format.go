@@ -0,0 +1,134 @@
+package multiavatar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"image/png"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is an output encoding for batch and file exports.
+type Format int
+
+const (
+	FormatSVG Format = iota
+	FormatPNG
+	FormatGIF
+	FormatWebP
+)
+
+// defaultRasterSize is the pixel size of raster exports when WithSize is not given.
+const defaultRasterSize = 256
+
+// String returns the format name, e.g. "png".
+func (f Format) String() string {
+	switch f {
+	case FormatSVG:
+		return "svg"
+	case FormatPNG:
+		return "png"
+	case FormatGIF:
+		return "gif"
+	case FormatWebP:
+		return "webp"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// Extension returns the file extension for f, including the dot.
+func (f Format) Extension() string {
+	return "." + f.String()
+}
+
+// ContentType returns the MIME type of f.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatPNG:
+		return "image/png"
+	case FormatGIF:
+		return "image/gif"
+	case FormatWebP:
+		return "image/webp"
+	}
+	return "image/svg+xml"
+}
+
+// Encode writes the avatar for input to w in format. PNG, GIF and WebP
+// images are WithSize pixels wide, 256 by default; WebP images are
+// lossless.
+func Encode(w io.Writer, input string, format Format, opts ...Option) error {
+	return EncodeContext(context.Background(), w, input, format, opts...)
+}
+
+// EncodeContext is like Encode but stops and returns ctx's error once ctx
+// is done.
+func EncodeContext(ctx context.Context, w io.Writer, input string, format Format, opts ...Option) error {
+	if input == "" {
+		return ErrEmptyInput
+	}
+	cfg := newConfig(opts)
+	if err := cfg.checkOptions(ctx, "Encode"); err != nil {
+		return err
+	}
+	if err := cfg.checkFormat(format); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	start := time.Now()
+	err := cfg.encode(ctx, bw, input, format, opts)
+	cfg.logRender(ctx, "Encode", input, format, cfg.size, start, err)
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// checkFormat reports whether cfg.size is too large for format.
+func (cfg *config) checkFormat(format Format) error {
+	switch format {
+	case FormatSVG:
+	case FormatPNG, FormatWebP, FormatGIF:
+		if size := cfg.size; size > maxImageSize || (format == FormatGIF && size > maxGIFSize) {
+			return fmt.Errorf("multiavatar: %v size %d too large", format, size)
+		}
+	default:
+		return fmt.Errorf("multiavatar: unsupported format %v", format)
+	}
+	return nil
+}
+
+// encode renders input in format f; raster formats use cfg.size, or
+// defaultRasterSize when it is unset.
+func (cfg *config) encode(ctx context.Context, w svgWriter, input string, f Format, opts []Option) error {
+	size := cfg.size
+	if size == 0 {
+		size = defaultRasterSize
+	}
+	switch f {
+	case FormatSVG:
+		cfg.writeSVG(w, cfg.selectParts(input))
+		return nil
+	case FormatPNG, FormatWebP:
+		var b strings.Builder
+		cfg.writeSVG(&b, cfg.selectParts(input))
+		img, err := cfg.rasterizeAvatar(ctx, b.String(), size, size)
+		if err != nil {
+			return err
+		}
+		if f == FormatWebP {
+			return encodeWebP(w, img)
+		}
+		return png.Encode(w, img)
+	case FormatGIF:
+		data, err := GenerateGIFContext(ctx, input, size, opts...)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	return fmt.Errorf("multiavatar: unsupported format %v", f)
+}
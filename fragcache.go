@@ -0,0 +1,71 @@
+package multiavatar
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// fragmentCacheSize bounds the number of recolored part fragments kept in
+// fragments. The built-in art has 288 part versions and themes; the rest
+// of the room goes to color overrides and filters.
+const fragmentCacheSize = 4096
+
+// fragmentKey identifies the template of a part fragment. Colors are
+// compared separately, so looking a fragment up does not allocate.
+type fragmentKey struct {
+	// art and pack are the art set the template comes from
+	art                  *artSet
+	pack                 *themePack
+	name, version, theme string
+}
+
+// fragment is a template with its placeholders replaced by colors.
+type fragment struct {
+	colors []string
+	svg    string
+}
+
+// fragments caches recolored part templates: millions of avatars are
+// combinations of the same few hundred fragments.
+var fragments = struct {
+	sync.RWMutex
+	m map[fragmentKey][]fragment
+	n int
+}{m: make(map[fragmentKey][]fragment)}
+
+// cachedPart returns the fragment of p, rendering and caching it on a miss.
+// ok is false for parts that are not cached: those with dark mode colors,
+// whose colors are wrapped per config.
+func (cfg *config) cachedPart(p selectedPart) (svg string, ok bool) {
+	if len(cfg.darkColors[p.name]) > 0 {
+		return "", false
+	}
+	key := fragmentKey{art: loadedArt.Load(), pack: cfg.packFor(p.name), name: p.name, version: p.version, theme: p.theme}
+	fragments.RLock()
+	for _, f := range fragments.m[key] {
+		if slices.Equal(f.colors, p.colors) {
+			fragments.RUnlock()
+			return f.svg, true
+		}
+	}
+	fragments.RUnlock()
+
+	var b strings.Builder
+	cfg.recolor(&b, p)
+	f := fragment{colors: slices.Clone(p.colors), svg: b.String()}
+	fragments.Lock()
+	if fragments.n >= fragmentCacheSize {
+		// drop about a quarter of the entries; map order is random
+		for k, fs := range fragments.m {
+			delete(fragments.m, k)
+			if fragments.n -= len(fs); fragments.n < fragmentCacheSize*3/4 {
+				break
+			}
+		}
+	}
+	fragments.m[key] = append(fragments.m[key], f)
+	fragments.n++
+	fragments.Unlock()
+	return f.svg, true
+}
@@ -0,0 +1,146 @@
+package multiavatar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Built-in frames, selected with WithFrame.
+const (
+	// FrameGold is a polished gold ring.
+	FrameGold = "gold"
+	// FrameLaurel is a laurel wreath around the lower half of the avatar.
+	FrameLaurel = "laurel"
+	// FrameFlame is a ring of flames.
+	FrameFlame = "flame"
+)
+
+var (
+	framesMu sync.RWMutex
+	frames   = map[string]Overlay{
+		FrameGold:   goldFrame(),
+		FrameLaurel: laurelFrame(),
+		FrameFlame:  flameFrame(),
+	}
+)
+
+// RegisterFrame makes a custom frame available under name, to be selected
+// with WithFrame, e.g. for the avatar frames a gamified product awards.
+// Like OverlayCustom, svgFragment is sanitized and drawn in avatar
+// coordinates (0 0 231 231) unless it is an <svg> element with its own
+// viewBox. Register frames from an init function or before the first
+// Generate call that uses them. It panics if name is empty or already
+// registered, or svgFragment is rejected by the sanitizer.
+func RegisterFrame(name, svgFragment string) {
+	if name == "" {
+		panic("multiavatar: RegisterFrame name is empty")
+	}
+	o := OverlayCustom(svgFragment)
+	if o.err != nil {
+		panic("multiavatar: RegisterFrame " + name + ": " + o.err.Error())
+	}
+
+	framesMu.Lock()
+	defer framesMu.Unlock()
+	if _, dup := frames[name]; dup {
+		panic("multiavatar: RegisterFrame called twice for " + name)
+	}
+	frames[name] = o
+}
+
+// WithFrame draws a built-in frame (FrameGold, FrameLaurel, FrameFlame) or
+// one registered with RegisterFrame around the edge of the avatar. It is
+// drawn above the artwork and the border and below any overlay and badge,
+// and also appears without a background. Unknown names are reported as
+// errors by the error-returning APIs.
+func WithFrame(name string) Option {
+	return func(c *config) {
+		framesMu.RLock()
+		o, ok := frames[strings.TrimSpace(name)]
+		framesMu.RUnlock()
+		if !ok {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown frame %q", name))
+			return
+		}
+		c.frame = &o
+	}
+}
+
+// goldFrame returns a gold ring with a dark outer edge and a highlight.
+func goldFrame() Overlay {
+	ring := func(r, width float64, placeholder string) string {
+		return `<circle cx="115.5" cy="115.5" r="` + formatFloat(r) + `" style="fill:none;stroke:` + placeholder +
+			`stroke-width:` + formatFloat(width) + `;"/>`
+	}
+	return Overlay{
+		art:    ring(108, 14, "#1;") + ring(114, 2, "#2;") + ring(102, 2, "#2;") + ring(107, 4, "#3;"),
+		colors: []string{"#d4a017", "#8a6410", "#f7dc6f"},
+	}
+}
+
+// laurelFrame returns two laurel branches rising from the bottom of the
+// avatar along its edge, with pairs of leaves along a stem.
+func laurelFrame() Overlay {
+	const r = 103
+	var b strings.Builder
+	for _, side := range []float64{-1, 1} {
+		// the stem runs from just off the bottom (90°) up to 10° above the middle
+		from, to := 90-side*8, 90-side*100
+		x0, y0 := circlePoint(r, from)
+		x1, y1 := circlePoint(r, to)
+		sweep := "0" // the right stem runs counterclockwise
+		if side < 0 {
+			sweep = "1"
+		}
+		b.WriteString(`<path d="M` + formatFloat(x0) + "," + formatFloat(y0) + "A" + formatFloat(r) + "," + formatFloat(r) +
+			",0,0," + sweep + "," + formatFloat(x1) + "," + formatFloat(y1) + `" style="fill:none;stroke:#1;stroke-width:3;stroke-linecap:round;"/>`)
+		for a := from - side*10; side*(to-a) < 0; a -= side * 14 {
+			// leaves point along the stem, away from the bottom
+			tangent := a - side*90
+			for _, off := range []float64{-8, 8} {
+				x, y := circlePoint(r+off, a)
+				tilt := tangent + side*off*3
+				placeholder := "#2;"
+				if off > 0 {
+					placeholder = "#3;"
+				}
+				b.WriteString(`<ellipse cx="` + formatFloat(x) + `" cy="` + formatFloat(y) + `" rx="10" ry="4.5" transform="rotate(` +
+					formatFloat(tilt) + " " + formatFloat(x) + " " + formatFloat(y) + `)" style="fill:` + placeholder + `"/>`)
+			}
+		}
+	}
+	return Overlay{art: b.String(), colors: []string{"#3b6d21", "#4c8c2b", "#6fb43f"}}
+}
+
+// flameFrame returns a ring with flames licking outward from it.
+func flameFrame() Overlay {
+	var b strings.Builder
+	flame := func(a, base, height, width float64, placeholder string) {
+		bx0, by0 := circlePoint(base, a-width)
+		bx1, by1 := circlePoint(base, a+width)
+		tx, ty := circlePoint(base+height, a+width/2)
+		c0x, c0y := circlePoint(base+height*0.6, a-width*1.2)
+		c1x, c1y := circlePoint(base+height*0.5, a+width*1.4)
+		b.WriteString(`<path d="M` + formatFloat(bx0) + "," + formatFloat(by0) +
+			"Q" + formatFloat(c0x) + "," + formatFloat(c0y) + "," + formatFloat(tx) + "," + formatFloat(ty) +
+			"Q" + formatFloat(c1x) + "," + formatFloat(c1y) + "," + formatFloat(bx1) + "," + formatFloat(by1) +
+			`Z" style="fill:` + placeholder + `"/>`)
+	}
+	for a := 0.0; a < 360; a += 20 {
+		flame(a, 100, 15, 7, "#1;")
+	}
+	for a := 10.0; a < 360; a += 20 {
+		flame(a, 100, 11, 5, "#2;")
+	}
+	b.WriteString(`<circle cx="115.5" cy="115.5" r="102" style="fill:none;stroke:#3;stroke-width:6;"/>`)
+	return Overlay{art: b.String(), colors: []string{"#ff5a1f", "#ffc53d", "#e0301e"}}
+}
+
+// circlePoint returns the point at angle degrees (clockwise from the
+// positive x axis) on the circle of radius r around the canvas center.
+func circlePoint(r, degrees float64) (x, y float64) {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	return canvasSize/2 + r*cos, canvasSize/2 + r*sin
+}
@@ -0,0 +1,189 @@
+package multiavatar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"math"
+	"sort"
+)
+
+// maxGIFSize bounds GIF dimensions; every frame is rasterized separately.
+const maxGIFSize = 1024
+
+const (
+	// gifFrames is the number of frames in one loop of the animation.
+	gifFrames = 20
+	// gifDelay is the delay of each frame in hundredths of a second.
+	gifDelay = 10
+	// gifBlinkFrame is the frame drawn with closed eyes.
+	gifBlinkFrame = 14
+	// gifShimmer is how far the background is lightened at the peak of the shimmer.
+	gifShimmer = 0.14
+)
+
+// blinkCenters holds the vertical center of the eye versions that are drawn
+// open, in avatar units. Closed, squinting and covered eyes do not blink.
+var blinkCenters = map[string]float64{"11": 105.25, "13": 103, "15": 104.25}
+
+// GenerateGIF renders the avatar as a looping size×size GIF: the background
+// shimmers and open eyes blink once per two-second loop. It is meant for
+// platforms that only support GIF for animated images; GIF has no partial
+// transparency, so the edge of a transparent avatar is not anti-aliased.
+func GenerateGIF(input string, size int, opts ...Option) ([]byte, error) {
+	return GenerateGIFContext(context.Background(), input, size, opts...)
+}
+
+// GenerateGIFContext is like GenerateGIF but stops rendering frames and
+// returns ctx's error once ctx is done.
+func GenerateGIFContext(ctx context.Context, input string, size int, opts ...Option) ([]byte, error) {
+	if input == "" {
+		return nil, ErrEmptyInput
+	}
+	if size <= 0 || size > maxGIFSize {
+		return nil, fmt.Errorf("multiavatar: GIF size %d out of range 1..%d", size, maxGIFSize)
+	}
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return nil, err
+	}
+	selected := cfg.selectParts(input)
+
+	anim := &gif.GIF{LoopCount: 0}
+	for i := 0; i < gifFrames; i++ {
+		frame := *cfg
+		frame.blink = i == gifBlinkFrame
+		lighten := gifShimmer * (1 - math.Cos(2*math.Pi*float64(i)/gifFrames)) / 2
+
+		parts := make([]selectedPart, len(selected))
+		copy(parts, selected)
+		for j, p := range parts {
+			if p.name == "env" {
+				parts[j].colors = lightenColors(p.colors, lighten)
+			}
+		}
+		if cfg.bgGradient != nil {
+			g := *cfg.bgGradient
+			cols := lightenColors([]string{g.from, g.to}, lighten)
+			g.from, g.to = cols[0], cols[1]
+			frame.bgGradient = &g
+		}
+
+		var b bytes.Buffer
+		frame.writeSVG(&b, parts)
+		img, err := cfg.rasterizeAvatar(ctx, b.String(), size, size)
+		if err != nil {
+			return nil, err
+		}
+		delay := gifDelay
+		if frame.blink {
+			delay = gifDelay + 2
+		}
+		anim.Image = append(anim.Image, quantize(img))
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+
+	var out bytes.Buffer
+	if err := gif.EncodeAll(&out, anim); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// blinkEyes squashes rendered eyes vertically when cfg draws a blink frame.
+func (cfg *config) blinkEyes(version, svg string) string {
+	cy, ok := blinkCenters[version]
+	if !cfg.blink || !ok || svg == "" {
+		return svg
+	}
+	return `<g transform="matrix(1,0,0,0.1,0,` + formatFloat(cy*0.9) + `)">` + svg + `</g>`
+}
+
+// lightenColors mixes every parsable color with white by amount (0..1).
+func lightenColors(colors []string, amount float64) []string {
+	out := make([]string, len(colors))
+	for i, s := range colors {
+		c, ok := parseColor(s)
+		if !ok || c.A == 0 {
+			out[i] = s
+			continue
+		}
+		mix := func(v uint8) uint8 { return uint8(math.Round(float64(v) + (255-float64(v))*amount)) }
+		out[i] = formatColor(color.NRGBA{mix(c.R), mix(c.G), mix(c.B), c.A})
+	}
+	return out
+}
+
+// quantize converts img to a paletted image with the up to 255 most common
+// colors plus a transparent entry for pixels that are less than half opaque.
+func quantize(img *image.RGBA) *image.Paletted {
+	// Count colors in 15-bit buckets so anti-aliased edges do not crowd out
+	// the flat fills of the artwork.
+	key := func(r, g, b uint8) uint16 { return uint16(r>>3)<<10 | uint16(g>>3)<<5 | uint16(b>>3) }
+	type bucket struct {
+		n       int
+		r, g, b int
+	}
+	buckets := make(map[uint16]*bucket)
+	px := img.Pix
+	for i := 0; i < len(px); i += 4 {
+		if px[i+3] < 128 {
+			continue
+		}
+		r, g, b := unpremultiply(px[i : i+4])
+		k := key(r, g, b)
+		bk := buckets[k]
+		if bk == nil {
+			bk = &bucket{}
+			buckets[k] = bk
+		}
+		bk.n++
+		bk.r += int(r)
+		bk.g += int(g)
+		bk.b += int(b)
+	}
+	list := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		list = append(list, bk)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].n > list[j].n })
+	if len(list) > 255 {
+		list = list[:255]
+	}
+
+	pal := color.Palette{color.RGBA{}}
+	for _, bk := range list {
+		pal = append(pal, color.RGBA{uint8(bk.r / bk.n), uint8(bk.g / bk.n), uint8(bk.b / bk.n), 255})
+	}
+
+	out := image.NewPaletted(img.Rect, pal)
+	opaque := pal[1:]
+	cache := make(map[uint16]uint8)
+	for i, j := 0, 0; i < len(px); i, j = i+4, j+1 {
+		if px[i+3] < 128 {
+			continue // index 0 is transparent
+		}
+		r, g, b := unpremultiply(px[i : i+4])
+		k := key(r, g, b)
+		idx, ok := cache[k]
+		if !ok {
+			idx = uint8(opaque.Index(color.RGBA{r, g, b, 255}) + 1)
+			cache[k] = idx
+		}
+		out.Pix[j] = idx
+	}
+	return out
+}
+
+// unpremultiply returns the straight RGB values of a premultiplied RGBA pixel.
+func unpremultiply(p []uint8) (r, g, b uint8) {
+	a := int(p[3])
+	if a == 255 || a == 0 {
+		return p[0], p[1], p[2]
+	}
+	return uint8(int(p[0]) * 255 / a), uint8(int(p[1]) * 255 / a), uint8(int(p[2]) * 255 / a)
+}
@@ -0,0 +1,192 @@
+package multiavatar
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GroupLayout arranges the avatars of ComposeGroup.
+type GroupLayout int
+
+const (
+	// GroupOverlap lines up to four avatars in a row of overlapping
+	// circles, the first on top, as in "seen by" lists.
+	GroupOverlap GroupLayout = iota
+	// GroupGrid splits one circle between up to four avatars: halves for
+	// two, a half and two quarters for three, quarters for four.
+	GroupGrid
+	// GroupStacked shows two avatars as offset circles, the first in
+	// front at the bottom right, as in group chat icons.
+	GroupStacked
+)
+
+const (
+	// groupGap is the transparent gap between the avatars of a group, in
+	// artwork units.
+	groupGap = 6
+	// groupOverlapStep is the horizontal distance between the circles of
+	// GroupOverlap, two thirds of a diameter.
+	groupOverlapStep = canvasSize * 2 / 3.0
+	// groupStackedScale is the size of each GroupStacked circle relative
+	// to the image.
+	groupStackedScale = 2 / 3.0
+)
+
+// groupCell is the placement of one avatar in a group image.
+type groupCell struct {
+	x, y, w, h float64
+	// circle clips the avatar to the circle inscribed in the cell
+	circle bool
+}
+
+// ComposeGroup renders the avatars of inputs as a single SVG "team
+// bubble", e.g. for a channel or group chat icon. Empty inputs are
+// skipped; inputs beyond what layout shows (four, or two for
+// GroupStacked) are left out. Gaps between the avatars are transparent,
+// so the image sits on any background. WithSize sets the height; a
+// GroupOverlap row is as many times wider as it has avatars. Without
+// non-empty inputs, or with an unknown layout, the result is empty;
+// ComposeGroupTo reports both as errors.
+func ComposeGroup(inputs []string, layout GroupLayout, opts ...Option) string {
+	cfg := newConfig(opts)
+	members := groupMembers(inputs, layout)
+	if len(members) == 0 || !layout.valid() {
+		return ""
+	}
+	var b strings.Builder
+	cfg.writeGroup(&b, members, layout)
+	return b.String()
+}
+
+// ComposeGroupTo is like ComposeGroup but streams the SVG to w and reports
+// an unknown layout, no non-empty inputs and invalid options as errors.
+func ComposeGroupTo(w io.Writer, inputs []string, layout GroupLayout, opts ...Option) error {
+	if !layout.valid() {
+		return fmt.Errorf("multiavatar: unknown group layout %d", int(layout))
+	}
+	members := groupMembers(inputs, layout)
+	if len(members) == 0 {
+		return ErrEmptyInput
+	}
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return err
+	}
+	bw := getWriter(w)
+	defer putWriter(bw)
+	cfg.writeGroup(bw, members, layout)
+	return bw.Flush()
+}
+
+// valid reports whether l is one of the layouts above.
+func (l GroupLayout) valid() bool {
+	return l >= GroupOverlap && l <= GroupStacked
+}
+
+// groupMembers returns the non-empty inputs layout shows.
+func groupMembers(inputs []string, layout GroupLayout) []string {
+	var members []string
+	for _, input := range inputs {
+		if input != "" {
+			members = append(members, input)
+		}
+	}
+	limit := 4
+	if layout == GroupStacked {
+		limit = 2
+	}
+	return members[:min(len(members), limit)]
+}
+
+// writeGroup writes the group image of members, which is not empty.
+func (cfg *config) writeGroup(b svgWriter, members []string, layout GroupLayout) {
+	cells, width, height := groupCells(layout, len(members))
+	key := fmt.Sprintf("%d\x00%s", layout, strings.Join(members, "\x00"))
+
+	w, h := formatFloat(width), formatFloat(height)
+	if cfg.size > 0 {
+		fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %s %s" width="%d" height="%d"%s>`,
+			w, h, int(float64(cfg.size)*width/height+0.5), cfg.size, cfg.rootAttrString())
+	} else {
+		b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 ` + w + " " + h + `"` + cfg.rootAttrString() + `>`)
+	}
+
+	// Each circle is cut out of the avatars below it, widened by the gap.
+	b.WriteString(`<defs>`)
+	for i, c := range cells {
+		if !c.circle {
+			continue
+		}
+		r := formatFloat(c.w / 2)
+		cx, cy := formatFloat(c.x+c.w/2), formatFloat(c.y+c.h/2)
+		b.WriteString(`<clipPath id="` + cfg.defID("group-clip-"+strconv.Itoa(i), key) + `"><circle cx="` + cx + `" cy="` + cy + `" r="` + r + `"/></clipPath>`)
+		if i > 0 {
+			b.WriteString(`<mask id="` + cfg.defID("group-mask-"+strconv.Itoa(i), key) + `" maskUnits="userSpaceOnUse" x="0" y="0" width="` + w + `" height="` + h + `">`)
+			b.WriteString(`<rect width="` + w + `" height="` + h + `" fill="#fff"/>`)
+			prev := cells[i-1]
+			b.WriteString(`<circle cx="` + formatFloat(prev.x+prev.w/2) + `" cy="` + formatFloat(prev.y+prev.h/2) + `" r="` + formatFloat(prev.w/2+groupGap) + `" fill="#000"/></mask>`)
+		}
+	}
+	if layout == GroupGrid {
+		b.WriteString(`<clipPath id="` + cfg.defID("group-clip", key) + `"><circle cx="` + formatFloat(width/2) + `" cy="` + formatFloat(height/2) + `" r="` + formatFloat(width/2) + `"/></clipPath>`)
+	}
+	b.WriteString(`</defs>`)
+
+	if layout == GroupGrid {
+		b.WriteString(`<g clip-path="url(#` + cfg.defID("group-clip", key) + `)">`)
+	}
+	// draw back to front: the first avatar is on top
+	for i := len(cells) - 1; i >= 0; i-- {
+		c := cells[i]
+		if c.circle {
+			b.WriteString(`<g clip-path="url(#` + cfg.defID("group-clip-"+strconv.Itoa(i), key) + `)"`)
+			if i > 0 {
+				b.WriteString(` mask="url(#` + cfg.defID("group-mask-"+strconv.Itoa(i), key) + `)"`)
+			}
+			b.WriteString(`>`)
+		}
+		fmt.Fprintf(b, `<svg x="%s" y="%s" width="%s" height="%s" viewBox="%s" preserveAspectRatio="xMidYMid slice">`,
+			formatFloat(c.x), formatFloat(c.y), formatFloat(c.w), formatFloat(c.h), cfg.viewBoxAttr())
+		cfg.writeBody(b, cfg.selectParts(members[i]))
+		b.WriteString(`</svg>`)
+		if c.circle {
+			b.WriteString(`</g>`)
+		}
+	}
+	if layout == GroupGrid {
+		b.WriteString(`</g>`)
+	}
+	b.WriteString(`</svg>`)
+}
+
+// groupCells returns the cells of n avatars in layout, listed front to
+// back, and the size of the image.
+func groupCells(layout GroupLayout, n int) ([]groupCell, float64, float64) {
+	const size, half, g = canvasSize, canvasSize / 2.0, groupGap / 2.0
+	switch {
+	case n == 1:
+		return []groupCell{{0, 0, size, size, true}}, size, size
+	case layout == GroupGrid:
+		left := groupCell{0, 0, half - g, size, false}
+		switch n {
+		case 2:
+			return []groupCell{left, {half + g, 0, half - g, size, false}}, size, size
+		case 3:
+			return []groupCell{left, {half + g, 0, half - g, half - g, false}, {half + g, half + g, half - g, half - g, false}}, size, size
+		}
+		return []groupCell{
+			{0, 0, half - g, half - g, false}, {half + g, 0, half - g, half - g, false},
+			{0, half + g, half - g, half - g, false}, {half + g, half + g, half - g, half - g, false},
+		}, size, size
+	case layout == GroupStacked:
+		d := size * groupStackedScale
+		return []groupCell{{size - d, size - d, d, d, true}, {0, 0, d, d, true}}, size, size
+	}
+	cells := make([]groupCell, n)
+	for i := range cells {
+		cells[i] = groupCell{float64(i) * groupOverlapStep, 0, size, size, true}
+	}
+	return cells, size + float64(n-1)*groupOverlapStep, size
+}
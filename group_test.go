@@ -0,0 +1,48 @@
+package multiavatar_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestComposeGroupLayouts(t *testing.T) {
+	inputs := []string{"alice", "bob", "carol"}
+	for _, layout := range []multiavatar.GroupLayout{multiavatar.GroupOverlap, multiavatar.GroupGrid, multiavatar.GroupStacked} {
+		svg := multiavatar.ComposeGroup(inputs, layout)
+		if !strings.HasPrefix(svg, "<svg") {
+			t.Errorf("ComposeGroup with layout %d: got %.40q", layout, svg)
+		}
+		var b strings.Builder
+		if err := multiavatar.ComposeGroupTo(&b, inputs, layout); err != nil {
+			t.Errorf("ComposeGroupTo with layout %d: %v", layout, err)
+		} else if b.String() != svg {
+			t.Errorf("ComposeGroupTo with layout %d differs from ComposeGroup", layout)
+		}
+	}
+}
+
+func TestComposeGroupUnknownLayout(t *testing.T) {
+	for _, layout := range []multiavatar.GroupLayout{-1, 99} {
+		if svg := multiavatar.ComposeGroup([]string{"alice", "bob"}, layout); svg != "" {
+			t.Errorf("ComposeGroup with layout %d: got %.40q, want empty", layout, svg)
+		}
+		if err := multiavatar.ComposeGroupTo(io.Discard, []string{"alice", "bob"}, layout); err == nil {
+			t.Errorf("ComposeGroupTo with layout %d: got nil error", layout)
+		}
+	}
+}
+
+func TestComposeGroupToErrors(t *testing.T) {
+	if err := multiavatar.ComposeGroupTo(io.Discard, []string{"", ""}, multiavatar.GroupGrid); !errors.Is(err, multiavatar.ErrEmptyInput) {
+		t.Errorf("ComposeGroupTo without inputs: got %v, want ErrEmptyInput", err)
+	}
+	err := multiavatar.ComposeGroupTo(io.Discard, []string{"alice"}, multiavatar.GroupGrid, multiavatar.WithPartColors("env", []string{"nope"}))
+	var cerr multiavatar.ErrInvalidColor
+	if !errors.As(err, &cerr) {
+		t.Errorf("ComposeGroupTo with an invalid color: got %v, want ErrInvalidColor", err)
+	}
+}
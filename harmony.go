@@ -0,0 +1,83 @@
+package multiavatar
+
+import (
+	"crypto/sha256"
+	"math"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// harmonySchemes are the hue offsets of the env, clo and top colors from
+// the base hue: complementary, triadic and split-complementary.
+var harmonySchemes = [][3]float64{
+	{0, 180, 30},
+	{0, 120, 240},
+	{0, 150, 210},
+}
+
+// WithHarmoniousColors derives the background, clothes and hair colors
+// from the input hash instead of the theme tables: a base hue and a color
+// harmony rule (complementary, triadic or split-complementary) give each
+// of the three parts its own hue. The theme colors keep their lightness, so
+// shading survives, and near-grays such as white shirts stay as they are.
+// Palettes are more varied but still deterministic. Parts with colors set
+// by WithPartColors keep them.
+func WithHarmoniousColors() Option {
+	return func(c *config) { c.harmonious = true }
+}
+
+// harmonize recolors the env, clo and top parts of selected from the color
+// digest sum; see colorSum.
+func (cfg *config) harmonize(sum [sha256.Size]byte, selected []selectedPart) {
+	base := float64(uint16(sum[24])<<8|uint16(sum[25])) * 360 / 65536
+	scheme := harmonySchemes[int(sum[26])%len(harmonySchemes)]
+	envSat := 0.45 + float64(sum[27])/255*0.35
+
+	for i, p := range selected {
+		var hue float64
+		switch p.name {
+		case "env":
+			hue = base
+		case "clo":
+			hue = base + scheme[1]
+		case "top":
+			hue = base + scheme[2]
+		default:
+			continue
+		}
+		if len(cfg.overrideColors[p.name]) > 0 {
+			continue
+		}
+		hue = math.Mod(hue, 360)
+		colors := cfg.partColors(p.name, p.version, p.theme)
+		out := make([]string, len(colors))
+		for j, s := range colors {
+			out[j] = harmonizeColor(s, hue, p.name == "env", envSat)
+		}
+		selected[i].colors = cfg.filterColors(out)
+	}
+}
+
+// harmonizeColor moves a theme color onto hue, keeping its lightness. A
+// background always takes the hue, with at least saturation sat and a
+// mid lightness; other near-gray colors are returned unchanged.
+func harmonizeColor(s string, hue float64, background bool, sat float64) string {
+	suffix := ""
+	if i := strings.IndexByte(s, ';'); i >= 0 {
+		s, suffix = s[:i], s[i:]
+	}
+	c, ok := parseColor(s)
+	if !ok || c.A == 0 {
+		return s + suffix
+	}
+	_, cs, l := multiavatarcolor.ToHSL(c)
+	if background {
+		cs, l = math.Max(cs, sat), clamp(l, 0.35, 0.65)
+	} else if cs < 0.15 {
+		return s + suffix
+	}
+	out := multiavatarcolor.FromHSL(hue, cs, l)
+	out.A = c.A
+	return formatColor(out) + suffix
+}
@@ -0,0 +1,52 @@
+package multiavatar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// WithHashedInput treats the input as the hex SHA-256 digest of the real
+// input, as returned by HashInput, and selects the parts from the digest
+// instead of hashing again. Services can then pass digests of emails and
+// other personal identifiers around instead of the plain text and still
+// get the avatar Generate gives the plain text: the six parts and their
+// colors match, as do the options deriving from the input hash, such as
+// WithHarmoniousColors, WithDeterministicJitter and WithPart. Inputs that
+// are not 64 hex digits are hashed as usual.
+func WithHashedInput() Option {
+	return func(c *config) { c.hashedInput = true }
+}
+
+// HashInput returns the hex SHA-256 digest of input, normalized as
+// Generate normalizes it with opts, for WithHashedInput.
+func HashInput(input string, opts ...Option) string {
+	sum := sha256.Sum256([]byte(newConfig(opts).normalizeInput(input)))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsSHA256Hex reports whether s is a hex SHA-256 digest, 64 hex digits in
+// either case, as accepted by WithHashedInput.
+func IsSHA256Hex(s string) bool {
+	if len(s) != 2*sha256.Size {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// inputSum returns the SHA-256 digest of input, or the digest input
+// spells out with WithHashedInput.
+func (cfg *config) inputSum(input string) [sha256.Size]byte {
+	var sum [sha256.Size]byte
+	if cfg.hashedInput && IsSHA256Hex(input) {
+		hex.Decode(sum[:], []byte(input))
+		return sum
+	}
+	return sha256.Sum256([]byte(input))
+}
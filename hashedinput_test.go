@@ -0,0 +1,36 @@
+package multiavatar_test
+
+import (
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestHashedInputParity(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []multiavatar.Option
+	}{
+		{"default", nil},
+		{"hat", []multiavatar.Option{multiavatar.WithPart("hat")}},
+		{"accessory", []multiavatar.Option{multiavatar.WithPart("accessory")}},
+		{"harmonious", []multiavatar.Option{multiavatar.WithHarmoniousColors()}},
+		{"jitter", []multiavatar.Option{multiavatar.WithDeterministicJitter("tenant-42")}},
+		{"jitter harmonious", []multiavatar.Option{
+			multiavatar.WithDeterministicJitter("tenant-42"),
+			multiavatar.WithHarmoniousColors(),
+			multiavatar.WithPart("hat"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, x := range []string{"alice", "bob@example.com", "Binx Bond"} {
+				want := multiavatar.Generate(x, tt.opts...)
+				hashed := append([]multiavatar.Option{multiavatar.WithHashedInput()}, tt.opts...)
+				if got := multiavatar.Generate(multiavatar.HashInput(x), hashed...); got != want {
+					t.Errorf("Generate(HashInput(%q), WithHashedInput()) differs from Generate(%q)", x, x)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package multiavatar
+
+import "fmt"
+
+// headShapes are head versions beyond the 16 of the original art, which
+// all share one round silhouette. Each draws the neck and shoulders of the
+// original with a different face outline, as a single path so it keeps the
+// head's single color placeholder.
+//
+// The hash never picks them, so existing avatars are unchanged. Select one
+// with WithPartVersion("head", "18") or WithNamedPart("head", "square-jaw"),
+// or spread them across users with WithAllowedVersions. Their skin color is
+// the one the hash picks for the original head, so only the silhouette
+// changes. They belong to the built-in art; theme packs leave them out.
+var headShapes = []struct {
+	name string
+	tmpl partTemplate
+}{
+	{"round", headShapeTemplate(`M51.75,115.5A63.75,63.75,0,0,1,179.25,115.5A63.75,63.75,0,0,1,51.75,115.5Z`)},
+	{"oval", headShapeTemplate(`M59.5,112A56,68,0,0,1,171.5,112A56,68,0,0,1,59.5,112Z`)},
+	{"square-jaw", headShapeTemplate(`M51.75,112A63.75,63.75,0,0,1,179.25,112V138Q179,170,148,180H83Q52,170,51.75,138Z`)},
+	{"heart", headShapeTemplate(`M51.75,110A63.75,63.75,0,0,1,179.25,110C179.25,140,150,172,115.5,182C81,172,51.75,140,51.75,110Z`)},
+}
+
+// firstHeadShape is the version code number of headShapes[0].
+const firstHeadShape = 16
+
+// headNeck is the neck and shoulders of the original head, with the neck
+// reaching up into the face so no outline leaves a gap. It winds the same
+// way as the outlines, so their overlap stays filled.
+const headNeck = `M126,160V192.468A115.5,115.5,0,0,1,179.731,211.497A115.5,115.5,0,0,1,51.271,211.497A115.5,115.5,0,0,1,105,192.47V160Z`
+
+func headShapeTemplate(face string) partTemplate {
+	svg := `<path d="` + headNeck + face + `" style="fill:#000;"/>`
+	return partTemplate{svg: svg, placeholders: findPlaceholders(svg)}
+}
+
+func init() {
+	for i, s := range headShapes {
+		RegisterPartAlias("head", s.name, fmt.Sprintf("%02d", firstHeadShape+i))
+	}
+}
+
+// headShape returns the head shape of version code v, or false if v is
+// not a head shape.
+func headShape(v string) (partTemplate, bool) {
+	if len(v) != 2 || v[0] < '0' || v[0] > '9' || v[1] < '0' || v[1] > '9' {
+		return partTemplate{}, false
+	}
+	i := int(v[0]-'0')*10 + int(v[1]-'0') - firstHeadShape
+	if i < 0 || i >= len(headShapes) {
+		return partTemplate{}, false
+	}
+	return headShapes[i].tmpl, true
+}
+
+// validPartVersion reports whether v is a version code of part: "00".."15",
+// or one of the head shapes for "head".
+func validPartVersion(part, v string) bool {
+	if validVersion(v) {
+		return true
+	}
+	_, ok := headShape(v)
+	return ok && part == "head"
+}
@@ -0,0 +1,92 @@
+package multiavatar
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// StyleIdenticon draws a GitHub-like symmetric 5×5 pixel pattern instead of
+// a character, e.g. for bots and service accounts. The pattern comes from
+// the same part selection as the default art, and its background is the
+// default avatar's background color.
+const StyleIdenticon = "identicon"
+
+func init() {
+	registerComposer(StyleIdenticon, false, writeIdenticon)
+}
+
+const (
+	identiconGrid   = 5
+	identiconCell   = 27
+	identiconOrigin = (canvasSize - identiconGrid*identiconCell) / 2
+)
+
+// writeIdenticon draws the identicon: a circle in the env color with the
+// pattern in white or near-black, whichever contrasts more. Without a
+// background the pattern itself takes the env color.
+func writeIdenticon(cfg *config, b svgWriter, selected []selectedPart) {
+	// Every part choice is one of 48 version/theme combinations; read them
+	// as the digits of a base-48 number and mix it to get the pattern bits.
+	// Optional parts, selected after the original six, are left out.
+	var n uint64
+	var env selectedPart
+	for _, p := range selected[:len(partNames)] {
+		v, _ := strconv.Atoi(p.version)
+		n = n*48 + uint64(v+16*max(strings.Index("ABC", p.theme), 0))
+		if p.name == "env" {
+			env = p
+		}
+	}
+	bits := mix64(n)
+
+	bg := "#888"
+	if len(env.colors) > 0 {
+		bg = env.colors[0]
+	}
+	fg := bg
+	switch {
+	case cfg.disabledParts["env"]:
+	case cfg.withoutBackground || cfg.bgShape == ShapeNone:
+		b.WriteString(cfg.bgPlaceholder())
+	default:
+		b.WriteString(cfg.shapeElement(bg))
+		fg = "#fff"
+		if c, ok := parseColor(bg); ok && multiavatarcolor.Luminance(c) > 0.4 {
+			fg = "#222"
+		}
+	}
+
+	cell := func(col, row int) {
+		x, y := identiconOrigin+col*identiconCell, identiconOrigin+row*identiconCell
+		b.WriteString("M" + strconv.Itoa(x) + "," + strconv.Itoa(y) + "h" + strconv.Itoa(identiconCell) +
+			"v" + strconv.Itoa(identiconCell) + "h-" + strconv.Itoa(identiconCell) + "Z")
+	}
+	b.WriteString(`<path d="`)
+	// The left three columns are drawn from the bits and mirrored to the right.
+	for col := 0; col < (identiconGrid+1)/2; col++ {
+		for row := 0; row < identiconGrid; row++ {
+			on := bits&1 == 1
+			bits >>= 1
+			if !on {
+				continue
+			}
+			cell(col, row)
+			if mirror := identiconGrid - 1 - col; mirror != col {
+				cell(mirror, row)
+			}
+		}
+	}
+	b.WriteString(`" style="fill:` + fg + `;"/>`)
+}
+
+// mix64 is the SplitMix64 finalizer, spreading every input bit over the output.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
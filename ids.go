@@ -0,0 +1,49 @@
+package multiavatar
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// WithIDPrefix names every id in the output, and the url(#...) references
+// to it, with prefix, e.g. WithIDPrefix("user42-") gives "user42-bg" for
+// the background gradient. By default ids are derived from a hash of what
+// they define, so avatars inlined in the same page never pick up each
+// other's gradients. The prefix must start with a letter or underscore and
+// contain only letters, digits, '_', '-' and '.'.
+func WithIDPrefix(prefix string) Option {
+	return func(c *config) {
+		if !validIDPrefix(prefix) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid id prefix %q", prefix))
+			return
+		}
+		c.idPrefix = prefix
+	}
+}
+
+func validIDPrefix(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case i > 0 && (c >= '0' && c <= '9' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// defID returns the id of the definition called name, whose content is
+// described by key: the id prefix followed by name, or by default name
+// with a hash of key.
+func (cfg *config) defID(name, key string) string {
+	if cfg.idPrefix != "" {
+		return cfg.idPrefix + name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return fmt.Sprintf("multiavatar-%s-%08x", name, h.Sum32())
+}
@@ -0,0 +1,59 @@
+package multiavatar
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+)
+
+// maxImageSize bounds raster output dimensions.
+const maxImageSize = 4096
+
+// GenerateImage renders the avatar for input as a size×size image, so it can
+// be composed with the standard image/draw pipeline without encoding and
+// decoding intermediate bytes. Pixels outside the avatar are transparent.
+func GenerateImage(input string, size int, opts ...Option) (image.Image, error) {
+	return GenerateImageContext(context.Background(), input, size, opts...)
+}
+
+// GenerateImageContext is like GenerateImage but stops rasterizing and
+// returns ctx's error once ctx is done, e.g. when the client of an HTTP
+// request disconnects.
+func GenerateImageContext(ctx context.Context, input string, size int, opts ...Option) (image.Image, error) {
+	if input == "" {
+		return nil, ErrEmptyInput
+	}
+	if size <= 0 || size > maxImageSize {
+		return nil, fmt.Errorf("multiavatar: image size %d out of range 1..%d", size, maxImageSize)
+	}
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	cfg.writeSVG(&b, cfg.selectParts(input))
+	return cfg.rasterizeAvatar(ctx, b.String(), size, size)
+}
+
+// rasterizeSVG renders an SVG document to a w×h image.
+func rasterizeSVG(svg string, w, h int) (*image.RGBA, error) {
+	return rasterizeSVGContext(context.Background(), svg, w, h)
+}
+
+// rasterizeSVGContext is rasterizeSVG stopping early when ctx is done.
+func rasterizeSVGContext(ctx context.Context, svg string, w, h int) (*image.RGBA, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	root, err := parseSVGTree(strings.NewReader(svg))
+	if err != nil {
+		return nil, err
+	}
+	c := newRasterCanvas(w, h, root.viewBox())
+	c.done = ctx.Done()
+	if err := drawSVGContext(ctx, root, c); err != nil {
+		return nil, err
+	}
+	return c.img, nil
+}
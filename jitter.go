@@ -0,0 +1,42 @@
+package multiavatar
+
+import "crypto/sha256"
+
+// WithDeterministicJitter mixes a secondary discriminator, such as a tenant
+// or user ID, into the color selection only: every part keeps the version
+// chosen from the input, but its theme, and so its colors, is chosen from
+// the input and discriminator together. "john" in two tenants then shares
+// the same face and hair but almost always differs in palette. With
+// WithHarmoniousColors the derived hues follow the discriminator as well.
+// Theme options still take precedence. An empty discriminator has no
+// effect.
+func WithDeterministicJitter(discriminator string) Option {
+	return func(c *config) { c.jitter = discriminator }
+}
+
+// colorSum returns the digest colors are derived from: the digest of
+// input, or that digest mixed with the jitter discriminator. It goes
+// through inputSum, so WithHashedInput gives the colors of the plain input.
+func (cfg *config) colorSum(input string) [sha256.Size]byte {
+	sum := cfg.inputSum(input)
+	if cfg.jitter == "" {
+		return sum
+	}
+	h := sha256.New()
+	h.Write(sum[:])
+	h.Write([]byte{0})
+	h.Write([]byte(cfg.jitter))
+	h.Sum(sum[:0])
+	return sum
+}
+
+// jitterThemes returns a theme per part of partNames followed by one per
+// optional part of extraParts, derived from the color seed.
+func (cfg *config) jitterThemes(input string) []string {
+	sum := cfg.colorSum(input)
+	themes := make([]string, len(partNames)+len(extraParts))
+	for i := range themes {
+		themes[i] = string("ABC"[sum[i]%3])
+	}
+	return themes
+}
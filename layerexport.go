@@ -0,0 +1,141 @@
+package multiavatar
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ExportSVGLayers returns the SVG avatar of input with every part, and the
+// border, frame, overlay and badge, in a group named after it, e.g.
+// <g id="eyes">, so designers can open it in Figma or Illustrator and
+// tweak the parts as separate layers. Part groups are named like the
+// parts: env, clo, head, mouth, eyes, top, hat and accessory. Styles that
+// draw the whole avatar, such as StyleBot, give a single "art" group. The
+// names take the WithIDPrefix prefix. An empty input and invalid options
+// are reported as errors.
+func ExportSVGLayers(input string, opts ...Option) (string, error) {
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return "", err
+	}
+	if input == "" {
+		return "", ErrEmptyInput
+	}
+	cfg.exportLayers = true
+	var b strings.Builder
+	cfg.writeSVG(&b, cfg.selectParts(input))
+	return b.String(), nil
+}
+
+// FigmaExport is the document written by ExportFigmaJSON.
+type FigmaExport struct {
+	// Name is the input the avatar was generated from.
+	Name string `json:"name"`
+	// Width and Height are the size of the avatar in pixels: the WithSize
+	// size, or its viewBox size.
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	// SVG is the whole avatar, as returned by ExportSVGLayers.
+	SVG string `json:"svg"`
+	// Layers are the groups of SVG, bottom first.
+	Layers []FigmaLayer `json:"layers"`
+}
+
+// FigmaLayer is one layer of a FigmaExport.
+type FigmaLayer struct {
+	// Name is the group id, e.g. "eyes".
+	Name string `json:"name"`
+	// SVG is a standalone SVG document of the layer alone, on the avatar
+	// canvas.
+	SVG string `json:"svg"`
+}
+
+// ExportFigmaJSON returns the avatar of input as the JSON of a
+// FigmaExport: the layered SVG of ExportSVGLayers and each layer as its
+// own SVG document, for Figma plugins that build the avatar with
+// figma.createNodeFromSvg, one named node per layer. An empty input and
+// invalid options are reported as errors.
+func ExportFigmaJSON(input string, opts ...Option) ([]byte, error) {
+	svg, err := ExportSVGLayers(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfig(opts)
+	vb := parseNumberList(cfg.viewBoxAttr())
+	doc := FigmaExport{Name: input, Width: vb[2], Height: vb[3], SVG: svg}
+	if cfg.size > 0 {
+		doc.Width, doc.Height = float64(cfg.size), float64(cfg.size)
+	}
+	start := svg[:strings.IndexByte(svg, '>')+1]
+	for _, name := range cfg.layerNames() {
+		if g := layerGroup(svg, cfg.layerID(name)); g != "" {
+			doc.Layers = append(doc.Layers, FigmaLayer{Name: cfg.layerID(name), SVG: start + g + `</svg>`})
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// layerNames lists every layer name in the order the layers are drawn.
+func (cfg *config) layerNames() []string {
+	order := cfg.layerOrder
+	if order == nil {
+		order = defaultLayerOrder
+	}
+	var names []string
+	add := func(above string) {
+		for _, x := range extraParts {
+			if x.above == above {
+				names = append(names, x.name)
+			}
+		}
+	}
+	names = append(names, "art")
+	for _, name := range order {
+		names = append(names, name)
+		add(name)
+	}
+	add("")
+	return append(names, "border", "frame", "overlay", "badge")
+}
+
+// layerID returns the id of the group of layer name.
+func (cfg *config) layerID(name string) string {
+	return cfg.idPrefix + name
+}
+
+// beginLayer starts the group of layer name when exporting layers.
+func (cfg *config) beginLayer(b svgWriter, name string) {
+	if cfg.exportLayers {
+		b.WriteString(`<g id="` + cfg.layerID(name) + `">`)
+	}
+}
+
+// endLayer ends the group started by beginLayer.
+func (cfg *config) endLayer(b svgWriter) {
+	if cfg.exportLayers {
+		b.WriteString(`</g>`)
+	}
+}
+
+// layerGroup returns the group with the given id in svg, which this
+// package generated, or "" if there is none.
+func layerGroup(svg, id string) string {
+	open := `<g id="` + id + `">`
+	start := strings.Index(svg, open)
+	if start < 0 {
+		return ""
+	}
+	depth := 0
+	for i := start; i < len(svg); i++ {
+		switch {
+		case strings.HasPrefix(svg[i:], "</g>"):
+			depth--
+			if depth == 0 {
+				return svg[start : i+len("</g>")]
+			}
+		case strings.HasPrefix(svg[i:], "<g ") || strings.HasPrefix(svg[i:], "<g>"):
+			depth++
+		}
+	}
+	return ""
+}
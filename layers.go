@@ -0,0 +1,43 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultLayerOrder is the original stacking, from bottom to top.
+var defaultLayerOrder = []string{"env", "head", "clo", "top", "eyes", "mouth"}
+
+// WithLayerOrder changes the stacking of the parts, from bottom to top.
+// All six parts must be listed once, e.g. to draw the clothes above the hair:
+//
+//	WithLayerOrder("env", "head", "top", "clo", "eyes", "mouth")
+//
+// Invalid orders keep the default env, head, clo, top, eyes, mouth and are
+// reported as errors by the error-returning APIs.
+func WithLayerOrder(parts ...string) Option {
+	return func(c *config) {
+		order := make([]string, len(parts))
+		seen := make(map[string]bool, len(parts))
+		for i, p := range parts {
+			p = strings.TrimSpace(p)
+			switch p {
+			case "env", "clo", "head", "mouth", "eyes", "top":
+			default:
+				c.errs = append(c.errs, fmt.Errorf("%w in layer order", ErrInvalidPart{Name: p}))
+				return
+			}
+			if seen[p] {
+				c.errs = append(c.errs, fmt.Errorf("multiavatar: part %q listed twice in layer order", p))
+				return
+			}
+			seen[p] = true
+			order[i] = p
+		}
+		if len(order) != len(defaultLayerOrder) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: layer order must list all %d parts, got %d", len(defaultLayerOrder), len(order)))
+			return
+		}
+		c.layerOrder = order
+	}
+}
@@ -0,0 +1,77 @@
+package multiavatar
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger logs the work of Encode and the batch APIs, ExportZip,
+// GenerateSrcSet and GenerateSheetImage, to l: every rendered avatar with
+// its input, format, size, algorithm and duration at Debug level, batch
+// totals at Info, invalid options at Warn and rendering failures at Error.
+// Inputs are logged as given; use a handler with ReplaceAttr to redact
+// them if they are e-mail addresses or other personal data. The
+// single-avatar APIs such as Generate do not log.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *config) { c.logger = l }
+}
+
+// checkOptions returns cfg's invalid option values, logging them for op.
+func (cfg *config) checkOptions(ctx context.Context, op string) error {
+	err := cfg.err()
+	if err != nil && cfg.logger != nil {
+		cfg.logger.LogAttrs(ctx, slog.LevelWarn, "multiavatar: invalid options",
+			slog.String("op", op), slog.Any("error", err))
+	}
+	return err
+}
+
+// logRender logs one avatar of op rendered since start, or its failure.
+func (cfg *config) logRender(ctx context.Context, op, input string, format Format, size int, start time.Time, err error) {
+	if cfg.logger == nil {
+		return
+	}
+	level, msg := slog.LevelDebug, "multiavatar: rendered avatar"
+	if err != nil {
+		level, msg = slog.LevelError, "multiavatar: render failed"
+	}
+	if !cfg.logger.Enabled(ctx, level) {
+		return
+	}
+	if size == 0 && format != FormatSVG {
+		size = defaultRasterSize
+	}
+	algorithm := cfg.algorithm
+	if algorithm == 0 {
+		algorithm = AlgorithmV1
+	}
+	attrs := []slog.Attr{
+		slog.String("op", op),
+		slog.String("input", input),
+		slog.String("format", format.String()),
+		slog.Int("size", size),
+		slog.Int("algorithm", int(algorithm)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	cfg.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logBatch logs the totals of a batch op of n avatars started at start.
+func (cfg *config) logBatch(ctx context.Context, op string, n int, start time.Time, err error) {
+	if cfg.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("op", op),
+		slog.Int("avatars", n),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	cfg.logger.LogAttrs(ctx, slog.LevelInfo, "multiavatar: batch done", attrs...)
+}
@@ -0,0 +1,56 @@
+package multiavatar
+
+import "strconv"
+
+// chestSlot is the square on the clothes where a logo is placed, in avatar units.
+type chestSlot struct{ x, y, size float64 }
+
+// cloChestSlots holds the logo anchor for every clothing version "00".."15":
+// the largest square of fabric on the wearer's left chest, clear of the
+// neckline. Version 07 is a tank top with narrower straps.
+var cloChestSlots = [16]chestSlot{
+	{138, 205, 16}, {138, 205, 16}, {138, 205, 16}, {138, 205, 16},
+	{138, 205, 16}, {138, 205, 16}, {138, 205, 16}, {142, 208, 12},
+	{138, 205, 16}, {138, 205, 16}, {138, 205, 16}, {138, 205, 16},
+	{138, 205, 16}, {138, 205, 16}, {138, 205, 16}, {138, 205, 16},
+}
+
+// clothingLogo is a sanitized logo fragment and the coordinate box it is drawn in.
+type clothingLogo struct {
+	svg     string
+	viewBox [4]float64
+}
+
+// WithClothingLogo places an SVG logo on the chest of the clothes, e.g. to put
+// a company mark on every employee avatar. The fragment is drawn in a 100×100
+// box unless it is a complete <svg> element with its own viewBox, and is
+// scaled into the slot of the selected clothing version.
+//
+// Only basic shapes and presentation attributes are kept; scripts, links,
+// ids and url() references are removed. Fragments over 16 KiB or 256
+// elements are rejected, reported by the error-returning APIs.
+func WithClothingLogo(svgFragment string) Option {
+	return func(c *config) {
+		frag, vb, err := sanitizeFragment(svgFragment)
+		if err != nil {
+			c.errs = append(c.errs, err)
+			return
+		}
+		if vb[2] == 0 {
+			vb = [4]float64{0, 0, 100, 100}
+		}
+		c.clothingLogo = &clothingLogo{svg: frag, viewBox: vb}
+	}
+}
+
+// render returns the logo positioned on the chest slot of clothing version v.
+func (l *clothingLogo) render(version string) string {
+	slot := cloChestSlots[0]
+	if id, err := strconv.Atoi(version); err == nil && id >= 0 && id < len(cloChestSlots) {
+		slot = cloChestSlots[id]
+	}
+	return `<svg x="` + formatFloat(slot.x) + `" y="` + formatFloat(slot.y) +
+		`" width="` + formatFloat(slot.size) + `" height="` + formatFloat(slot.size) +
+		`" viewBox="` + formatFloat(l.viewBox[0]) + " " + formatFloat(l.viewBox[1]) + " " +
+		formatFloat(l.viewBox[2]) + " " + formatFloat(l.viewBox[3]) + `">` + l.svg + `</svg>`
+}
@@ -0,0 +1,14 @@
+package multiavatar
+
+// WithMirror flips the avatar horizontally, so it faces the other way, e.g.
+// for the avatars on one side of a chat conversation. Borders, overlays
+// and badges are drawn unflipped on top.
+func WithMirror() Option {
+	return func(c *config) {
+		c.mirror = true
+	}
+}
+
+// mirrorTransform is the transform of the root group of mirrored avatars:
+// a reflection about the vertical center line of the canvas.
+const mirrorTransform = `matrix(-1,0,0,1,231,0)`
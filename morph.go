@@ -0,0 +1,107 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// GenerateMorph creates the SVG avatar a fraction t of the way from the
+// avatar of a to that of b, e.g. for account merges and team intros. Each
+// part takes the shape of the nearer avatar, a's for t < 0.5 and b's
+// from 0.5, and colors blended between both: GenerateMorph(a, b, 0) is
+// Generate(a) and GenerateMorph(a, b, 1) is Generate(b). t is clamped to
+// 0..1. With WithMorphAnimation the avatar instead cross-fades from a
+// towards b, ending at t.
+// An empty a or b gives an empty result.
+func GenerateMorph(a, b string, t float64, opts ...Option) string {
+	cfg := newConfig(opts)
+	if a == "" || b == "" {
+		return ""
+	}
+	if !(t > 0) {
+		t = 0
+	}
+	t = min(t, 1)
+	from, to := cfg.selectParts(a), cfg.selectParts(b)
+	buf := getBuffer(cfg.estimateSize(from) + cfg.estimateSize(to))
+	defer putBuffer(buf)
+	if cfg.morphDuration > 0 {
+		cfg.writeMorphAnimation(buf, from, to, t)
+	} else {
+		cfg.writeSVG(buf, morphParts(from, to, t))
+	}
+	return buf.String()
+}
+
+// WithMorphAnimation makes GenerateMorph return an animated SVG that
+// cross-fades every part from the first avatar towards the second over
+// duration, ending at t, and then holds. Durations below a millisecond
+// are reported as errors by the error-returning APIs. Other APIs ignore
+// it.
+func WithMorphAnimation(duration time.Duration) Option {
+	return func(c *config) {
+		if duration < time.Millisecond {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid morph duration %v", duration))
+			return
+		}
+		c.morphDuration = duration
+	}
+}
+
+// morphParts returns the parts of the avatar t of the way from from to to,
+// which list the same parts in the same order.
+func morphParts(from, to []selectedPart, t float64) []selectedPart {
+	switch t {
+	case 0:
+		return from
+	case 1:
+		return to
+	}
+	parts := make([]selectedPart, len(from))
+	for i, p := range from {
+		q := to[i]
+		if t >= 0.5 {
+			p, q = q, p
+		}
+		// blend the colors p and q have in common; w weights p's
+		w := 1 - t
+		if t >= 0.5 {
+			w = t
+		}
+		colors := make([]string, len(p.colors))
+		for j, c := range p.colors {
+			colors[j] = c
+			if j >= len(q.colors) {
+				continue
+			}
+			cp, ok1 := parseColor(colorOnly(c))
+			cq, ok2 := parseColor(colorOnly(q.colors[j]))
+			if !ok1 || !ok2 {
+				continue
+			}
+			_, suffix, _ := strings.Cut(c, ";")
+			colors[j] = formatColor(multiavatarcolor.Mix(cq, cp, w))
+			if suffix != "" {
+				colors[j] += ";" + suffix
+			}
+		}
+		p.colors = colors
+		parts[i] = p
+	}
+	return parts
+}
+
+// writeMorphAnimation writes the avatar of from with that of to on top,
+// fading in to opacity t.
+func (cfg *config) writeMorphAnimation(b svgWriter, from, to []selectedPart, t float64) {
+	cfg.writeSVGStart(b)
+	cfg.writeBody(b, from)
+	dur := strconv.FormatFloat(cfg.morphDuration.Seconds(), 'f', -1, 64)
+	b.WriteString(`<g opacity="0"><animate attributeName="opacity" from="0" to="` + formatFloat(t) + `" dur="` + dur + `s" fill="freeze"/>`)
+	cfg.writeBody(b, to)
+	b.WriteString(`</g></svg>`)
+}
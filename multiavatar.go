@@ -1,13 +1,14 @@
 package multiavatar
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
+	"errors"
 	"fmt"
-	"math"
-	"regexp"
-	"strconv"
+	"io"
+	"log/slog"
+	"slices"
 	"strings"
+	"time"
 )
 
 // config holds the configuration for generating an avatar.
@@ -28,6 +29,102 @@ type config struct {
 	// overrideColors allows overriding the colors array for a specific part
 	// e.g., {"head": {"#f2c280"}} to force skin tone
 	overrideColors map[string][]string
+	// darkColors are the colors of each part under prefers-color-scheme: dark
+	darkColors map[string][]string
+	// size sets the width/height attributes of the root <svg> in pixels (0 = unset)
+	size int
+	// srcSetURL builds the source URLs of GenerateSrcSet; nil renders data URIs
+	srcSetURL func(input string, size int, format Format) string
+	// viewBox overrides the root viewBox (x, y, w, h); nil keeps 0 0 231 231
+	viewBox *[4]float64
+	// padding grows the viewBox by this many units on every side
+	padding float64
+	// bgShape is the background geometry; 0 keeps the env art
+	bgShape BackgroundShape
+	// bgGradient replaces the flat env fill with a gradient
+	bgGradient *gradient
+	// bgPattern is drawn over the background; see WithBackgroundPattern
+	bgPattern *bgPattern
+	// idPrefix names the ids of definitions; "" derives them from a hash
+	idPrefix string
+	// clothingLogo is drawn on the chest of the clo layer
+	clothingLogo *clothingLogo
+	// border is a ring along the edge of the avatar
+	border *border
+	// frame is a decoration around the edge, above the border; see WithFrame
+	frame *Overlay
+	// overlay is a decoration drawn above all parts, the border and the frame
+	overlay *Overlay
+	// badge is a status indicator drawn over a corner
+	badge *placedBadge
+	// harmonious derives the env, clo and top colors from the input hash
+	harmonious bool
+	// minContrast is the least env contrast against clo and top; see WithMinContrast
+	minContrast float64
+	// jitter is mixed into the color selection; see WithDeterministicJitter
+	jitter string
+	// structureFields and colorField name the GenerateComposite fields that
+	// choose part versions and colors; see WithStructureField
+	structureFields map[string]string
+	colorField      string
+	// colorFilters are applied in order to every resolved color
+	colorFilters []colorFilter
+	// layerOrder is the stacking of the parts from bottom to top; nil uses defaultLayerOrder
+	layerOrder []string
+	// partTransforms wraps parts in a <g transform>; see WithPartTransform
+	partTransforms map[string]partTransform
+	// partOpacity wraps parts in a <g opacity>; see WithOpacity
+	partOpacity map[string]float64
+	// rootClasses and rootAttrs are added to the root <svg> element; see
+	// WithClass and WithRootAttr
+	rootClasses []string
+	rootAttrs   [][2]string
+	// rotation tilts the avatar by this many degrees; see WithRotation
+	rotation float64
+	// mirror flips the avatar horizontally; see WithMirror
+	mirror bool
+	// flippedMouths lists mouth versions drawn upside down by WithExpression
+	flippedMouths []string
+	// pack is the theme pack selected with WithStyle; nil uses the built-in art
+	pack *themePack
+	// blink draws open eyes closed, for animation frames
+	blink bool
+	// normalization is the Unicode form of the input before hashing; see
+	// WithUnicodeNormalization
+	normalization Normalization
+	// hashedInput takes inputs as their SHA-256 digest; see WithHashedInput
+	hashedInput bool
+	// normalizeEmail canonicalizes the input as an email address before hashing
+	normalizeEmail bool
+	// extraParts enables optional parts such as "hat"; see WithPart
+	extraParts map[string]bool
+	// algorithm is the selection algorithm; 0 means AlgorithmV1
+	algorithm Algorithm
+	// compatV1 pins output to the reference JavaScript library; see WithCompatV1
+	compatV1 bool
+	// precision, if set, rounds the numbers of the SVG; see WithPrecision
+	precision *int
+	// outline draws the parts as outlines; see WithOutlineStyle
+	outline *outline
+	// texture is layered over the avatar; see WithTexture
+	texture *texture
+	// exportLayers groups the layers by name; see ExportSVGLayers
+	exportLayers bool
+	// morphDuration animates GenerateMorph; see WithMorphAnimation
+	morphDuration time.Duration
+	// partStyles draws parts in other styles, nil for the built-in art;
+	// see WithPartStyle
+	partStyles map[string]*themePack
+	// rasterizer renders raster outputs; nil means the built-in one
+	rasterizer Rasterizer
+	// logger receives the logs of Encode and the batch APIs; see WithLogger
+	logger *slog.Logger
+	// errs collects invalid option values; reported by the error-returning APIs
+	errs []error
+	// applied counts the options applied so far; see applyOptions
+	applied int
+	// compiled, if set, holds the maps shared with these compiled options
+	compiled *CompiledOptions
 }
 
 // Option is a function that configures a generation option.
@@ -44,40 +141,51 @@ func WithTheme(theme string) Option {
 }
 
 // WithPartVersion forces a specific part to use a given version "00".."15".
+// Unknown parts and versions are ignored and reported as ErrInvalidPart and
+// ErrInvalidVersion by the error-returning APIs.
 func WithPartVersion(partName, partVersion string) Option {
 	return func(c *config) {
-		if c.forcePartV == nil {
-			c.forcePartV = make(map[string]string)
-		}
 		pn := strings.TrimSpace(partName)
 		pv := strings.TrimSpace(partVersion)
-		// basic validation: partName must be one of known parts and version must be 2-digit
-		switch pn {
-		case "env", "clo", "head", "mouth", "eyes", "top":
-			if len(pv) == 2 {
-				c.forcePartV[pn] = pv
-			}
+		if !c.checkPartName(pn) || !c.checkPartVersion(pn, pv) {
+			return
 		}
+		if c.forcePartV == nil {
+			c.forcePartV = make(map[string]string)
+		}
+		c.forcePartV[pn] = pv
 	}
 }
 
 // WithPartColors overrides the colors array used for a specific part.
 // For example, WithPartColors("head", []string{"#f2c280"}) to set skin tone.
+//
+// Colors must be hex (#rgb, #rgba, #rrggbb, #rrggbbaa), rgb()/rgba(),
+// hsl()/hsla(), a CSS named color, "none" or "transparent"; colors with
+// alpha are translucent.
+// If any color is invalid the override is dropped and the error is reported
+// by the error-returning APIs, so colors taken from user input cannot inject
+// markup. Unknown parts are reported as ErrInvalidPart.
 func WithPartColors(partName string, colors []string) Option {
 	return func(c *config) {
-		if c.overrideColors == nil {
-			c.overrideColors = make(map[string][]string)
-		}
 		pn := strings.TrimSpace(partName)
-		switch pn {
-		case "env", "clo", "head", "mouth", "eyes", "top":
-			// store a copy to avoid external mutation
-			cp := make([]string, len(colors))
-			for i := range colors {
-				cp[i] = strings.TrimSpace(colors[i])
+		if !c.checkPartName(pn) {
+			return
+		}
+		// store a copy to avoid external mutation
+		cp := make([]string, len(colors))
+		for i := range colors {
+			cp[i] = strings.TrimSpace(colors[i])
+			if err := checkColor(cp[i]); err != nil {
+				c.errs = append(c.errs, fmt.Errorf("%w for part %q", err, pn))
+				return
 			}
-			c.overrideColors[pn] = cp
+			cp[i] = cssColor(cp[i])
 		}
+		if c.overrideColors == nil {
+			c.overrideColors = make(map[string][]string)
+		}
+		c.overrideColors[pn] = cp
 	}
 }
 
@@ -114,81 +222,86 @@ func WithMouthColors(colors ...string) Option {
 }
 
 // WithPartTheme forces theme letter ("A","B","C") for a specific part.
+// Unknown parts are reported as ErrInvalidPart.
 func WithPartTheme(partName, theme string) Option {
 	return func(c *config) {
-		if c.partTheme == nil {
-			c.partTheme = make(map[string]string)
-		}
 		pn := strings.TrimSpace(partName)
 		t := strings.ToUpper(strings.TrimSpace(theme))
-		switch pn {
-		case "env", "clo", "head", "mouth", "eyes", "top":
-			if t == "A" || t == "B" || t == "C" {
-				c.partTheme[pn] = t
+		if !c.checkPartName(pn) {
+			return
+		}
+		if t == "A" || t == "B" || t == "C" {
+			if c.partTheme == nil {
+				c.partTheme = make(map[string]string)
 			}
+			c.partTheme[pn] = t
 		}
 	}
 }
 
 // WithAllowedThemes restricts a part to given theme letters (e.g., ["A","C"]).
 // Deterministic selection within the list based on the input hash slice.
+// Unknown parts are reported as ErrInvalidPart.
 func WithAllowedThemes(partName string, themesList []string) Option {
 	return func(c *config) {
-		if c.allowedThemes == nil {
-			c.allowedThemes = make(map[string][]string)
-		}
 		pn := strings.TrimSpace(partName)
-		switch pn {
-		case "env", "clo", "head", "mouth", "eyes", "top":
-			var tl []string
-			for _, t := range themesList {
-				tu := strings.ToUpper(strings.TrimSpace(t))
-				if tu == "A" || tu == "B" || tu == "C" {
-					tl = append(tl, tu)
-				}
+		if !c.checkPartName(pn) {
+			return
+		}
+		var tl []string
+		for _, t := range themesList {
+			tu := strings.ToUpper(strings.TrimSpace(t))
+			if tu == "A" || tu == "B" || tu == "C" {
+				tl = append(tl, tu)
 			}
-			if len(tl) > 0 {
-				c.allowedThemes[pn] = tl
+		}
+		if len(tl) > 0 {
+			if c.allowedThemes == nil {
+				c.allowedThemes = make(map[string][]string)
 			}
+			c.allowedThemes[pn] = tl
 		}
 	}
 }
 
 // WithoutPart disables rendering a specific part (e.g., "top" to remove hair).
+// Unknown parts are reported as ErrInvalidPart.
 func WithoutPart(partName string) Option {
 	return func(c *config) {
+		pn := strings.TrimSpace(partName)
+		if !c.checkPartName(pn) {
+			return
+		}
 		if c.disabledParts == nil {
 			c.disabledParts = make(map[string]bool)
 		}
-		pn := strings.TrimSpace(partName)
-		switch pn {
-		case "env", "clo", "head", "mouth", "eyes", "top":
-			c.disabledParts[pn] = true
-		}
+		c.disabledParts[pn] = true
 	}
 }
 
 // WithAllowedVersions restricts a part to given version list (e.g., ["01","03","07"]).
 // The algorithm will pick deterministically within the list based on the input hash.
+// Unknown parts and versions drop the restriction and are reported as
+// ErrInvalidPart and ErrInvalidVersion by the error-returning APIs.
 func WithAllowedVersions(partName string, versions []string) Option {
 	return func(c *config) {
-		if c.allowedVersions == nil {
-			c.allowedVersions = make(map[string][]string)
-		}
 		pn := strings.TrimSpace(partName)
-		switch pn {
-		case "env", "clo", "head", "mouth", "eyes", "top":
-			// sanitize to 2-digit codes
-			var vlist []string
-			for _, v := range versions {
-				v = strings.TrimSpace(v)
-				if len(v) == 2 {
-					vlist = append(vlist, v)
-				}
+		if !c.checkPartName(pn) {
+			return
+		}
+		var vlist []string
+		for _, v := range versions {
+			v = strings.TrimSpace(v)
+			if !c.checkPartVersion(pn, v) {
+				return
 			}
-			if len(vlist) > 0 {
-				c.allowedVersions[pn] = vlist
+			vlist = append(vlist, v)
+		}
+		if len(vlist) > 0 {
+			if c.allowedVersions == nil {
+				c.allowedVersions = make(map[string][]string)
 			}
+			c.allowedVersions[pn] = vlist
 		}
 	}
 }
@@ -250,12 +363,32 @@ func WithoutBackground() Option {
 	}
 }
 
-// Generate creates an SVG avatar string from an input string based on a deterministic algorithm.
-// It is thread-safe.
-func Generate(input string, opts ...Option) string {
-	cfg := &config{}
-	for _, opt := range opts {
-		opt(cfg)
+// WithSize sets the rendered width and height of the SVG in pixels.
+// The viewBox is unaffected, so the artwork simply scales. Non-positive sizes are ignored.
+func WithSize(px int) Option {
+	return func(c *config) {
+		if px > 0 {
+			c.size = px
+		}
+	}
+}
+
+// partNames lists the avatar parts in the order they consume hash digits.
+var partNames = []string{"env", "clo", "head", "mouth", "eyes", "top"}
+
+// selectedPart is the resolved choice for one part of an avatar.
+type selectedPart struct {
+	name    string
+	version string // "00".."15"
+	theme   string // "A", "B" or "C"
+	colors  []string
+}
+
+// newConfig applies opts to a fresh config with all maps initialized.
+func newConfig(opts []Option) *config {
+	cfg := applyOptions(opts)
+	if cfg.compatV1 {
+		cfg = compatConfig(cfg)
 	}
 
 	// ensure internal maps are initialized
@@ -277,131 +410,157 @@ func Generate(input string, opts ...Option) string {
 	if cfg.overrideColors == nil {
 		cfg.overrideColors = make(map[string][]string)
 	}
+	return cfg
+}
+
+// err reports the problems recorded while applying options.
+func (cfg *config) err() error {
+	return errors.Join(cfg.errs...)
+}
 
+// Generate creates an SVG avatar string from an input string based on a deterministic algorithm.
+// It is thread-safe.
+func Generate(input string, opts ...Option) string {
+	cfg := newConfig(opts)
 	if input == "" {
 		return ""
 	}
 
-	// 1. SHA-256 hash
-	hashBytes := sha256.Sum256([]byte(input))
-	hexHash := hex.EncodeToString(hashBytes[:])
+	return cfg.renderSVG(input)
+}
 
-	// 2. Remove non-digits (mimicking JS replace(/\D/g, ''))
-	re := regexp.MustCompile(`[^0-9]`)
-	sha256Numbers := re.ReplaceAllString(hexHash, "")
+// GenerateTo writes the SVG avatar for input directly to w, e.g. an
+// http.ResponseWriter or a gzip.Writer, without building the whole document
+// in memory first. Unlike Generate it reports invalid options, an empty
+// input and write errors.
+func GenerateTo(w io.Writer, input string, opts ...Option) error {
+	if input == "" {
+		return ErrEmptyInput
+	}
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return err
+	}
+	bw := getWriter(w)
+	defer putWriter(bw)
+	cfg.writeSVG(bw, cfg.selectParts(input))
+	return bw.Flush()
+}
 
-	// 3. Get the first 12 digits
-	hashStr := sha256Numbers
-	if len(hashStr) > 12 {
-		hashStr = hashStr[:12]
+// AppendSVG appends the SVG avatar for input to dst and returns the
+// extended slice, like the strconv Append functions. Reusing dst across
+// calls, e.g. dst = AppendSVG(dst[:0], name), renders in tight loops such
+// as bulk exports without allocating per avatar once dst has grown. Like
+// Generate, it ignores invalid options; an empty input appends nothing.
+func AppendSVG(dst []byte, input string, opts ...Option) []byte {
+	if input == "" {
+		return dst
 	}
+	cfg := newConfig(opts)
+	selected := cfg.selectParts(input)
+	w := appendPool.Get().(*appendWriter)
+	w.b = slices.Grow(dst, cfg.estimateSize(selected))
+	cfg.writeSVG(w, selected)
+	dst, w.b = w.b, nil
+	appendPool.Put(w)
+	return dst
+}
 
-	// 4. Determine parts
-	partNames := []string{"env", "clo", "head", "mouth", "eyes", "top"}
-	selectedParts := make(map[string]string)
+// GenerateContext is like Generate but reports ctx's error if ctx is done,
+// and invalid options and an empty input like GenerateTo. Use the Context
+// variants of the raster and batch APIs, such as GenerateImageContext and
+// ExportZipContext, to stop long renders when a request is canceled.
+func GenerateContext(ctx context.Context, input string, opts ...Option) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if input == "" {
+		return "", ErrEmptyInput
+	}
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return "", err
+	}
+	return cfg.renderSVG(input), nil
+}
 
-	for i, name := range partNames {
-		// 4a. Take 2 digits
-		valStr := hashStr[i*2 : i*2+2]
-		val, _ := strconv.Atoi(valStr)
+// selectParts runs the deterministic selection: it hashes the input and picks
+// a version, theme and colors for every part, honoring the configured restrictions.
+func (cfg *config) selectParts(input string) []selectedPart {
+	input = cfg.normalizeInput(input)
 
-		// 4b. Scale to 0-47 range
-		nr := int(math.Round(float64(val) * 47 / 100))
+	// 1. SHA-256 hash, 2.-4b. scaled to a 0-47 slot per part
+	slots := cfg.hashSlots(input)
 
-		// 4c. Determine version (partV) and theme (A, B, C)
-		var partV, theme string
-		if nr > 31 {
-			partV = fmt.Sprintf("%02d", nr-32)
-			theme = "C"
-		} else if nr > 15 {
-			partV = fmt.Sprintf("%02d", nr-16)
-			theme = "B"
-		} else {
-			partV = fmt.Sprintf("%02d", nr)
-			theme = "A"
-		}
+	// 4. Determine parts
+	selected := make([]selectedPart, 0, len(partNames))
+	var jittered []string
+	if cfg.jitter != "" {
+		jittered = cfg.jitterThemes(input)
+	}
 
-		// Apply forced/global/per-part theme/version if configured
-		if cfg.selectedTheme != nil {
-			theme = *cfg.selectedTheme
-		}
-		if pt, ok := cfg.partTheme[name]; ok {
-			theme = pt
-		} else if allowedT, ok := cfg.allowedThemes[name]; ok && len(allowedT) > 0 {
-			theme = allowedT[val%len(allowedT)]
-		}
+	for i, name := range partNames {
+		nr, val := slots[i].nr, slots[i].val
 
-		if forced, ok := cfg.forcePartV[name]; ok && len(forced) == 2 {
-			partV = forced
-		} else if allowed, ok := cfg.allowedVersions[name]; ok && len(allowed) > 0 {
-			partV = allowed[val%len(allowed)]
+		// 4c. Determine version (partV) and theme (A, B, C)
+		partV, theme := slotPart(nr)
+		if jittered != nil {
+			theme = jittered[i]
 		}
 
-		// 4d. Get the final SVG part with colors, allowing overrides
-		selectedParts[name] = getFinalPartWithOverride(name, partV, theme, cfg.overrideColors[name])
+		selected = append(selected, cfg.resolvePart(name, partV, theme, val))
 	}
+	if cfg.harmonious {
+		cfg.harmonize(cfg.colorSum(input), selected)
+	}
+	cfg.ensureContrast(selected)
+	return cfg.selectExtraParts(input, selected)
+}
 
-	// 5. Assemble the final SVG
-	var finalSVG strings.Builder
-	finalSVG.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 231 231">`)
+// slotPart returns the version and theme of a 0..47 hash slot: slots
+// 0..15 are versions 00..15 of theme A, 16..31 of theme B and 32..47 of C.
+func slotPart(nr int) (partV, theme string) {
+	return fmt.Sprintf("%02d", nr%16), string("ABC"[nr/16])
+}
 
-	if !cfg.withoutBackground && !cfg.disabledParts["env"] {
-		finalSVG.WriteString(selectedParts["env"])
-	}
-	if !cfg.disabledParts["head"] {
-		finalSVG.WriteString(selectedParts["head"])
-	}
-	if !cfg.disabledParts["clo"] {
-		finalSVG.WriteString(selectedParts["clo"])
+// resolvePart applies the configured theme, version and color overrides to
+// the hash-derived choice for a part; val indexes the allowed lists.
+func (cfg *config) resolvePart(name, partV, theme string, val int) selectedPart {
+	hashV := partV
+	choice := cfg.resolveChoice(name, partV, theme, val)
+	partV, theme = choice.Version, choice.Theme
+
+	// 4d. Resolve colors, allowing overrides; head shapes keep the skin
+	// color of the hashed head
+	colorV := partV
+	if _, ok := headShape(partV); ok && name == "head" {
+		colorV = hashV
 	}
-	if !cfg.disabledParts["top"] {
-		finalSVG.WriteString(selectedParts["top"])
+	colors := cfg.partColors(name, colorV, theme)
+	if override := cfg.overrideColors[name]; len(override) > 0 {
+		colors = override
 	}
-	if !cfg.disabledParts["eyes"] {
-		finalSVG.WriteString(selectedParts["eyes"])
-	}
-	if !cfg.disabledParts["mouth"] {
-		finalSVG.WriteString(selectedParts["mouth"])
-	}
-
-	finalSVG.WriteString(`</svg>`)
 
-	return finalSVG.String()
+	return selectedPart{name: name, version: partV, theme: theme, colors: cfg.filterColors(colors)}
 }
 
-// getFinalPartWithOverride retrieves the raw SVG string for a part,
-// and replaces color placeholders, allowing optional color overrides.
-func getFinalPartWithOverride(partName, partV, theme string, override []string) string {
-	colors, ok := themes[partV][theme][partName]
-	if !ok {
-		return "" // Should not happen with correct logic
+// resolveChoice applies the configured theme and version options to the
+// hash-derived choice for a part.
+func (cfg *config) resolveChoice(name, partV, theme string, val int) PartSpec {
+	// Apply forced/global/per-part theme/version if configured
+	if cfg.selectedTheme != nil {
+		theme = *cfg.selectedTheme
 	}
-
-	// If override provided, use it (truncate/extend matching placeholders count on use)
-	if override != nil && len(override) > 0 {
-		// copy to avoid mutating themes
-		cp := make([]string, len(override))
-		copy(cp, override)
-		colors = cp
+	if pt, ok := cfg.partTheme[name]; ok {
+		theme = pt
+	} else if allowedT, ok := cfg.allowedThemes[name]; ok && len(allowedT) > 0 {
+		theme = allowedT[val%len(allowedT)]
 	}
 
-	partID, _ := strconv.Atoi(partV)
-	partIndex := map[string]int{"env": 0, "clo": 1, "head": 2, "mouth": 3, "eyes": 4, "top": 5}[partName]
-
-	svgString := parts[partID][partIndex]
-
-	// Replace color placeholders like "#01;"
-	re := regexp.MustCompile(`#(.*?);`)
-	matches := re.FindAllString(svgString, -1)
-
-	resultFinal := svgString
-	if matches != nil {
-		for i, placeholder := range matches {
-			if i < len(colors) {
-				resultFinal = strings.Replace(resultFinal, placeholder, colors[i]+";", 1)
-			}
-		}
+	if forced, ok := cfg.forcePartV[name]; ok && len(forced) == 2 {
+		partV = forced
+	} else if allowed, ok := cfg.allowedVersions[name]; ok && len(allowed) > 0 {
+		partV = allowed[val%len(allowed)]
 	}
-
-	return resultFinal
+	return PartSpec{Version: partV, Theme: theme}
 }
@@ -0,0 +1,235 @@
+// Package multiavatarclient is a typed client for the avatar endpoint served
+// by multiavatarhttp.NewHandler, described by multiavatarhttp.OpenAPI.
+//
+//	svg, err := multiavatarclient.Get(ctx, "https://avatars.example.com/avatar", "alice",
+//		multiavatarclient.WithTheme("B"),
+//		multiavatarclient.WithoutBackground(),
+//	)
+//
+// URL builds the same request as a URL, e.g. for an <img> src.
+package multiavatarclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarhttp"
+)
+
+// maxResponseSize bounds the avatar body read by Get.
+const maxResponseSize = 4 << 20
+
+// request collects the query parameters of one avatar request.
+type request struct {
+	query url.Values
+	// perPart holds the part:value parameters, by parameter then part
+	perPart    map[string]map[string]string
+	signingKey []byte
+	client     *http.Client
+}
+
+// Option sets a request parameter.
+type Option func(*request)
+
+func param(key, value string) Option {
+	return func(r *request) { r.query.Set(key, value) }
+}
+
+func partParam(key, part string, values ...string) Option {
+	return func(r *request) {
+		if r.perPart[key] == nil {
+			r.perPart[key] = make(map[string]string)
+		}
+		r.perPart[key][part] = strings.Join(values, "|")
+	}
+}
+
+// WithProfile applies a profile registered on the server (profile=).
+func WithProfile(name string) Option { return param("profile", name) }
+
+// WithAlgorithm sets the selection algorithm version (algorithm=).
+func WithAlgorithm(v int) Option { return param("algorithm", strconv.Itoa(v)) }
+
+// WithStyle selects a built-in style or registered theme pack (style=).
+func WithStyle(name string) Option { return param("style", name) }
+
+// WithoutBackground omits the background (transparent=true).
+func WithoutBackground() Option { return param("transparent", "true") }
+
+// WithBackgroundShape sets the background shape: "none", "circle" or
+// "squircle" (shape=).
+func WithBackgroundShape(shape string) Option { return param("shape", shape) }
+
+// WithHarmoniousColors derives the background, clothes and hair colors
+// from the name (harmonious=true).
+func WithHarmoniousColors() Option { return param("harmonious", "true") }
+
+// WithMirror flips the avatar horizontally (mirror=true).
+func WithMirror() Option { return param("mirror", "true") }
+
+// WithFrame draws a frame such as "gold", "laurel" or "flame" (frame=).
+func WithFrame(name string) Option { return param("frame", name) }
+
+// WithRotation tilts the avatar by degrees clockwise (rotation=).
+func WithRotation(degrees float64) Option {
+	return param("rotation", strconv.FormatFloat(degrees, 'f', -1, 64))
+}
+
+// WithTheme sets the theme, "A", "B" or "C", of every part (theme=).
+func WithTheme(theme string) Option { return param("theme", theme) }
+
+// WithGender applies a gender preset: "male", "female" or "unisex" (gender=).
+func WithGender(gender string) Option { return param("gender", gender) }
+
+// WithExpression applies an expression preset such as "happy" (expression=).
+func WithExpression(expression string) Option { return param("expression", expression) }
+
+// WithPartTheme sets the theme of one part (partTheme=part:theme).
+func WithPartTheme(part, theme string) Option { return partParam("partTheme", part, theme) }
+
+// WithAllowedThemes restricts the themes of one part (allowedThemes=part:A|C).
+func WithAllowedThemes(part string, themes ...string) Option {
+	return partParam("allowedThemes", part, themes...)
+}
+
+// WithPartVersion forces one part to a version code or registered name
+// (partVersion=part:version).
+func WithPartVersion(part, version string) Option { return partParam("partVersion", part, version) }
+
+// WithAllowedVersions restricts the versions of one part (allowedVersions=part:03|11).
+func WithAllowedVersions(part string, versions ...string) Option {
+	return partParam("allowedVersions", part, versions...)
+}
+
+// WithPartColors overrides the colors of one part (env=, clo=, head=,
+// mouth=, eyes=, top=, hat= or accessory=).
+func WithPartColors(part string, colors ...string) Option {
+	return param(part, strings.Join(colors, "|"))
+}
+
+// WithPart adds optional parts, "hat" or "accessory" (withPart=hat|accessory).
+func WithPart(parts ...string) Option {
+	return func(r *request) {
+		list := append(splitList(r.query.Get("withPart")), parts...)
+		r.query.Set("withPart", strings.Join(list, "|"))
+	}
+}
+
+// WithoutPart leaves parts out (withoutPart=top|eyes).
+func WithoutPart(parts ...string) Option {
+	return func(r *request) {
+		list := append(splitList(r.query.Get("withoutPart")), parts...)
+		r.query.Set("withoutPart", strings.Join(list, "|"))
+	}
+}
+
+// WithSigningKey signs the request for a handler configured with
+// multiavatarhttp.WithSigningKey.
+func WithSigningKey(key []byte) Option {
+	return func(r *request) { r.signingKey = key }
+}
+
+// WithHTTPClient sets the client used by Get; the default is http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(r *request) { r.client = c }
+}
+
+// StatusError is returned by Get for responses other than 200 OK.
+type StatusError struct {
+	Code int
+	// Message is the plain-text error from the server, if any.
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("multiavatarclient: %d %s", e.Code, http.StatusText(e.Code))
+	}
+	return fmt.Sprintf("multiavatarclient: %d %s: %s", e.Code, http.StatusText(e.Code), e.Message)
+}
+
+// URL returns the URL of the avatar for name at baseURL, the URL the
+// handler is mounted at, e.g. "https://avatars.example.com/avatar".
+func URL(baseURL, name string, opts ...Option) (string, error) {
+	u, _, err := build(baseURL, name, opts)
+	return u, err
+}
+
+// Get fetches the SVG of the avatar for name from baseURL. Responses other
+// than 200 OK are returned as *StatusError.
+func Get(ctx context.Context, baseURL, name string, opts ...Option) ([]byte, error) {
+	u, r, err := build(baseURL, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/svg+xml")
+	client := r.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return body, nil
+}
+
+// build assembles the request URL.
+func build(baseURL, name string, opts []Option) (string, *request, error) {
+	if name == "" {
+		return "", nil, errors.New("multiavatarclient: empty name")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", nil, err
+	}
+	r := &request{query: u.Query(), perPart: make(map[string]map[string]string)}
+	r.query.Set("name", name)
+	for _, opt := range opts {
+		opt(r)
+	}
+	for key, parts := range r.perPart {
+		names := make([]string, 0, len(parts))
+		for p := range parts {
+			names = append(names, p)
+		}
+		sort.Strings(names)
+		pairs := make([]string, len(names))
+		for i, p := range names {
+			pairs[i] = p + ":" + parts[p]
+		}
+		r.query.Set(key, strings.Join(pairs, ","))
+	}
+	u.RawQuery = r.query.Encode()
+	if r.signingKey == nil {
+		return u.String(), r, nil
+	}
+	signed, err := multiavatarhttp.SignURL(r.signingKey, u.String())
+	return signed, r, err
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "|")
+}
@@ -0,0 +1,322 @@
+// Package multiavatarcolor parses, converts and compares CSS colors the way
+// multiavatar does, for code built around the library such as avatar
+// editors that let users pick part colors:
+//
+//	c, err := multiavatarcolor.Parse("hsl(210, 60%, 45%)")
+//	if err != nil {
+//		return err
+//	}
+//	hover := multiavatarcolor.Format(multiavatarcolor.Lighten(c, 0.1))
+//	readable := multiavatarcolor.ContrastRatio(c, white) >= 4.5
+//
+// Every color Parse accepts is accepted by the multiavatar color options.
+package multiavatarcolor
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a CSS color: #rgb, #rgba, #rrggbb, #rrggbbaa, rgb(),
+// rgba(), hsl(), hsla() and the named colors, in any case and with
+// surrounding space. "none" and "transparent" parse as fully transparent.
+func Parse(s string) (color.NRGBA, error) {
+	c, ok := parse(strings.ToLower(strings.TrimSpace(s)))
+	if !ok {
+		return color.NRGBA{}, fmt.Errorf("multiavatarcolor: invalid color %q", s)
+	}
+	return c, nil
+}
+
+func parse(s string) (color.NRGBA, bool) {
+	switch {
+	case s == "none" || s == "transparent":
+		return color.NRGBA{}, true
+	case strings.HasPrefix(s, "#"):
+		return parseHex(s[1:])
+	case strings.HasPrefix(s, "rgb(") || strings.HasPrefix(s, "rgba("):
+		return parseRGBFunc(s)
+	case strings.HasPrefix(s, "hsl(") || strings.HasPrefix(s, "hsla("):
+		return parseHSLFunc(s)
+	}
+	if c, ok := namedColors[s]; ok {
+		return c, true
+	}
+	return color.NRGBA{}, false
+}
+
+func parseHex(h string) (color.NRGBA, bool) {
+	for i := 0; i < len(h); i++ {
+		if !isHexDigit(h[i]) {
+			return color.NRGBA{}, false
+		}
+	}
+	nib := func(i int) uint8 { v, _ := strconv.ParseUint(h[i:i+1], 16, 8); return uint8(v) * 17 }
+	byt := func(i int) uint8 { v, _ := strconv.ParseUint(h[i:i+2], 16, 8); return uint8(v) }
+	switch len(h) {
+	case 3:
+		return color.NRGBA{nib(0), nib(1), nib(2), 255}, true
+	case 4:
+		return color.NRGBA{nib(0), nib(1), nib(2), nib(3)}, true
+	case 6:
+		return color.NRGBA{byt(0), byt(2), byt(4), 255}, true
+	case 8:
+		return color.NRGBA{byt(0), byt(2), byt(4), byt(6)}, true
+	}
+	return color.NRGBA{}, false
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// funcArgs returns the three or four arguments of a CSS color function,
+// separated by commas, spaces or a slash before the alpha.
+func funcArgs(s string) ([]string, bool) {
+	open, end := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+	if open < 0 || end != len(s)-1 {
+		return nil, false
+	}
+	args := strings.FieldsFunc(s[open+1:end], func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+	return args, len(args) == 3 || len(args) == 4
+}
+
+// parseAlpha parses an alpha argument, a number in [0, 1] or a percentage.
+func parseAlpha(a string) (uint8, bool) {
+	pct := strings.HasSuffix(a, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(a, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	if pct {
+		v /= 100
+	}
+	return uint8(clamp(v, 0, 1)*255 + 0.5), true
+}
+
+// parseRGBFunc parses rgb(r,g,b) and rgba(r,g,b,a); channels may be percentages.
+func parseRGBFunc(s string) (color.NRGBA, bool) {
+	args, ok := funcArgs(s)
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	var ch [4]uint8
+	ch[3] = 255
+	for i, a := range args {
+		if i == 3 {
+			if ch[3], ok = parseAlpha(a); !ok {
+				return color.NRGBA{}, false
+			}
+			continue
+		}
+		pct := strings.HasSuffix(a, "%")
+		v, err := strconv.ParseFloat(strings.TrimSuffix(a, "%"), 64)
+		if err != nil {
+			return color.NRGBA{}, false
+		}
+		if pct {
+			v = v / 100 * 255
+		}
+		ch[i] = uint8(clamp(v, 0, 255) + 0.5)
+	}
+	return color.NRGBA{ch[0], ch[1], ch[2], ch[3]}, true
+}
+
+// parseHSLFunc parses hsl(h,s%,l%) and hsla(h,s%,l%,a); the hue is in
+// degrees, optionally with a "deg" unit.
+func parseHSLFunc(s string) (color.NRGBA, bool) {
+	args, ok := funcArgs(s)
+	if !ok || !strings.HasSuffix(args[1], "%") || !strings.HasSuffix(args[2], "%") {
+		return color.NRGBA{}, false
+	}
+	h, err1 := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+	sat, err2 := strconv.ParseFloat(strings.TrimSuffix(args[1], "%"), 64)
+	l, err3 := strconv.ParseFloat(strings.TrimSuffix(args[2], "%"), 64)
+	if err1 != nil || err2 != nil || err3 != nil || math.IsInf(h, 0) || math.IsNaN(h) {
+		return color.NRGBA{}, false
+	}
+	c := FromHSL(h, clamp(sat/100, 0, 1), clamp(l/100, 0, 1))
+	if len(args) == 4 {
+		if c.A, ok = parseAlpha(args[3]); !ok {
+			return color.NRGBA{}, false
+		}
+	}
+	return c, true
+}
+
+// Format formats c as #rrggbb, or as rgba() when it is translucent.
+func Format(c color.NRGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	a := math.Round(float64(c.A)/255*1e4) / 1e4
+	return fmt.Sprintf("rgba(%d,%d,%d,%s)", c.R, c.G, c.B, strconv.FormatFloat(a, 'f', -1, 64))
+}
+
+// ToHSL converts c to hue in degrees [0, 360) and saturation and
+// lightness in [0, 1]. Alpha is ignored.
+func ToHSL(c color.NRGBA) (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (hi + lo) / 2
+	d := hi - lo
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// FromHSL is the inverse of ToHSL; the result is opaque. Hues outside
+// [0, 360) wrap around.
+func FromHSL(h, s, l float64) color.NRGBA {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	ch := (1 - math.Abs(2*l-1)) * s
+	x := ch * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - ch/2
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = ch, x
+	case h < 120:
+		r, g = x, ch
+	case h < 180:
+		g, b = ch, x
+	case h < 240:
+		g, b = x, ch
+	case h < 300:
+		r, b = x, ch
+	default:
+		r, b = ch, x
+	}
+	u := func(v float64) uint8 { return uint8(math.Round(clamp((v+m)*255, 0, 255))) }
+	return color.NRGBA{R: u(r), G: u(g), B: u(b), A: 255}
+}
+
+// Lighten raises the HSL lightness of c by amount, e.g. 0.1 for ten
+// percentage points, keeping its hue, saturation and alpha. Lightness
+// stops at white.
+func Lighten(c color.NRGBA, amount float64) color.NRGBA {
+	h, s, l := ToHSL(c)
+	out := FromHSL(h, s, clamp(l+amount, 0, 1))
+	out.A = c.A
+	return out
+}
+
+// Darken lowers the HSL lightness of c by amount; see Lighten.
+func Darken(c color.NRGBA, amount float64) color.NRGBA {
+	return Lighten(c, -amount)
+}
+
+// Mix blends a and b, from a at t = 0 to b at t = 1.
+func Mix(a, b color.NRGBA, t float64) color.NRGBA {
+	t = clamp(t, 0, 1)
+	ch := func(x, y uint8) uint8 { return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t)) }
+	return color.NRGBA{ch(a.R, b.R), ch(a.G, b.G), ch(a.B, b.B), ch(a.A, b.A)}
+}
+
+// Luminance returns the WCAG relative luminance of c, from 0 (black) to 1
+// (white). Alpha is ignored.
+func Luminance(c color.NRGBA) float64 {
+	lin := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(c.R) + 0.7152*lin(c.G) + 0.0722*lin(c.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio of a and b, from 1 (same
+// luminance) to 21 (black on white). WCAG AA asks for 4.5 for body text
+// and 3 for large text and graphics.
+func ContrastRatio(a, b color.NRGBA) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// namedColors holds the CSS named colors.
+var namedColors = map[string]color.NRGBA{
+	"aliceblue": {240, 248, 255, 255}, "antiquewhite": {250, 235, 215, 255}, "aqua": {0, 255, 255, 255},
+	"aquamarine": {127, 255, 212, 255}, "azure": {240, 255, 255, 255}, "beige": {245, 245, 220, 255},
+	"bisque": {255, 228, 196, 255}, "black": {0, 0, 0, 255}, "blanchedalmond": {255, 235, 205, 255},
+	"blue": {0, 0, 255, 255}, "blueviolet": {138, 43, 226, 255}, "brown": {165, 42, 42, 255},
+	"burlywood": {222, 184, 135, 255}, "cadetblue": {95, 158, 160, 255}, "chartreuse": {127, 255, 0, 255},
+	"chocolate": {210, 105, 30, 255}, "coral": {255, 127, 80, 255}, "cornflowerblue": {100, 149, 237, 255},
+	"cornsilk": {255, 248, 220, 255}, "crimson": {220, 20, 60, 255}, "cyan": {0, 255, 255, 255},
+	"darkblue": {0, 0, 139, 255}, "darkcyan": {0, 139, 139, 255}, "darkgoldenrod": {184, 134, 11, 255},
+	"darkgray": {169, 169, 169, 255}, "darkgreen": {0, 100, 0, 255}, "darkgrey": {169, 169, 169, 255},
+	"darkkhaki": {189, 183, 107, 255}, "darkmagenta": {139, 0, 139, 255}, "darkolivegreen": {85, 107, 47, 255},
+	"darkorange": {255, 140, 0, 255}, "darkorchid": {153, 50, 204, 255}, "darkred": {139, 0, 0, 255},
+	"darksalmon": {233, 150, 122, 255}, "darkseagreen": {143, 188, 143, 255}, "darkslateblue": {72, 61, 139, 255},
+	"darkslategray": {47, 79, 79, 255}, "darkslategrey": {47, 79, 79, 255}, "darkturquoise": {0, 206, 209, 255},
+	"darkviolet": {148, 0, 211, 255}, "deeppink": {255, 20, 147, 255}, "deepskyblue": {0, 191, 255, 255},
+	"dimgray": {105, 105, 105, 255}, "dimgrey": {105, 105, 105, 255}, "dodgerblue": {30, 144, 255, 255},
+	"firebrick": {178, 34, 34, 255}, "floralwhite": {255, 250, 240, 255}, "forestgreen": {34, 139, 34, 255},
+	"fuchsia": {255, 0, 255, 255}, "gainsboro": {220, 220, 220, 255}, "ghostwhite": {248, 248, 255, 255},
+	"gold": {255, 215, 0, 255}, "goldenrod": {218, 165, 32, 255}, "gray": {128, 128, 128, 255},
+	"green": {0, 128, 0, 255}, "greenyellow": {173, 255, 47, 255}, "grey": {128, 128, 128, 255},
+	"honeydew": {240, 255, 240, 255}, "hotpink": {255, 105, 180, 255}, "indianred": {205, 92, 92, 255},
+	"indigo": {75, 0, 130, 255}, "ivory": {255, 255, 240, 255}, "khaki": {240, 230, 140, 255},
+	"lavender": {230, 230, 250, 255}, "lavenderblush": {255, 240, 245, 255}, "lawngreen": {124, 252, 0, 255},
+	"lemonchiffon": {255, 250, 205, 255}, "lightblue": {173, 216, 230, 255}, "lightcoral": {240, 128, 128, 255},
+	"lightcyan": {224, 255, 255, 255}, "lightgoldenrodyellow": {250, 250, 210, 255}, "lightgray": {211, 211, 211, 255},
+	"lightgreen": {144, 238, 144, 255}, "lightgrey": {211, 211, 211, 255}, "lightpink": {255, 182, 193, 255},
+	"lightsalmon": {255, 160, 122, 255}, "lightseagreen": {32, 178, 170, 255}, "lightskyblue": {135, 206, 250, 255},
+	"lightslategray": {119, 136, 153, 255}, "lightslategrey": {119, 136, 153, 255}, "lightsteelblue": {176, 196, 222, 255},
+	"lightyellow": {255, 255, 224, 255}, "lime": {0, 255, 0, 255}, "limegreen": {50, 205, 50, 255},
+	"linen": {250, 240, 230, 255}, "magenta": {255, 0, 255, 255}, "maroon": {128, 0, 0, 255},
+	"mediumaquamarine": {102, 205, 170, 255}, "mediumblue": {0, 0, 205, 255}, "mediumorchid": {186, 85, 211, 255},
+	"mediumpurple": {147, 112, 219, 255}, "mediumseagreen": {60, 179, 113, 255}, "mediumslateblue": {123, 104, 238, 255},
+	"mediumspringgreen": {0, 250, 154, 255}, "mediumturquoise": {72, 209, 204, 255}, "mediumvioletred": {199, 21, 133, 255},
+	"midnightblue": {25, 25, 112, 255}, "mintcream": {245, 255, 250, 255}, "mistyrose": {255, 228, 225, 255},
+	"moccasin": {255, 228, 181, 255}, "navajowhite": {255, 222, 173, 255}, "navy": {0, 0, 128, 255},
+	"oldlace": {253, 245, 230, 255}, "olive": {128, 128, 0, 255}, "olivedrab": {107, 142, 35, 255},
+	"orange": {255, 165, 0, 255}, "orangered": {255, 69, 0, 255}, "orchid": {218, 112, 214, 255},
+	"palegoldenrod": {238, 232, 170, 255}, "palegreen": {152, 251, 152, 255}, "paleturquoise": {175, 238, 238, 255},
+	"palevioletred": {219, 112, 147, 255}, "papayawhip": {255, 239, 213, 255}, "peachpuff": {255, 218, 185, 255},
+	"peru": {205, 133, 63, 255}, "pink": {255, 192, 203, 255}, "plum": {221, 160, 221, 255},
+	"powderblue": {176, 224, 230, 255}, "purple": {128, 0, 128, 255}, "rebeccapurple": {102, 51, 153, 255},
+	"red": {255, 0, 0, 255}, "rosybrown": {188, 143, 143, 255}, "royalblue": {65, 105, 225, 255},
+	"saddlebrown": {139, 69, 19, 255}, "salmon": {250, 128, 114, 255}, "sandybrown": {244, 164, 96, 255},
+	"seagreen": {46, 139, 87, 255}, "seashell": {255, 245, 238, 255}, "sienna": {160, 82, 45, 255},
+	"silver": {192, 192, 192, 255}, "skyblue": {135, 206, 235, 255}, "slateblue": {106, 90, 205, 255},
+	"slategray": {112, 128, 144, 255}, "slategrey": {112, 128, 144, 255}, "snow": {255, 250, 250, 255},
+	"springgreen": {0, 255, 127, 255}, "steelblue": {70, 130, 180, 255}, "tan": {210, 180, 140, 255},
+	"teal": {0, 128, 128, 255}, "thistle": {216, 191, 216, 255}, "tomato": {255, 99, 71, 255},
+	"turquoise": {64, 224, 208, 255}, "violet": {238, 130, 238, 255}, "wheat": {245, 222, 179, 255},
+	"white": {255, 255, 255, 255}, "whitesmoke": {245, 245, 245, 255}, "yellow": {255, 255, 0, 255},
+	"yellowgreen": {154, 205, 50, 255},
+}
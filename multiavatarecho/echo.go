@@ -0,0 +1,32 @@
+// Package multiavatarecho exposes the multiavatarhttp handlers as Echo handlers.
+//
+//	e := echo.New()
+//	e.GET("/avatar", multiavatarecho.Handler())
+//	e.GET("/avatars/:name", multiavatarecho.PathHandler("name"))
+//
+// Echo path parameters span a whole segment, so /avatars/alice.svg matches
+// ":name" and the ".svg" extension is stripped by the handler.
+package multiavatarecho
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/changzee/multiavatar-go/multiavatarhttp"
+)
+
+// Handler serves `?name=...` requests like multiavatarhttp.NewHandler.
+func Handler(opts ...multiavatarhttp.HandlerOption) echo.HandlerFunc {
+	return echo.WrapHandler(multiavatarhttp.NewHandler(opts...))
+}
+
+// PathHandler serves the avatar named by the path parameter param, like
+// multiavatarhttp.NewPathHandler.
+func PathHandler(param string, opts ...multiavatarhttp.HandlerOption) echo.HandlerFunc {
+	h := multiavatarhttp.NewPathHandler(param, opts...)
+	return func(c echo.Context) error {
+		r := c.Request()
+		r.SetPathValue(param, c.Param(param))
+		h.ServeHTTP(c.Response(), r)
+		return nil
+	}
+}
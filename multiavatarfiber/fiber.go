@@ -0,0 +1,37 @@
+// Package multiavatarfiber exposes the multiavatarhttp handlers as Fiber handlers.
+//
+//	app := fiber.New()
+//	app.Get("/avatar", multiavatarfiber.Handler())
+//	app.Get("/avatars/:name.svg", multiavatarfiber.PathHandler("name"))
+//
+// Requests are converted to net/http by Fiber's adaptor middleware.
+package multiavatarfiber
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/changzee/multiavatar-go/multiavatarhttp"
+)
+
+// Handler serves `?name=...` requests like multiavatarhttp.NewHandler.
+func Handler(opts ...multiavatarhttp.HandlerOption) fiber.Handler {
+	return adaptor.HTTPHandler(multiavatarhttp.NewHandler(opts...))
+}
+
+// PathHandler serves the avatar named by the path parameter param, like
+// multiavatarhttp.NewPathHandler.
+func PathHandler(param string, opts ...multiavatarhttp.HandlerOption) fiber.Handler {
+	h := multiavatarhttp.NewPathHandler(param, opts...)
+	return func(c *fiber.Ctx) error {
+		// copy: Fiber reuses the parameter's memory after the handler returns
+		name := strings.Clone(c.Params(param))
+		return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.SetPathValue(param, name)
+			h.ServeHTTP(w, r)
+		})(c)
+	}
+}
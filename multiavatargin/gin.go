@@ -0,0 +1,30 @@
+// Package multiavatargin exposes the multiavatarhttp handlers as Gin handlers.
+//
+//	r := gin.Default()
+//	r.GET("/avatar", multiavatargin.Handler())
+//	r.GET("/avatars/:name", multiavatargin.PathHandler("name"))
+//
+// Gin path parameters span a whole segment, so /avatars/alice.svg matches
+// ":name" and the ".svg" extension is stripped by the handler.
+package multiavatargin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changzee/multiavatar-go/multiavatarhttp"
+)
+
+// Handler serves `?name=...` requests like multiavatarhttp.NewHandler.
+func Handler(opts ...multiavatarhttp.HandlerOption) gin.HandlerFunc {
+	return gin.WrapH(multiavatarhttp.NewHandler(opts...))
+}
+
+// PathHandler serves the avatar named by the path parameter param, like
+// multiavatarhttp.NewPathHandler.
+func PathHandler(param string, opts ...multiavatarhttp.HandlerOption) gin.HandlerFunc {
+	h := multiavatarhttp.NewPathHandler(param, opts...)
+	return func(c *gin.Context) {
+		c.Request.SetPathValue(param, c.Param(param))
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,1074 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: avatar.proto
+
+package multiavatargrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Format is the encoding of the returned image.
+type Format int32
+
+const (
+	Format_FORMAT_UNSPECIFIED Format = 0 // SVG
+	Format_FORMAT_SVG         Format = 1
+	Format_FORMAT_PNG         Format = 2
+)
+
+// Enum value maps for Format.
+var (
+	Format_name = map[int32]string{
+		0: "FORMAT_UNSPECIFIED",
+		1: "FORMAT_SVG",
+		2: "FORMAT_PNG",
+	}
+	Format_value = map[string]int32{
+		"FORMAT_UNSPECIFIED": 0,
+		"FORMAT_SVG":         1,
+		"FORMAT_PNG":         2,
+	}
+)
+
+func (x Format) Enum() *Format {
+	p := new(Format)
+	*p = x
+	return p
+}
+
+func (x Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_avatar_proto_enumTypes[0].Descriptor()
+}
+
+func (Format) Type() protoreflect.EnumType {
+	return &file_avatar_proto_enumTypes[0]
+}
+
+func (x Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Format.Descriptor instead.
+func (Format) EnumDescriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{0}
+}
+
+type Badge_Kind int32
+
+const (
+	Badge_KIND_UNSPECIFIED Badge_Kind = 0
+	Badge_KIND_ONLINE      Badge_Kind = 1
+	Badge_KIND_BUSY        Badge_Kind = 2
+	Badge_KIND_AWAY        Badge_Kind = 3
+	Badge_KIND_CUSTOM      Badge_Kind = 4
+)
+
+// Enum value maps for Badge_Kind.
+var (
+	Badge_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_ONLINE",
+		2: "KIND_BUSY",
+		3: "KIND_AWAY",
+		4: "KIND_CUSTOM",
+	}
+	Badge_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_ONLINE":      1,
+		"KIND_BUSY":        2,
+		"KIND_AWAY":        3,
+		"KIND_CUSTOM":      4,
+	}
+)
+
+func (x Badge_Kind) Enum() *Badge_Kind {
+	p := new(Badge_Kind)
+	*p = x
+	return p
+}
+
+func (x Badge_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Badge_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_avatar_proto_enumTypes[1].Descriptor()
+}
+
+func (Badge_Kind) Type() protoreflect.EnumType {
+	return &file_avatar_proto_enumTypes[1]
+}
+
+func (x Badge_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Badge_Kind.Descriptor instead.
+func (Badge_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type Badge_Corner int32
+
+const (
+	Badge_CORNER_BOTTOM_RIGHT Badge_Corner = 0
+	Badge_CORNER_BOTTOM_LEFT  Badge_Corner = 1
+	Badge_CORNER_TOP_RIGHT    Badge_Corner = 2
+	Badge_CORNER_TOP_LEFT     Badge_Corner = 3
+)
+
+// Enum value maps for Badge_Corner.
+var (
+	Badge_Corner_name = map[int32]string{
+		0: "CORNER_BOTTOM_RIGHT",
+		1: "CORNER_BOTTOM_LEFT",
+		2: "CORNER_TOP_RIGHT",
+		3: "CORNER_TOP_LEFT",
+	}
+	Badge_Corner_value = map[string]int32{
+		"CORNER_BOTTOM_RIGHT": 0,
+		"CORNER_BOTTOM_LEFT":  1,
+		"CORNER_TOP_RIGHT":    2,
+		"CORNER_TOP_LEFT":     3,
+	}
+)
+
+func (x Badge_Corner) Enum() *Badge_Corner {
+	p := new(Badge_Corner)
+	*p = x
+	return p
+}
+
+func (x Badge_Corner) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Badge_Corner) Descriptor() protoreflect.EnumDescriptor {
+	return file_avatar_proto_enumTypes[2].Descriptor()
+}
+
+func (Badge_Corner) Type() protoreflect.EnumType {
+	return &file_avatar_proto_enumTypes[2]
+}
+
+func (x Badge_Corner) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Badge_Corner.Descriptor instead.
+func (Badge_Corner) EnumDescriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{8, 1}
+}
+
+// GenerateRequest selects the avatar and its customizations. Every field of
+// Options mirrors the functional option of the same name.
+type GenerateRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Seed   string                 `protobuf:"bytes,1,opt,name=seed,proto3" json:"seed,omitempty"`
+	Format Format                 `protobuf:"varint,2,opt,name=format,proto3,enum=multiavatar.v1.Format" json:"format,omitempty"`
+	// Size is the PNG width and height in pixels (default 256), or the SVG
+	// width and height attributes when set.
+	Size          int32    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Options       *Options `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_avatar_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateRequest) GetSeed() string {
+	if x != nil {
+		return x.Seed
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetFormat() Format {
+	if x != nil {
+		return x.Format
+	}
+	return Format_FORMAT_UNSPECIFIED
+}
+
+func (x *GenerateRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GenerateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Content-Type of data: "image/svg+xml" or "image/png".
+	ContentType   string `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_avatar_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *GenerateResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type StringList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringList) Reset() {
+	*x = StringList{}
+	mi := &file_avatar_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringList) ProtoMessage() {}
+
+func (x *StringList) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringList.ProtoReflect.Descriptor instead.
+func (*StringList) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StringList) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type Options struct {
+	state              protoimpl.MessageState    `protogen:"open.v1"`
+	Style              string                    `protobuf:"bytes,1,opt,name=style,proto3" json:"style,omitempty"`
+	Theme              string                    `protobuf:"bytes,2,opt,name=theme,proto3" json:"theme,omitempty"`
+	Gender             string                    `protobuf:"bytes,3,opt,name=gender,proto3" json:"gender,omitempty"`
+	Expression         string                    `protobuf:"bytes,4,opt,name=expression,proto3" json:"expression,omitempty"`
+	WithoutBackground  bool                      `protobuf:"varint,5,opt,name=without_background,json=withoutBackground,proto3" json:"without_background,omitempty"`
+	PartVersions       map[string]string         `protobuf:"bytes,6,rep,name=part_versions,json=partVersions,proto3" json:"part_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	AllowedVersions    map[string]*StringList    `protobuf:"bytes,7,rep,name=allowed_versions,json=allowedVersions,proto3" json:"allowed_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PartThemes         map[string]string         `protobuf:"bytes,8,rep,name=part_themes,json=partThemes,proto3" json:"part_themes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	AllowedThemes      map[string]*StringList    `protobuf:"bytes,9,rep,name=allowed_themes,json=allowedThemes,proto3" json:"allowed_themes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Colors             map[string]*StringList    `protobuf:"bytes,10,rep,name=colors,proto3" json:"colors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	WithoutParts       []string                  `protobuf:"bytes,11,rep,name=without_parts,json=withoutParts,proto3" json:"without_parts,omitempty"`
+	LayerOrder         []string                  `protobuf:"bytes,12,rep,name=layer_order,json=layerOrder,proto3" json:"layer_order,omitempty"`
+	PartTransforms     map[string]*PartTransform `protobuf:"bytes,13,rep,name=part_transforms,json=partTransforms,proto3" json:"part_transforms,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Padding            float64                   `protobuf:"fixed64,14,opt,name=padding,proto3" json:"padding,omitempty"`
+	ViewBox            *ViewBox                  `protobuf:"bytes,15,opt,name=view_box,json=viewBox,proto3" json:"view_box,omitempty"`
+	BackgroundGradient *Gradient                 `protobuf:"bytes,16,opt,name=background_gradient,json=backgroundGradient,proto3" json:"background_gradient,omitempty"`
+	Border             *Border                   `protobuf:"bytes,17,opt,name=border,proto3" json:"border,omitempty"`
+	Badge              *Badge                    `protobuf:"bytes,18,opt,name=badge,proto3" json:"badge,omitempty"`
+	ClothingLogo       string                    `protobuf:"bytes,19,opt,name=clothing_logo,json=clothingLogo,proto3" json:"clothing_logo,omitempty"`
+	// Saturation scales color saturation when set; 0 is grayscale.
+	Saturation         *float64 `protobuf:"fixed64,20,opt,name=saturation,proto3,oneof" json:"saturation,omitempty"`
+	EmailNormalization bool     `protobuf:"varint,21,opt,name=email_normalization,json=emailNormalization,proto3" json:"email_normalization,omitempty"`
+	CompatV1           bool     `protobuf:"varint,22,opt,name=compat_v1,json=compatV1,proto3" json:"compat_v1,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_avatar_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Options) GetStyle() string {
+	if x != nil {
+		return x.Style
+	}
+	return ""
+}
+
+func (x *Options) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *Options) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+func (x *Options) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *Options) GetWithoutBackground() bool {
+	if x != nil {
+		return x.WithoutBackground
+	}
+	return false
+}
+
+func (x *Options) GetPartVersions() map[string]string {
+	if x != nil {
+		return x.PartVersions
+	}
+	return nil
+}
+
+func (x *Options) GetAllowedVersions() map[string]*StringList {
+	if x != nil {
+		return x.AllowedVersions
+	}
+	return nil
+}
+
+func (x *Options) GetPartThemes() map[string]string {
+	if x != nil {
+		return x.PartThemes
+	}
+	return nil
+}
+
+func (x *Options) GetAllowedThemes() map[string]*StringList {
+	if x != nil {
+		return x.AllowedThemes
+	}
+	return nil
+}
+
+func (x *Options) GetColors() map[string]*StringList {
+	if x != nil {
+		return x.Colors
+	}
+	return nil
+}
+
+func (x *Options) GetWithoutParts() []string {
+	if x != nil {
+		return x.WithoutParts
+	}
+	return nil
+}
+
+func (x *Options) GetLayerOrder() []string {
+	if x != nil {
+		return x.LayerOrder
+	}
+	return nil
+}
+
+func (x *Options) GetPartTransforms() map[string]*PartTransform {
+	if x != nil {
+		return x.PartTransforms
+	}
+	return nil
+}
+
+func (x *Options) GetPadding() float64 {
+	if x != nil {
+		return x.Padding
+	}
+	return 0
+}
+
+func (x *Options) GetViewBox() *ViewBox {
+	if x != nil {
+		return x.ViewBox
+	}
+	return nil
+}
+
+func (x *Options) GetBackgroundGradient() *Gradient {
+	if x != nil {
+		return x.BackgroundGradient
+	}
+	return nil
+}
+
+func (x *Options) GetBorder() *Border {
+	if x != nil {
+		return x.Border
+	}
+	return nil
+}
+
+func (x *Options) GetBadge() *Badge {
+	if x != nil {
+		return x.Badge
+	}
+	return nil
+}
+
+func (x *Options) GetClothingLogo() string {
+	if x != nil {
+		return x.ClothingLogo
+	}
+	return ""
+}
+
+func (x *Options) GetSaturation() float64 {
+	if x != nil && x.Saturation != nil {
+		return *x.Saturation
+	}
+	return 0
+}
+
+func (x *Options) GetEmailNormalization() bool {
+	if x != nil {
+		return x.EmailNormalization
+	}
+	return false
+}
+
+func (x *Options) GetCompatV1() bool {
+	if x != nil {
+		return x.CompatV1
+	}
+	return false
+}
+
+type PartTransform struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scale         float64                `protobuf:"fixed64,1,opt,name=scale,proto3" json:"scale,omitempty"`
+	Dx            float64                `protobuf:"fixed64,2,opt,name=dx,proto3" json:"dx,omitempty"`
+	Dy            float64                `protobuf:"fixed64,3,opt,name=dy,proto3" json:"dy,omitempty"`
+	Rotate        float64                `protobuf:"fixed64,4,opt,name=rotate,proto3" json:"rotate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartTransform) Reset() {
+	*x = PartTransform{}
+	mi := &file_avatar_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartTransform) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartTransform) ProtoMessage() {}
+
+func (x *PartTransform) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartTransform.ProtoReflect.Descriptor instead.
+func (*PartTransform) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PartTransform) GetScale() float64 {
+	if x != nil {
+		return x.Scale
+	}
+	return 0
+}
+
+func (x *PartTransform) GetDx() float64 {
+	if x != nil {
+		return x.Dx
+	}
+	return 0
+}
+
+func (x *PartTransform) GetDy() float64 {
+	if x != nil {
+		return x.Dy
+	}
+	return 0
+}
+
+func (x *PartTransform) GetRotate() float64 {
+	if x != nil {
+		return x.Rotate
+	}
+	return 0
+}
+
+type ViewBox struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             float64                `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             float64                `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Width         float64                `protobuf:"fixed64,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height        float64                `protobuf:"fixed64,4,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ViewBox) Reset() {
+	*x = ViewBox{}
+	mi := &file_avatar_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ViewBox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ViewBox) ProtoMessage() {}
+
+func (x *ViewBox) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ViewBox.ProtoReflect.Descriptor instead.
+func (*ViewBox) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ViewBox) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *ViewBox) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *ViewBox) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ViewBox) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type Gradient struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	From  string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To    string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Angle in degrees for linear gradients; ignored when radial.
+	Angle         float64 `protobuf:"fixed64,3,opt,name=angle,proto3" json:"angle,omitempty"`
+	Radial        bool    `protobuf:"varint,4,opt,name=radial,proto3" json:"radial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Gradient) Reset() {
+	*x = Gradient{}
+	mi := &file_avatar_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Gradient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gradient) ProtoMessage() {}
+
+func (x *Gradient) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gradient.ProtoReflect.Descriptor instead.
+func (*Gradient) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Gradient) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Gradient) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Gradient) GetAngle() float64 {
+	if x != nil {
+		return x.Angle
+	}
+	return 0
+}
+
+func (x *Gradient) GetRadial() bool {
+	if x != nil {
+		return x.Radial
+	}
+	return false
+}
+
+type Border struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Color         string                 `protobuf:"bytes,1,opt,name=color,proto3" json:"color,omitempty"`
+	Width         float64                `protobuf:"fixed64,2,opt,name=width,proto3" json:"width,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Border) Reset() {
+	*x = Border{}
+	mi := &file_avatar_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Border) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Border) ProtoMessage() {}
+
+func (x *Border) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Border.ProtoReflect.Descriptor instead.
+func (*Border) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Border) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Border) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+type Badge struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Kind   Badge_Kind             `protobuf:"varint,1,opt,name=kind,proto3,enum=multiavatar.v1.Badge_Kind" json:"kind,omitempty"`
+	Corner Badge_Corner           `protobuf:"varint,2,opt,name=corner,proto3,enum=multiavatar.v1.Badge_Corner" json:"corner,omitempty"`
+	// Svg is the fragment drawn by KIND_CUSTOM badges.
+	Svg           string `protobuf:"bytes,3,opt,name=svg,proto3" json:"svg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Badge) Reset() {
+	*x = Badge{}
+	mi := &file_avatar_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Badge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Badge) ProtoMessage() {}
+
+func (x *Badge) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Badge.ProtoReflect.Descriptor instead.
+func (*Badge) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Badge) GetKind() Badge_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Badge_KIND_UNSPECIFIED
+}
+
+func (x *Badge) GetCorner() Badge_Corner {
+	if x != nil {
+		return x.Corner
+	}
+	return Badge_CORNER_BOTTOM_RIGHT
+}
+
+func (x *Badge) GetSvg() string {
+	if x != nil {
+		return x.Svg
+	}
+	return ""
+}
+
+var File_avatar_proto protoreflect.FileDescriptor
+
+const file_avatar_proto_rawDesc = "" +
+	"\n" +
+	"\favatar.proto\x12\x0emultiavatar.v1\"\x9c\x01\n" +
+	"\x0fGenerateRequest\x12\x12\n" +
+	"\x04seed\x18\x01 \x01(\tR\x04seed\x12.\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x16.multiavatar.v1.FormatR\x06format\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x121\n" +
+	"\aoptions\x18\x04 \x01(\v2\x17.multiavatar.v1.OptionsR\aoptions\"I\n" +
+	"\x10GenerateResponse\x12!\n" +
+	"\fcontent_type\x18\x01 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"$\n" +
+	"\n" +
+	"StringList\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"\xcf\f\n" +
+	"\aOptions\x12\x14\n" +
+	"\x05style\x18\x01 \x01(\tR\x05style\x12\x14\n" +
+	"\x05theme\x18\x02 \x01(\tR\x05theme\x12\x16\n" +
+	"\x06gender\x18\x03 \x01(\tR\x06gender\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x04 \x01(\tR\n" +
+	"expression\x12-\n" +
+	"\x12without_background\x18\x05 \x01(\bR\x11withoutBackground\x12N\n" +
+	"\rpart_versions\x18\x06 \x03(\v2).multiavatar.v1.Options.PartVersionsEntryR\fpartVersions\x12W\n" +
+	"\x10allowed_versions\x18\a \x03(\v2,.multiavatar.v1.Options.AllowedVersionsEntryR\x0fallowedVersions\x12H\n" +
+	"\vpart_themes\x18\b \x03(\v2'.multiavatar.v1.Options.PartThemesEntryR\n" +
+	"partThemes\x12Q\n" +
+	"\x0eallowed_themes\x18\t \x03(\v2*.multiavatar.v1.Options.AllowedThemesEntryR\rallowedThemes\x12;\n" +
+	"\x06colors\x18\n" +
+	" \x03(\v2#.multiavatar.v1.Options.ColorsEntryR\x06colors\x12#\n" +
+	"\rwithout_parts\x18\v \x03(\tR\fwithoutParts\x12\x1f\n" +
+	"\vlayer_order\x18\f \x03(\tR\n" +
+	"layerOrder\x12T\n" +
+	"\x0fpart_transforms\x18\r \x03(\v2+.multiavatar.v1.Options.PartTransformsEntryR\x0epartTransforms\x12\x18\n" +
+	"\apadding\x18\x0e \x01(\x01R\apadding\x122\n" +
+	"\bview_box\x18\x0f \x01(\v2\x17.multiavatar.v1.ViewBoxR\aviewBox\x12I\n" +
+	"\x13background_gradient\x18\x10 \x01(\v2\x18.multiavatar.v1.GradientR\x12backgroundGradient\x12.\n" +
+	"\x06border\x18\x11 \x01(\v2\x16.multiavatar.v1.BorderR\x06border\x12+\n" +
+	"\x05badge\x18\x12 \x01(\v2\x15.multiavatar.v1.BadgeR\x05badge\x12#\n" +
+	"\rclothing_logo\x18\x13 \x01(\tR\fclothingLogo\x12#\n" +
+	"\n" +
+	"saturation\x18\x14 \x01(\x01H\x00R\n" +
+	"saturation\x88\x01\x01\x12/\n" +
+	"\x13email_normalization\x18\x15 \x01(\bR\x12emailNormalization\x12\x1b\n" +
+	"\tcompat_v1\x18\x16 \x01(\bR\bcompatV1\x1a?\n" +
+	"\x11PartVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a^\n" +
+	"\x14AllowedVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.multiavatar.v1.StringListR\x05value:\x028\x01\x1a=\n" +
+	"\x0fPartThemesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\\\n" +
+	"\x12AllowedThemesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.multiavatar.v1.StringListR\x05value:\x028\x01\x1aU\n" +
+	"\vColorsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.multiavatar.v1.StringListR\x05value:\x028\x01\x1a`\n" +
+	"\x13PartTransformsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x123\n" +
+	"\x05value\x18\x02 \x01(\v2\x1d.multiavatar.v1.PartTransformR\x05value:\x028\x01B\r\n" +
+	"\v_saturation\"]\n" +
+	"\rPartTransform\x12\x14\n" +
+	"\x05scale\x18\x01 \x01(\x01R\x05scale\x12\x0e\n" +
+	"\x02dx\x18\x02 \x01(\x01R\x02dx\x12\x0e\n" +
+	"\x02dy\x18\x03 \x01(\x01R\x02dy\x12\x16\n" +
+	"\x06rotate\x18\x04 \x01(\x01R\x06rotate\"S\n" +
+	"\aViewBox\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x01R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x01R\x01y\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\x01R\x05width\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x01R\x06height\"\\\n" +
+	"\bGradient\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x14\n" +
+	"\x05angle\x18\x03 \x01(\x01R\x05angle\x12\x16\n" +
+	"\x06radial\x18\x04 \x01(\bR\x06radial\"4\n" +
+	"\x06Border\x12\x14\n" +
+	"\x05color\x18\x01 \x01(\tR\x05color\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x01R\x05width\"\xc3\x02\n" +
+	"\x05Badge\x12.\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1a.multiavatar.v1.Badge.KindR\x04kind\x124\n" +
+	"\x06corner\x18\x02 \x01(\x0e2\x1c.multiavatar.v1.Badge.CornerR\x06corner\x12\x10\n" +
+	"\x03svg\x18\x03 \x01(\tR\x03svg\"\\\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vKIND_ONLINE\x10\x01\x12\r\n" +
+	"\tKIND_BUSY\x10\x02\x12\r\n" +
+	"\tKIND_AWAY\x10\x03\x12\x0f\n" +
+	"\vKIND_CUSTOM\x10\x04\"d\n" +
+	"\x06Corner\x12\x17\n" +
+	"\x13CORNER_BOTTOM_RIGHT\x10\x00\x12\x16\n" +
+	"\x12CORNER_BOTTOM_LEFT\x10\x01\x12\x14\n" +
+	"\x10CORNER_TOP_RIGHT\x10\x02\x12\x13\n" +
+	"\x0fCORNER_TOP_LEFT\x10\x03*@\n" +
+	"\x06Format\x12\x16\n" +
+	"\x12FORMAT_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"FORMAT_SVG\x10\x01\x12\x0e\n" +
+	"\n" +
+	"FORMAT_PNG\x10\x022^\n" +
+	"\rAvatarService\x12M\n" +
+	"\bGenerate\x12\x1f.multiavatar.v1.GenerateRequest\x1a .multiavatar.v1.GenerateResponseB4Z2github.com/changzee/multiavatar-go/multiavatargrpcb\x06proto3"
+
+var (
+	file_avatar_proto_rawDescOnce sync.Once
+	file_avatar_proto_rawDescData []byte
+)
+
+func file_avatar_proto_rawDescGZIP() []byte {
+	file_avatar_proto_rawDescOnce.Do(func() {
+		file_avatar_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_avatar_proto_rawDesc), len(file_avatar_proto_rawDesc)))
+	})
+	return file_avatar_proto_rawDescData
+}
+
+var file_avatar_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_avatar_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_avatar_proto_goTypes = []any{
+	(Format)(0),              // 0: multiavatar.v1.Format
+	(Badge_Kind)(0),          // 1: multiavatar.v1.Badge.Kind
+	(Badge_Corner)(0),        // 2: multiavatar.v1.Badge.Corner
+	(*GenerateRequest)(nil),  // 3: multiavatar.v1.GenerateRequest
+	(*GenerateResponse)(nil), // 4: multiavatar.v1.GenerateResponse
+	(*StringList)(nil),       // 5: multiavatar.v1.StringList
+	(*Options)(nil),          // 6: multiavatar.v1.Options
+	(*PartTransform)(nil),    // 7: multiavatar.v1.PartTransform
+	(*ViewBox)(nil),          // 8: multiavatar.v1.ViewBox
+	(*Gradient)(nil),         // 9: multiavatar.v1.Gradient
+	(*Border)(nil),           // 10: multiavatar.v1.Border
+	(*Badge)(nil),            // 11: multiavatar.v1.Badge
+	nil,                      // 12: multiavatar.v1.Options.PartVersionsEntry
+	nil,                      // 13: multiavatar.v1.Options.AllowedVersionsEntry
+	nil,                      // 14: multiavatar.v1.Options.PartThemesEntry
+	nil,                      // 15: multiavatar.v1.Options.AllowedThemesEntry
+	nil,                      // 16: multiavatar.v1.Options.ColorsEntry
+	nil,                      // 17: multiavatar.v1.Options.PartTransformsEntry
+}
+var file_avatar_proto_depIdxs = []int32{
+	0,  // 0: multiavatar.v1.GenerateRequest.format:type_name -> multiavatar.v1.Format
+	6,  // 1: multiavatar.v1.GenerateRequest.options:type_name -> multiavatar.v1.Options
+	12, // 2: multiavatar.v1.Options.part_versions:type_name -> multiavatar.v1.Options.PartVersionsEntry
+	13, // 3: multiavatar.v1.Options.allowed_versions:type_name -> multiavatar.v1.Options.AllowedVersionsEntry
+	14, // 4: multiavatar.v1.Options.part_themes:type_name -> multiavatar.v1.Options.PartThemesEntry
+	15, // 5: multiavatar.v1.Options.allowed_themes:type_name -> multiavatar.v1.Options.AllowedThemesEntry
+	16, // 6: multiavatar.v1.Options.colors:type_name -> multiavatar.v1.Options.ColorsEntry
+	17, // 7: multiavatar.v1.Options.part_transforms:type_name -> multiavatar.v1.Options.PartTransformsEntry
+	8,  // 8: multiavatar.v1.Options.view_box:type_name -> multiavatar.v1.ViewBox
+	9,  // 9: multiavatar.v1.Options.background_gradient:type_name -> multiavatar.v1.Gradient
+	10, // 10: multiavatar.v1.Options.border:type_name -> multiavatar.v1.Border
+	11, // 11: multiavatar.v1.Options.badge:type_name -> multiavatar.v1.Badge
+	1,  // 12: multiavatar.v1.Badge.kind:type_name -> multiavatar.v1.Badge.Kind
+	2,  // 13: multiavatar.v1.Badge.corner:type_name -> multiavatar.v1.Badge.Corner
+	5,  // 14: multiavatar.v1.Options.AllowedVersionsEntry.value:type_name -> multiavatar.v1.StringList
+	5,  // 15: multiavatar.v1.Options.AllowedThemesEntry.value:type_name -> multiavatar.v1.StringList
+	5,  // 16: multiavatar.v1.Options.ColorsEntry.value:type_name -> multiavatar.v1.StringList
+	7,  // 17: multiavatar.v1.Options.PartTransformsEntry.value:type_name -> multiavatar.v1.PartTransform
+	3,  // 18: multiavatar.v1.AvatarService.Generate:input_type -> multiavatar.v1.GenerateRequest
+	4,  // 19: multiavatar.v1.AvatarService.Generate:output_type -> multiavatar.v1.GenerateResponse
+	19, // [19:20] is the sub-list for method output_type
+	18, // [18:19] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_avatar_proto_init() }
+func file_avatar_proto_init() {
+	if File_avatar_proto != nil {
+		return
+	}
+	file_avatar_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_avatar_proto_rawDesc), len(file_avatar_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_avatar_proto_goTypes,
+		DependencyIndexes: file_avatar_proto_depIdxs,
+		EnumInfos:         file_avatar_proto_enumTypes,
+		MessageInfos:      file_avatar_proto_msgTypes,
+	}.Build()
+	File_avatar_proto = out.File
+	file_avatar_proto_goTypes = nil
+	file_avatar_proto_depIdxs = nil
+}
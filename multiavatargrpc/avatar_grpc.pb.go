@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: avatar.proto
+
+package multiavatargrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AvatarService_Generate_FullMethodName = "/multiavatar.v1.AvatarService/Generate"
+)
+
+// AvatarServiceClient is the client API for AvatarService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AvatarService renders multiavatar avatars.
+type AvatarServiceClient interface {
+	// Generate renders the avatar for one seed.
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+}
+
+type avatarServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAvatarServiceClient(cc grpc.ClientConnInterface) AvatarServiceClient {
+	return &avatarServiceClient{cc}
+}
+
+func (c *avatarServiceClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, AvatarService_Generate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AvatarServiceServer is the server API for AvatarService service.
+// All implementations must embed UnimplementedAvatarServiceServer
+// for forward compatibility.
+//
+// AvatarService renders multiavatar avatars.
+type AvatarServiceServer interface {
+	// Generate renders the avatar for one seed.
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	mustEmbedUnimplementedAvatarServiceServer()
+}
+
+// UnimplementedAvatarServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAvatarServiceServer struct{}
+
+func (UnimplementedAvatarServiceServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedAvatarServiceServer) mustEmbedUnimplementedAvatarServiceServer() {}
+func (UnimplementedAvatarServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeAvatarServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AvatarServiceServer will
+// result in compilation errors.
+type UnsafeAvatarServiceServer interface {
+	mustEmbedUnimplementedAvatarServiceServer()
+}
+
+func RegisterAvatarServiceServer(s grpc.ServiceRegistrar, srv AvatarServiceServer) {
+	// If the following call panics, it indicates UnimplementedAvatarServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AvatarService_ServiceDesc, srv)
+}
+
+func _AvatarService_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AvatarServiceServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AvatarService_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AvatarServiceServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AvatarService_ServiceDesc is the grpc.ServiceDesc for AvatarService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AvatarService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "multiavatar.v1.AvatarService",
+	HandlerType: (*AvatarServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _AvatarService_Generate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "avatar.proto",
+}
@@ -0,0 +1,175 @@
+// Package multiavatargrpc serves multiavatar avatars over gRPC, so internal
+// services can request avatars without encoding options into query strings.
+//
+// The service is defined in avatar.proto. Register it with:
+//
+//	s := grpc.NewServer()
+//	multiavatargrpc.RegisterAvatarServiceServer(s, multiavatargrpc.NewServer())
+//
+// Regenerate the protobuf code after editing avatar.proto with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative avatar.proto
+package multiavatargrpc
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"sort"
+
+	"github.com/changzee/multiavatar-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPNGSize is the PNG size used when a request does not set one.
+const defaultPNGSize = 256
+
+// Server implements AvatarServiceServer.
+// It is safe for concurrent use.
+type Server struct {
+	UnimplementedAvatarServiceServer
+	// opts are applied before the per-request options.
+	opts []multiavatar.Option
+}
+
+// NewServer returns a server that applies opts to every request, before the
+// options carried by the request itself.
+func NewServer(opts ...multiavatar.Option) *Server {
+	return &Server{opts: opts}
+}
+
+// Generate implements AvatarServiceServer. Invalid option values are
+// reported with codes.InvalidArgument.
+func (s *Server) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if req.GetSeed() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing seed")
+	}
+	opts := make([]multiavatar.Option, 0, len(s.opts))
+	opts = append(opts, s.opts...)
+	opts = append(opts, ToOptions(req.GetOptions())...)
+
+	switch req.GetFormat() {
+	case Format_FORMAT_PNG:
+		size := int(req.GetSize())
+		if size == 0 {
+			size = defaultPNGSize
+		}
+		img, err := multiavatar.GenerateImageContext(ctx, req.GetSeed(), size, opts...)
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &GenerateResponse{ContentType: "image/png", Data: buf.Bytes()}, nil
+	case Format_FORMAT_UNSPECIFIED, Format_FORMAT_SVG:
+		if req.GetSize() > 0 {
+			opts = append(opts, multiavatar.WithSize(int(req.GetSize())))
+		}
+		var buf bytes.Buffer
+		if err := multiavatar.GenerateTo(&buf, req.GetSeed(), opts...); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return &GenerateResponse{ContentType: "image/svg+xml", Data: buf.Bytes()}, nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "unknown format %v", req.GetFormat())
+}
+
+// ToOptions converts the protobuf options into functional options. Map
+// entries are applied in key order so the result is deterministic.
+func ToOptions(o *Options) []multiavatar.Option {
+	if o == nil {
+		return nil
+	}
+	base := multiavatar.Options{
+		Style:             o.GetStyle(),
+		Theme:             o.GetTheme(),
+		Gender:            o.GetGender(),
+		Expression:        o.GetExpression(),
+		WithoutBackground: o.GetWithoutBackground(),
+		PartVersions:      o.GetPartVersions(),
+		AllowedVersions:   lists(o.GetAllowedVersions()),
+		PartThemes:        o.GetPartThemes(),
+		AllowedThemes:     lists(o.GetAllowedThemes()),
+		Colors:            lists(o.GetColors()),
+		WithoutParts:      o.GetWithoutParts(),
+	}
+	opts := base.ToOptions()
+
+	if len(o.GetLayerOrder()) > 0 {
+		opts = append(opts, multiavatar.WithLayerOrder(o.GetLayerOrder()...))
+	}
+	transforms := o.GetPartTransforms()
+	for _, part := range sortedKeys(transforms) {
+		t := transforms[part]
+		opts = append(opts, multiavatar.WithPartTransform(part, t.GetScale(), t.GetDx(), t.GetDy(), t.GetRotate()))
+	}
+	if o.GetPadding() != 0 {
+		opts = append(opts, multiavatar.WithPadding(o.GetPadding()))
+	}
+	if vb := o.GetViewBox(); vb != nil {
+		opts = append(opts, multiavatar.WithViewBox(vb.GetX(), vb.GetY(), vb.GetWidth(), vb.GetHeight()))
+	}
+	if g := o.GetBackgroundGradient(); g != nil {
+		if g.GetRadial() {
+			opts = append(opts, multiavatar.WithRadialBackgroundGradient(g.GetFrom(), g.GetTo()))
+		} else {
+			opts = append(opts, multiavatar.WithBackgroundGradient(g.GetFrom(), g.GetTo(), g.GetAngle()))
+		}
+	}
+	if b := o.GetBorder(); b != nil {
+		opts = append(opts, multiavatar.WithBorder(b.GetColor(), b.GetWidth()))
+	}
+	if b := o.GetBadge(); b != nil {
+		badge := map[Badge_Kind]multiavatar.Badge{
+			Badge_KIND_ONLINE: multiavatar.BadgeOnline,
+			Badge_KIND_BUSY:   multiavatar.BadgeBusy,
+			Badge_KIND_AWAY:   multiavatar.BadgeAway,
+		}
+		if b.GetKind() == Badge_KIND_CUSTOM {
+			opts = append(opts, multiavatar.WithBadge(multiavatar.BadgeCustom(b.GetSvg()), multiavatar.Corner(b.GetCorner())))
+		} else if bd, ok := badge[b.GetKind()]; ok {
+			opts = append(opts, multiavatar.WithBadge(bd, multiavatar.Corner(b.GetCorner())))
+		}
+	}
+	if o.GetClothingLogo() != "" {
+		opts = append(opts, multiavatar.WithClothingLogo(o.GetClothingLogo()))
+	}
+	if o.Saturation != nil {
+		opts = append(opts, multiavatar.WithSaturation(o.GetSaturation()))
+	}
+	if o.GetEmailNormalization() {
+		opts = append(opts, multiavatar.WithEmailNormalization())
+	}
+	if o.GetCompatV1() {
+		opts = append(opts, multiavatar.WithCompatV1())
+	}
+	return opts
+}
+
+// lists flattens StringList map values.
+func lists(m map[string]*StringList) map[string][]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string][]string, len(m))
+	for k, v := range m {
+		out[k] = v.GetValues()
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
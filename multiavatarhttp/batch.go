@@ -0,0 +1,200 @@
+package multiavatarhttp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// DefaultMaxBatch is the most names a batch handler accepts per request
+// unless WithMaxBatch says otherwise.
+const DefaultMaxBatch = 1000
+
+// batchFlushEvery is how many records a batch handler writes between
+// flushes, so clients receive avatars while the rest are rendered.
+const batchFlushEvery = 32
+
+// batchRecord is one line of a batch response.
+type batchRecord struct {
+	Name  string `json:"name"`
+	SVG   string `json:"svg,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewBatchHandler returns a handler rendering many avatars per request,
+// for bulk clients that would otherwise send one GET per avatar. It reads
+// newline-delimited names from the body of a POST and streams back one
+// JSON record per name, in order, as NDJSON:
+//
+//	{"name":"alice","svg":"<svg ...>"}
+//
+// Blank lines are skipped. The customization parameters understood by
+// ParseQuery, given in the URL, apply to every name:
+//
+//	mux.Handle("POST /avatars", multiavatarhttp.NewBatchHandler())
+//	// curl --data-binary @names.txt 'localhost:8080/avatars?theme=B'
+//
+// The response is gzip-compressed when the client accepts it. Names
+// rejected by the Authorizer get a record with an "error" field instead of
+// "svg". A batch with more than WithMaxBatch names is answered with 413
+// Request Entity Too Large, and a name longer than WithMaxNameLength or
+// invalid option values with 400 Bad Request, before any record is sent. A
+// batch counts as one request for WithRateLimit; the signature of
+// WithSigningKey covers the URL, not the names.
+func NewBatchHandler(opts ...HandlerOption) *Handler {
+	h := newHandler(nil, opts)
+	h.batch = true
+	return h
+}
+
+// WithMaxBatch rejects batches of more than n names with 413 Request
+// Entity Too Large; the default is DefaultMaxBatch. A non-positive n
+// removes the limit. It only affects NewBatchHandler.
+func WithMaxBatch(n int) HandlerOption {
+	return func(h *Handler) {
+		h.maxBatch = n
+	}
+}
+
+func (h *Handler) serveBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.admit(w, r) {
+		return
+	}
+	names, code, msg := h.readNames(w, r)
+	if code != 0 {
+		http.Error(w, msg, code)
+		return
+	}
+	reqOpts := ParseQuery(r.URL.Query())
+	opts := make([]multiavatar.Option, 0, len(h.opts)+len(reqOpts))
+	opts = append(opts, h.opts...)
+	opts = append(opts, reqOpts...)
+	// Resolve reports invalid option values without rendering.
+	if err := multiavatar.Resolve(names[0], opts...).Err(); err != nil {
+		h.log(r, slog.LevelWarn, "multiavatar: invalid options", slog.Any("error", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Add("Vary", "Accept-Encoding")
+	out := io.Writer(w)
+	var zw *gzip.Writer
+	if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		w.Header().Set("Content-Encoding", "gzip")
+		zw = gzip.NewWriter(w)
+		defer zw.Close()
+		out = zw
+	}
+	w.WriteHeader(http.StatusOK)
+	flush := func() {
+		if zw != nil {
+			zw.Flush()
+		}
+		_ = http.NewResponseController(w).Flush()
+	}
+
+	start := time.Now()
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	for i, name := range names {
+		if r.Context().Err() != nil {
+			return
+		}
+		rec := batchRecord{Name: name}
+		if h.authorize != nil {
+			if err := h.authorize(r, name, reqOpts); err != nil {
+				h.log(r, slog.LevelWarn, "multiavatar: request not authorized", slog.String("seed", name), slog.Any("error", err))
+				rec.Error = err.Error()
+			}
+		}
+		if rec.Error == "" {
+			t := time.Now()
+			buf.Reset()
+			if err := multiavatar.GenerateTo(buf, name, opts...); err != nil {
+				rec.Error = err.Error()
+			} else {
+				h.metrics.generation.WithLabelValues("svg").Observe(time.Since(t).Seconds())
+				rec.SVG = buf.String()
+			}
+		}
+		if err := enc.Encode(rec); err != nil {
+			return // the client went away
+		}
+		if (i+1)%batchFlushEvery == 0 {
+			flush()
+		}
+	}
+	h.log(r, slog.LevelDebug, "multiavatar: batch served",
+		slog.Int("avatars", len(names)), slog.Duration("duration", time.Since(start)))
+}
+
+// readNames reads the names of a batch request. On failure it returns the
+// status code and message to answer with.
+func (h *Handler) readNames(w http.ResponseWriter, r *http.Request) ([]string, int, string) {
+	lineLimit := bufio.MaxScanTokenSize
+	if h.maxNameLength > 0 {
+		// room for surrounding spaces and a \r
+		lineLimit = h.maxNameLength + 64
+	}
+	body := r.Body
+	if h.maxBatch > 0 {
+		body = http.MaxBytesReader(w, body, int64(h.maxBatch)*int64(lineLimit+1))
+	}
+	sc := bufio.NewScanner(body)
+	sc.Buffer(make([]byte, 0, 4096), lineLimit)
+	var names []string
+	for sc.Scan() {
+		name := strings.TrimSpace(sc.Text())
+		switch {
+		case name == "":
+			continue
+		case h.maxNameLength > 0 && len(name) > h.maxNameLength:
+			return nil, http.StatusBadRequest, "avatar name too long"
+		case h.maxBatch > 0 && len(names) == h.maxBatch:
+			return nil, http.StatusRequestEntityTooLarge, "too many names in batch"
+		}
+		names = append(names, name)
+	}
+	var maxBytes *http.MaxBytesError
+	switch err := sc.Err(); {
+	case errors.Is(err, bufio.ErrTooLong):
+		return nil, http.StatusBadRequest, "avatar name too long"
+	case errors.As(err, &maxBytes):
+		return nil, http.StatusRequestEntityTooLarge, "too many names in batch"
+	case err != nil:
+		return nil, http.StatusBadRequest, "reading request body: " + err.Error()
+	case len(names) == 0:
+		return nil, http.StatusBadRequest, "no avatar names in request body"
+	}
+	return names, 0, ""
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value allows gzip.
+func acceptsGzip(header string) bool {
+	for _, t := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(t), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		q := strings.ReplaceAll(params, " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
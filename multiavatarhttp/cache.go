@@ -0,0 +1,109 @@
+package multiavatarhttp
+
+import (
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WithCache keeps the last maxEntries rendered avatars in memory and
+// renders concurrent requests for the same avatar only once, sharing the
+// result, so a burst of cache misses, e.g. after a deploy empties a CDN,
+// costs one rendering per avatar instead of one per request. Requests are
+// still rate limited, verified and authorized one by one; only rendering
+// is shared. Entries are keyed by the seed, format, size and query, and
+// the least recently used are dropped first. A maxEntries of 0 or less
+// coalesces concurrent requests without keeping results. Invalid options
+// are reported to every waiting request and not kept.
+func WithCache(maxEntries int) HandlerOption {
+	return func(h *Handler) {
+		h.cache = newRenderCache(maxEntries)
+	}
+}
+
+// renderCache is an LRU cache of rendered avatars that coalesces
+// concurrent renderings of the same key, like x/sync/singleflight.
+type renderCache struct {
+	max int
+
+	mu      sync.Mutex
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+	calls   map[string]*renderCall
+}
+
+type cacheEntry struct {
+	key  string
+	body []byte
+}
+
+// renderCall is a rendering in progress; waiters block on done.
+type renderCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+func newRenderCache(maxEntries int) *renderCache {
+	return &renderCache{
+		max:     maxEntries,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		calls:   make(map[string]*renderCall),
+	}
+}
+
+// do returns the body cached for key, or waits for the rendering of key in
+// progress, or calls render and caches its body. result is "hit", "shared"
+// or "miss" accordingly. The body must not be modified.
+func (c *renderCache) do(key string, render func() ([]byte, error)) (body []byte, result string, err error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*cacheEntry).body, "hit", nil
+	}
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.body, "shared", call.err
+	}
+	call := &renderCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		if call.err == nil && c.max > 0 {
+			c.entries[key] = c.order.PushFront(&cacheEntry{key: key, body: call.body})
+			for c.order.Len() > c.max {
+				oldest := c.order.Back()
+				c.order.Remove(oldest)
+				delete(c.entries, oldest.Value.(*cacheEntry).key)
+			}
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	call.body, call.err = render()
+	return call.body, "miss", call.err
+}
+
+// cacheKey identifies the avatar req renders for r: everything but the
+// signature, which does not change the avatar.
+func cacheKey(r *http.Request, req *avatarRequest, format string) string {
+	q := r.URL.Query()
+	q.Del(sigParam)
+	var b strings.Builder
+	b.WriteString(format)
+	b.WriteByte(0)
+	b.WriteString(strconv.Itoa(req.size))
+	b.WriteByte(0)
+	b.WriteString(req.seed)
+	b.WriteByte(0)
+	b.WriteString(q.Encode())
+	return b.String()
+}
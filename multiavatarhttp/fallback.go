@@ -0,0 +1,103 @@
+package multiavatarhttp
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+)
+
+// fallbackKind is the response a Fallback selects.
+type fallbackKind int
+
+const (
+	fallbackError fallbackKind = iota
+	fallbackNotFound
+	fallbackBlank
+	fallbackRedirect
+	fallbackAvatar
+)
+
+// Fallback is what NewHandler and NewPathHandler serve when a request's
+// name is missing or longer than WithMaxNameLength allows, like Gravatar's
+// d= parameter, and what NewGravatarHandler serves for a d= URL on a host
+// WithRedirectHosts does not allow. Create one with FallbackError, FallbackNotFound,
+// FallbackBlank, FallbackRedirect or FallbackAvatar and install it with
+// WithFallback.
+type Fallback struct {
+	kind fallbackKind
+	// target is the redirect URL or the seed of the default avatar
+	target string
+}
+
+// FallbackError rejects the request with 400 Bad Request, the default.
+func FallbackError() Fallback { return Fallback{kind: fallbackError} }
+
+// FallbackNotFound answers 404 Not Found, like Gravatar's d=404.
+func FallbackNotFound() Fallback { return Fallback{kind: fallbackNotFound} }
+
+// FallbackBlank serves a 1×1 transparent pixel, as a PNG for ".png"
+// requests and an SVG otherwise, so <img> tags render nothing instead of a
+// broken image.
+func FallbackBlank() Fallback { return Fallback{kind: fallbackBlank} }
+
+// FallbackRedirect redirects to url with 302 Found, e.g. to a static
+// placeholder image.
+func FallbackRedirect(url string) Fallback { return Fallback{kind: fallbackRedirect, target: url} }
+
+// FallbackAvatar serves the avatar of seed instead, in the requested format
+// and size and with the request's other parameters, like Gravatar's
+// built-in defaults. seed must not be empty.
+func FallbackAvatar(seed string) Fallback { return Fallback{kind: fallbackAvatar, target: seed} }
+
+// WithFallback sets what the handler serves for a missing or too long
+// name; see Fallback. Other invalid parameters, such as a malformed color,
+// are still rejected with 400 Bad Request. Unlike Gravatar's d=, the
+// fallback is chosen by the server, never the URL, so the handler cannot
+// be used as an open redirect. NewGravatarHandler keeps Gravatar's d=,
+// redirecting only to hosts allowed with WithRedirectHosts and serving
+// this fallback for URLs on other hosts.
+func WithFallback(f Fallback) HandlerOption {
+	return func(h *Handler) {
+		h.fallback = f
+	}
+}
+
+// serveFallback answers a request whose name is invalid for reason. It
+// returns the seed to render instead and true for FallbackAvatar;
+// otherwise the response has been written.
+func (h *Handler) serveFallback(w http.ResponseWriter, r *http.Request, req *avatarRequest, reason string) (string, bool) {
+	switch h.fallback.kind {
+	case fallbackNotFound:
+		http.NotFound(w, r)
+	case fallbackBlank:
+		writePixel(w, req.format)
+	case fallbackRedirect:
+		http.Redirect(w, r, h.fallback.target, http.StatusFound)
+	case fallbackAvatar:
+		return h.fallback.target, true
+	default:
+		http.Error(w, reason, http.StatusBadRequest)
+	}
+	return "", false
+}
+
+// transparentPixel is a 1×1 transparent PNG.
+var transparentPixel = func() []byte {
+	var b bytes.Buffer
+	_ = png.Encode(&b, image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	return b.Bytes()
+}()
+
+// writePixel writes a 1×1 transparent image in format, "png" or "svg".
+func writePixel(w http.ResponseWriter, format string) {
+	if format == "png" {
+		w.Header().Set("Content-Type", "image/png")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(transparentPixel)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg" width="1" height="1"/>`))
+}
@@ -0,0 +1,150 @@
+package multiavatarhttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+const (
+	// defaultGravatarSize is Gravatar's default image size in pixels.
+	defaultGravatarSize = 80
+	// maxGravatarSize is the largest size Gravatar accepts.
+	maxGravatarSize = 2048
+)
+
+// NewGravatarHandler returns a handler speaking Gravatar's URL scheme, so it
+// can stand in for a self-hosted Gravatar:
+//
+//	/avatar/{md5-or-sha256-hash}[.ext]?s=200&d=404&f=y
+//
+// The lowercased hash is used as the seed. Supported parameters:
+//
+//	s, size          pixel size, 1..2048 (default 80)
+//	d, default       fallback when the hash is missing or invalid:
+//	                 "404", "blank", or an http(s) URL to redirect to;
+//	                 any other value (mp, identicon, ...) renders an avatar
+//	f, forcedefault  "y" always serves the fallback
+//
+// d= only redirects to hosts allowed with WithRedirectHosts, so the
+// handler cannot be used as an open redirect. A URL on any other host is
+// answered with the server's WithFallback, 400 Bad Request by default.
+//
+// Mount it with http.StripPrefix or any router; only the last path segment is read.
+func NewGravatarHandler(opts ...HandlerOption) *Handler {
+	h := newHandler(nil, opts)
+	h.parse = h.parseGravatarRequest
+	return h
+}
+
+// WithRedirectHosts lets the d= parameter of NewGravatarHandler redirect
+// to http and https URLs on the given hosts, e.g. "static.example.com".
+// Hosts are matched without the port and case-insensitively; repeated
+// calls add hosts.
+func WithRedirectHosts(hosts ...string) HandlerOption {
+	return func(h *Handler) {
+		if h.redirectHosts == nil {
+			h.redirectHosts = make(map[string]bool, len(hosts))
+		}
+		for _, host := range hosts {
+			h.redirectHosts[strings.ToLower(strings.TrimSpace(host))] = true
+		}
+	}
+}
+
+// redirectTarget returns the d= URL to redirect to, or false if its host
+// is not allowed with WithRedirectHosts.
+func (h *Handler) redirectTarget(def string) (string, bool) {
+	u, err := url.Parse(def)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return "", false
+	}
+	if !h.redirectHosts[strings.ToLower(u.Hostname())] {
+		return "", false
+	}
+	return u.String(), true
+}
+
+func (h *Handler) parseGravatarRequest(w http.ResponseWriter, r *http.Request) (*avatarRequest, bool) {
+	q := r.URL.Query()
+
+	hash := r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+	if i := strings.IndexByte(hash, '.'); i >= 0 {
+		hash = hash[:i]
+	}
+	hash = strings.ToLower(hash)
+
+	size := defaultGravatarSize
+	if s := firstParam(q.Get("s"), q.Get("size")); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= maxGravatarSize {
+			size = n
+		}
+	}
+
+	def := firstParam(q.Get("d"), q.Get("default"))
+	force := parseBool(firstParam(q.Get("f"), q.Get("forcedefault")))
+
+	if force || !isGravatarHash(hash) {
+		switch {
+		case def == "404":
+			http.NotFound(w, r)
+			return nil, false
+		case def == "blank":
+			writeBlank(w, size)
+			return nil, false
+		case strings.HasPrefix(def, "http://") || strings.HasPrefix(def, "https://"):
+			target, ok := h.redirectTarget(def)
+			if !ok {
+				req := newGravatarRequest(hash, size)
+				req.missing = "redirect host not allowed in 'd' parameter"
+				return req, true
+			}
+			http.Redirect(w, r, target, http.StatusFound)
+			return nil, false
+		}
+	}
+	if hash == "" {
+		writeBlank(w, size)
+		return nil, false
+	}
+
+	return newGravatarRequest(hash, size), true
+}
+
+func newGravatarRequest(hash string, size int) *avatarRequest {
+	return &avatarRequest{seed: hash, opts: []multiavatar.Option{multiavatar.WithSize(size)}, size: size}
+}
+
+// isGravatarHash reports whether s is a lowercase MD5 or SHA-256 hex digest.
+func isGravatarHash(s string) bool {
+	if len(s) != 32 && len(s) != 64 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// writeBlank writes a fully transparent SVG of the given size.
+func writeBlank(w http.ResponseWriter, size int) {
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 231 231" width="%d" height="%d"></svg>`, size, size)
+}
+
+func firstParam(vals ...string) string {
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
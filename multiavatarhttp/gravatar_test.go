@@ -0,0 +1,56 @@
+package multiavatarhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/changzee/multiavatar-go/multiavatarhttp"
+)
+
+func TestGravatarRedirect(t *testing.T) {
+	allowed := multiavatarhttp.WithRedirectHosts("static.example.com")
+	tests := []struct {
+		name     string
+		opts     []multiavatarhttp.HandlerOption
+		d        string
+		code     int
+		location string
+	}{
+		{"no allowlist", nil, "https://evil.example.net/x.png", http.StatusBadRequest, ""},
+		{"allowed", []multiavatarhttp.HandlerOption{allowed}, "https://static.example.com/x.png", http.StatusFound, "https://static.example.com/x.png"},
+		{"allowed with port", []multiavatarhttp.HandlerOption{allowed}, "http://STATIC.example.com:8080/x.png", http.StatusFound, "http://STATIC.example.com:8080/x.png"},
+		{"other host", []multiavatarhttp.HandlerOption{allowed}, "https://evil.example.net/x.png", http.StatusBadRequest, ""},
+		{"userinfo", []multiavatarhttp.HandlerOption{allowed}, "https://static.example.com@evil.example.net/", http.StatusBadRequest, ""},
+		{"server fallback", []multiavatarhttp.HandlerOption{multiavatarhttp.WithFallback(multiavatarhttp.FallbackRedirect("/default.png"))},
+			"https://evil.example.net/x.png", http.StatusFound, "/default.png"},
+		{"not found", nil, "404", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := multiavatarhttp.NewGravatarHandler(tt.opts...)
+			r := httptest.NewRequest("GET", "/avatar/nothash", nil)
+			q := r.URL.Query()
+			q.Set("d", tt.d)
+			r.URL.RawQuery = q.Encode()
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Fatalf("got status %d, want %d", w.Code, tt.code)
+			}
+			if loc := w.Header().Get("Location"); loc != tt.location {
+				t.Errorf("got Location %q, want %q", loc, tt.location)
+			}
+		})
+	}
+}
+
+func TestGravatarValidHashIgnoresDefault(t *testing.T) {
+	h := multiavatarhttp.NewGravatarHandler()
+	r := httptest.NewRequest("GET", "/avatar/205e460b479e2e5b48aec07710c08d50?d=https://evil.example.net/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("got status %d, want 200", w.Code)
+	}
+}
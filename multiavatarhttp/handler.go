@@ -0,0 +1,396 @@
+// Package multiavatarhttp serves multiavatar SVGs over HTTP.
+package multiavatarhttp
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// avatarRequest is a parsed request: the seed to hash and the options
+// derived from the URL.
+type avatarRequest struct {
+	seed string
+	opts []multiavatar.Option
+	// size is the requested image size in pixels, 0 if none
+	size int
+	// format is "png" for PNG requests and "svg" or "" for SVG
+	format string
+	// missing, if set, reports that the name is missing; see Fallback
+	missing string
+}
+
+// Handler is an http.Handler that renders avatars.
+// It is safe for concurrent use.
+type Handler struct {
+	// parse extracts the avatar request; when it returns false the
+	// response has already been written.
+	parse func(w http.ResponseWriter, r *http.Request) (*avatarRequest, bool)
+	// opts are applied before the per-request options.
+	opts []multiavatar.Option
+	// authorize, if set, vets every request before rendering.
+	authorize Authorizer
+	// signingKey, if set, requires requests to carry a valid HMAC signature.
+	signingKey []byte
+	// metrics records every request; see Collector.
+	metrics *metrics
+	// logger, if set, logs every request; see WithLogger.
+	logger *slog.Logger
+	// limiter, if set, rate-limits requests per client; see WithRateLimit.
+	limiter *rateLimiter
+	// clientIP identifies the client for limiter; nil uses remoteIP.
+	clientIP func(r *http.Request) string
+	// maxNameLength bounds the seed in bytes; 0 or less disables the check.
+	maxNameLength int
+	// maxSize bounds the requested image size; 0 or less disables the check.
+	maxSize int
+	// batch serves NDJSON batches instead of single avatars; see NewBatchHandler.
+	batch bool
+	// maxBatch bounds the names of a batch; 0 or less disables the check.
+	maxBatch int
+	// fallback answers requests with a missing or too long name; see WithFallback.
+	fallback Fallback
+	// redirectHosts are the hosts Gravatar's d= may redirect to; see
+	// WithRedirectHosts.
+	redirectHosts map[string]bool
+	// hashParam accepts the seed as a SHA-256 digest; see WithHashParam.
+	hashParam bool
+	// cache, if set, shares rendered avatars between requests; see WithCache.
+	cache *renderCache
+}
+
+// Authorizer decides whether a request may be served. It receives the seed
+// and the options parsed from the request (excluding base options); a
+// non-nil error rejects the request with 403 Forbidden and the error text.
+type Authorizer func(r *http.Request, seed string, opts []multiavatar.Option) error
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*Handler)
+
+// WithOptions sets base generation options applied to every request.
+// Options parsed from the request are applied after them.
+func WithOptions(opts ...multiavatar.Option) HandlerOption {
+	return func(h *Handler) {
+		h.opts = append(h.opts, opts...)
+	}
+}
+
+// WithAuthorizer installs an authorization hook, e.g. to allow
+// customizations only for authenticated users or to require the seed to match
+// the caller's own user ID.
+func WithAuthorizer(fn Authorizer) HandlerOption {
+	return func(h *Handler) {
+		h.authorize = fn
+	}
+}
+
+// WithLogger logs every request to l: served avatars with their seed,
+// query, generation time and cache result at Debug level, and requests
+// rejected for an invalid signature, by the Authorizer or for invalid
+// option values at Warn. Seeds are logged as given; use a handler with
+// ReplaceAttr to redact them if they are personal data.
+func WithLogger(l *slog.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.logger = l
+	}
+}
+
+const (
+	// DefaultPNGSize is the size, in pixels, of PNG avatars requested
+	// without a size parameter.
+	DefaultPNGSize = 256
+	// maxPNGSize is the largest PNG size served, like Gravatar's.
+	maxPNGSize = 2048
+)
+
+// NewHandler returns a handler serving `?name=...` requests, with the
+// customization parameters understood by ParseQuery.
+//
+// Without a name parameter the seed is read from the last path segment
+// when it ends in ".svg" or ".png", so a handler mounted on a subtree
+// also serves path-style URLs, which cache better and read cleaner in
+// HTML:
+//
+//	mux.Handle("/avatar/", multiavatarhttp.NewHandler())
+//	// /avatar/alice.svg, /avatar/Binx%20Bond.png?size=64
+//
+// The extension selects the format; PNGs are size×size pixels, set with
+// the size parameter from 1 to 2048 (default DefaultPNGSize).
+func NewHandler(opts ...HandlerOption) *Handler {
+	return newHandler(parseNameRequest, opts)
+}
+
+// NewPathHandler returns a handler that takes the seed from the path
+// parameter param, read with r.PathValue, e.g. with the standard mux:
+//
+//	mux.Handle("GET /avatars/{name}", multiavatarhttp.NewPathHandler("name"))
+//
+// A ".svg" extension is stripped, so /avatars/alice.svg renders "alice";
+// a ".png" extension serves a PNG as for NewHandler. The customization
+// parameters understood by ParseQuery are accepted as for NewHandler.
+// Routers without PathValue support set it with r.SetPathValue.
+func NewPathHandler(param string, opts ...HandlerOption) *Handler {
+	return newHandler(func(w http.ResponseWriter, r *http.Request) (*avatarRequest, bool) {
+		name, format := splitFormat(r.PathValue(param))
+		req, ok := newAvatarRequest(w, r.URL.Query(), strings.TrimSpace(name), format)
+		if ok && req.seed == "" {
+			req.missing = "missing avatar name in path"
+		}
+		return req, ok
+	}, opts)
+}
+
+func newHandler(parse func(http.ResponseWriter, *http.Request) (*avatarRequest, bool), opts []HandlerOption) *Handler {
+	h := &Handler{parse: parse, metrics: newMetrics(), maxNameLength: DefaultMaxNameLength, maxBatch: DefaultMaxBatch}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+//
+// Invalid option values, such as malformed colors, are rejected with
+// 400 Bad Request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w}
+	format := "svg"
+	if h.batch {
+		format = "ndjson"
+		h.serveBatch(rec, r)
+	} else if f := h.serve(rec, r); f != "" {
+		format = f
+	}
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	h.metrics.observe(rec.code, format)
+}
+
+// admit applies the rate limit and signature check to r. When it returns
+// false the response has already been written.
+func (h *Handler) admit(w http.ResponseWriter, r *http.Request) bool {
+	if h.limiter != nil {
+		client := remoteIP(r)
+		if h.clientIP != nil {
+			client = h.clientIP(r)
+		}
+		if ok, wait := h.limiter.allow(client, time.Now()); !ok {
+			h.log(r, slog.LevelDebug, "multiavatar: rate limited", slog.String("client", client))
+			w.Header().Set("Retry-After", retryAfter(wait))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return false
+		}
+	}
+	if h.signingKey != nil && !verifySignature(h.signingKey, r) {
+		h.log(r, slog.LevelWarn, "multiavatar: invalid signature")
+		http.Error(w, "invalid or missing signature", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// serve renders the avatar for r and returns its format, "svg" or "png",
+// or "" if the request was rejected before the format was known.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) string {
+	if !h.admit(w, r) {
+		return ""
+	}
+	req, ok := h.parse(w, r)
+	if !ok {
+		return ""
+	}
+	format := "svg"
+	if req.format == "png" {
+		format = "png"
+	}
+	if h.hashParam && !applyHash(w, r, req) {
+		return format
+	}
+	reason := req.missing
+	if reason == "" && h.maxNameLength > 0 && len(req.seed) > h.maxNameLength {
+		reason = "avatar name too long"
+	}
+	if reason != "" {
+		seed, ok := h.serveFallback(w, r, req, reason)
+		if !ok {
+			return format
+		}
+		req.seed = seed
+	}
+	if h.maxSize > 0 && req.size > h.maxSize {
+		http.Error(w, "avatar size too large", http.StatusBadRequest)
+		return format
+	}
+	if h.authorize != nil {
+		if err := h.authorize(r, req.seed, req.opts); err != nil {
+			h.log(r, slog.LevelWarn, "multiavatar: request not authorized", slog.String("seed", req.seed), slog.Any("error", err))
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return format
+		}
+	}
+
+	opts := make([]multiavatar.Option, 0, len(h.opts)+len(req.opts))
+	opts = append(opts, h.opts...)
+	opts = append(opts, req.opts...)
+
+	start := time.Now()
+	var body []byte
+	var result string
+	var err error
+	if h.cache != nil {
+		body, result, err = h.cache.do(cacheKey(r, req, format), func() ([]byte, error) {
+			var buf bytes.Buffer
+			err := h.render(&buf, req, format, opts)
+			return buf.Bytes(), err
+		})
+		h.metrics.renderCache.WithLabelValues(result).Inc()
+	} else {
+		buf := bufferPool.Get().(*bytes.Buffer)
+		defer putBuffer(buf)
+		err = h.render(buf, req, format, opts)
+		body = buf.Bytes()
+	}
+	if err != nil {
+		// Invalid option values, e.g. a color that is not a CSS color.
+		h.log(r, slog.LevelWarn, "multiavatar: invalid options", slog.String("seed", req.seed), slog.Any("error", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return format
+	}
+	h.logServed(r, req.seed, time.Since(start), result)
+
+	if format == "png" {
+		w.Header().Set("Content-Type", "image/png")
+	} else {
+		w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+	return format
+}
+
+// render writes the avatar of req to buf as an SVG or, for "png", as a
+// req.size×req.size PNG, and records the time it took.
+func (h *Handler) render(buf *bytes.Buffer, req *avatarRequest, format string, opts []multiavatar.Option) error {
+	start := time.Now()
+	err := render(buf, req, format, opts)
+	if err == nil {
+		h.metrics.generation.WithLabelValues(format).Observe(time.Since(start).Seconds())
+	}
+	return err
+}
+
+func render(buf *bytes.Buffer, req *avatarRequest, format string, opts []multiavatar.Option) error {
+	if format != "png" {
+		return multiavatar.GenerateTo(buf, req.seed, opts...)
+	}
+	png, err := multiavatar.Resolve(req.seed, opts...).PNG(req.size)
+	if err != nil {
+		return err
+	}
+	buf.Write(png)
+	return nil
+}
+
+// log logs msg for r, with its path and query, if a logger is set.
+func (h *Handler) log(r *http.Request, level slog.Level, msg string, attrs ...slog.Attr) {
+	if h.logger == nil || !h.logger.Enabled(r.Context(), level) {
+		return
+	}
+	attrs = append([]slog.Attr{slog.String("path", r.URL.Path), slog.String("query", r.URL.RawQuery)}, attrs...)
+	h.logger.LogAttrs(r.Context(), level, msg, attrs...)
+}
+
+// logServed logs a rendered avatar and, with WithCache, the cache result.
+func (h *Handler) logServed(r *http.Request, seed string, elapsed time.Duration, cache string) {
+	attrs := []slog.Attr{slog.String("seed", seed), slog.Duration("duration", elapsed)}
+	if cache != "" {
+		attrs = append(attrs, slog.String("cache", cache))
+	}
+	h.log(r, slog.LevelDebug, "multiavatar: avatar served", attrs...)
+}
+
+// maxPooledBuffer bounds the capacity of buffers returned to bufferPool.
+const maxPooledBuffer = 64 << 10
+
+// bufferPool holds the buffers responses are rendered into, so busy
+// servers do not regrow one per request.
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// putBuffer resets buf and returns it to bufferPool.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+func parseNameRequest(w http.ResponseWriter, r *http.Request) (*avatarRequest, bool) {
+	q := r.URL.Query()
+	name, format := strings.TrimSpace(q.Get("name")), "svg"
+	if name == "" {
+		name, format = pathName(r)
+		name = strings.TrimSpace(name)
+	}
+	req, ok := newAvatarRequest(w, q, name, format)
+	if ok && name == "" {
+		req.missing = "missing required 'name' parameter"
+	}
+	return req, ok
+}
+
+// pathName returns the unescaped last segment of r's path without its
+// extension, and the format the extension selects, or "" if the segment
+// has no ".svg" or ".png" extension. The segment is cut from the escaped
+// path, so names may contain an encoded slash.
+func pathName(r *http.Request) (name, format string) {
+	p := r.URL.EscapedPath()
+	seg, format := splitFormat(p[strings.LastIndexByte(p, '/')+1:])
+	if format == "" {
+		return "", ""
+	}
+	name, err := url.PathUnescape(seg)
+	if err != nil {
+		return "", ""
+	}
+	return name, format
+}
+
+// splitFormat cuts a ".svg" or ".png" extension, in any case, off name
+// and returns the format it selects; format is "" without one.
+func splitFormat(name string) (string, string) {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		switch ext := strings.ToLower(name[i+1:]); ext {
+		case "svg", "png":
+			return name[:i], ext
+		}
+	}
+	return name, ""
+}
+
+// newAvatarRequest returns the request for seed in format, reading the
+// PNG size from q. Invalid sizes are answered with 400 Bad Request.
+func newAvatarRequest(w http.ResponseWriter, q url.Values, seed, format string) (*avatarRequest, bool) {
+	req := &avatarRequest{seed: seed, opts: ParseQuery(q), format: format}
+	if format != "png" {
+		return req, true
+	}
+	req.size = DefaultPNGSize
+	if s := strings.TrimSpace(q.Get("size")); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxPNGSize {
+			http.Error(w, "invalid 'size' parameter", http.StatusBadRequest)
+			return nil, false
+		}
+		req.size = n
+	}
+	return req, true
+}
@@ -0,0 +1,46 @@
+package multiavatarhttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// WithHashParam lets requests to NewHandler and NewPathHandler give the
+// seed as hash=, the hex SHA-256 digest of the name as returned by
+// multiavatar.HashInput, instead of the name itself, so upstream services
+// never send emails and other personal data in plain text:
+//
+//	/avatar?hash=ff8d9819fc0e12bf0d24892e45987e249a28dce836a85cad60e28eaaa8c6d976
+//
+// The handler selects the parts from the digest without hashing it again
+// (see multiavatar.WithHashedInput), so the avatar matches the one of the
+// name. Digests that are not 64 hex digits, and requests giving both a
+// name and a hash, are rejected with 400 Bad Request.
+func WithHashParam() HandlerOption {
+	return func(h *Handler) {
+		h.hashParam = true
+	}
+}
+
+// applyHash takes the seed of req from the hash parameter of r, if any;
+// see WithHashParam. When it returns false the response has already been
+// written.
+func applyHash(w http.ResponseWriter, r *http.Request, req *avatarRequest) bool {
+	hash := strings.TrimSpace(r.URL.Query().Get("hash"))
+	if hash == "" {
+		return true
+	}
+	if req.missing == "" {
+		http.Error(w, "give either a name or a 'hash' parameter", http.StatusBadRequest)
+		return false
+	}
+	if !multiavatar.IsSHA256Hex(hash) {
+		http.Error(w, "invalid 'hash' parameter: want 64 hex digits", http.StatusBadRequest)
+		return false
+	}
+	req.seed, req.missing = strings.ToLower(hash), ""
+	req.opts = append(req.opts, multiavatar.WithHashedInput())
+	return true
+}
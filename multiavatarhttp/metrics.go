@@ -0,0 +1,111 @@
+package multiavatarhttp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics holds the Prometheus instruments of a Handler.
+type metrics struct {
+	requests   *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	generation *prometheus.HistogramVec
+	// renderCache counts the results of WithCache lookups
+	renderCache *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "multiavatar",
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Avatar requests by HTTP status code and output format.",
+		}, []string{"code", "format"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "multiavatar",
+			Subsystem: "http",
+			Name:      "errors_total",
+			Help:      "Avatar requests answered with a 4xx or 5xx status, by status code.",
+		}, []string{"code"}),
+		generation: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "multiavatar",
+			Subsystem: "http",
+			Name:      "generation_duration_seconds",
+			Help:      "Time spent rendering avatars, by output format.",
+			Buckets:   []float64{.00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1},
+		}, []string{"format"}),
+		renderCache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "multiavatar",
+			Subsystem: "http",
+			Name:      "render_cache_total",
+			Help:      "Avatars looked up in the WithCache cache by result: hit, shared with a concurrent rendering, or miss.",
+		}, []string{"result"}),
+	}
+}
+
+// Collector returns a prometheus.Collector exposing the handler's metrics:
+//
+//	multiavatar_http_requests_total{code,format}
+//	multiavatar_http_errors_total{code}
+//	multiavatar_http_generation_duration_seconds{format}
+//	multiavatar_http_render_cache_total{result}
+//
+// Register it once per handler. To monitor several handlers in one
+// registry, register each through prometheus.WrapRegistererWith with a
+// label such as handler="gravatar".
+func (h *Handler) Collector() prometheus.Collector {
+	return h.metrics
+}
+
+// Describe implements prometheus.Collector.
+func (m *metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.errors.Describe(ch)
+	m.generation.Describe(ch)
+	m.renderCache.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.errors.Collect(ch)
+	m.generation.Collect(ch)
+	m.renderCache.Collect(ch)
+}
+
+// observe records the outcome of one request.
+func (m *metrics) observe(code int, format string) {
+	c := strconv.Itoa(code)
+	m.requests.WithLabelValues(c, format).Inc()
+	if code >= 400 {
+		m.errors.WithLabelValues(c).Inc()
+	}
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
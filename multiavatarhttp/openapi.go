@@ -0,0 +1,25 @@
+package multiavatarhttp
+
+import (
+	"bytes"
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+// OpenAPI returns the OpenAPI 3 document, in YAML, describing the endpoints
+// of NewHandler (mounted at /avatar) and NewGravatarHandler (/avatar/{hash})
+// and all their parameters. The multiavatarclient package is a Go client for it.
+func OpenAPI() []byte {
+	return bytes.Clone(openAPI)
+}
+
+// OpenAPIHandler serves the document returned by OpenAPI.
+func OpenAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		_, _ = w.Write(openAPI)
+	})
+}
@@ -0,0 +1,250 @@
+package multiavatarhttp
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+// ParseQuery converts the demo query-string parameters into generation options.
+//
+// Supported parameters:
+//
+//	profile=corp                       WithProfile
+//	algorithm=2                        WithAlgorithm
+//	style=pixel                        WithStyle
+//	transparent=true                   WithoutBackground
+//	shape=squircle                     WithBackgroundShape
+//	harmonious=true                    WithHarmoniousColors
+//	mirror=true                        WithMirror
+//	rotation=-15                       WithRotation
+//	frame=laurel                       WithFrame
+//	theme=A                            WithTheme
+//	gender=female                      WithGender
+//	expression=sad                     WithExpression
+//	partTheme=eyes:C,top:A             WithPartTheme
+//	allowedThemes=top:A|C              WithAllowedThemes
+//	partVersion=eyes:11,top:afro       WithPartVersion, WithNamedPart
+//	allowedVersions=eyes:03|11         WithAllowedVersions
+//	env,clo,mouth,head,eyes,top,hat,accessory=#hex color overrides, '|' separated
+//	withPart=hat|accessory             WithPart
+//	withoutPart=top|eyes               WithoutPart
+func ParseQuery(q url.Values) []multiavatar.Option {
+	var opts []multiavatar.Option
+
+	// Registered profile, first so the other parameters override it
+	if p := strings.TrimSpace(q.Get("profile")); p != "" {
+		opts = append(opts, multiavatar.WithProfile(p))
+	}
+
+	// Selection algorithm version
+	if a, err := strconv.Atoi(strings.TrimSpace(q.Get("algorithm"))); err == nil {
+		opts = append(opts, multiavatar.WithAlgorithm(multiavatar.Algorithm(a)))
+	}
+
+	// Registered theme pack
+	if st := strings.TrimSpace(q.Get("style")); st != "" {
+		opts = append(opts, multiavatar.WithStyle(st))
+	}
+
+	// transparent => WithoutBackground
+	if parseBool(q.Get("transparent")) {
+		opts = append(opts, multiavatar.WithoutBackground())
+	}
+
+	// Background shape: none/circle/squircle
+	if sh, ok := multiavatar.ParseBackgroundShape(strings.TrimSpace(q.Get("shape"))); ok {
+		opts = append(opts, multiavatar.WithBackgroundShape(sh))
+	}
+
+	if parseBool(q.Get("harmonious")) {
+		opts = append(opts, multiavatar.WithHarmoniousColors())
+	}
+
+	// mirror => WithMirror
+	if parseBool(q.Get("mirror")) {
+		opts = append(opts, multiavatar.WithMirror())
+	}
+
+	// Rotation in degrees, clockwise
+	if r, err := strconv.ParseFloat(strings.TrimSpace(q.Get("rotation")), 64); err == nil {
+		opts = append(opts, multiavatar.WithRotation(r))
+	}
+
+	// Frame: gold/laurel/flame or a registered name
+	if f := strings.TrimSpace(q.Get("frame")); f != "" {
+		opts = append(opts, multiavatar.WithFrame(f))
+	}
+
+	// Global theme
+	if t := strings.TrimSpace(q.Get("theme")); t != "" {
+		opts = append(opts, multiavatar.WithTheme(t))
+	}
+	// Gender preset: male/female/unisex
+	if g := strings.TrimSpace(q.Get("gender")); g != "" {
+		opts = append(opts, multiavatar.WithGender(g))
+	}
+
+	// Expression preset: neutral/happy/sad/angry/surprised
+	if e, ok := multiavatar.ParseExpression(strings.TrimSpace(q.Get("expression"))); ok {
+		opts = append(opts, multiavatar.WithExpression(e))
+	}
+
+	// Per-part theme: eyes:C,top:A
+	for part, val := range parseKVComma(q.Get("partTheme")) {
+		opts = append(opts, multiavatar.WithPartTheme(part, val))
+	}
+
+	// Allowed themes per part: top:A|C
+	for part, list := range parseKVList(q.Get("allowedThemes")) {
+		opts = append(opts, multiavatar.WithAllowedThemes(part, list))
+	}
+
+	// Force versions per part by code or name: eyes:11,top:afro
+	for part, val := range parseKVComma(q.Get("partVersion")) {
+		if _, ok := multiavatar.PartVersionByName(part, val); ok {
+			opts = append(opts, multiavatar.WithNamedPart(part, val))
+		} else {
+			opts = append(opts, multiavatar.WithPartVersion(part, val))
+		}
+	}
+
+	// Allowed versions per part: eyes:03|11,top:01|03|07
+	for part, list := range parseKVList(q.Get("allowedVersions")) {
+		opts = append(opts, multiavatar.WithAllowedVersions(part, list))
+	}
+
+	// Optional parts: hat|accessory
+	for _, p := range splitList(q.Get("withPart")) {
+		opts = append(opts, multiavatar.WithPart(p))
+	}
+
+	// Color overrides: env,clo,mouth,head,eyes,top,hat,accessory with '|' separated values
+	addColorOverrides(&opts, "env", q.Get("env"))
+	addColorOverrides(&opts, "clo", q.Get("clo"))
+	addColorOverrides(&opts, "mouth", q.Get("mouth"))
+	if v := strings.TrimSpace(q.Get("head")); v != "" {
+		opts = append(opts, multiavatar.WithSkinColor(v))
+	}
+	addColorOverrides(&opts, "eyes", q.Get("eyes"))
+	addColorOverrides(&opts, "top", q.Get("top"))
+	addColorOverrides(&opts, "hat", q.Get("hat"))
+	addColorOverrides(&opts, "accessory", q.Get("accessory"))
+
+	// Disable parts: top|eyes|clo|mouth|head|env|hat|accessory
+	for _, p := range splitList(q.Get("withoutPart")) {
+		switch p {
+		case "env", "clo", "head", "mouth", "eyes", "top", "hat", "accessory":
+			opts = append(opts, multiavatar.WithoutPart(p))
+		}
+	}
+
+	return opts
+}
+
+// Helpers
+
+func parseBool(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "y", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse "key:val,key2:val2" into map[key]val
+func parseKVComma(s string) map[string]string {
+	res := make(map[string]string)
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return res
+	}
+	parts := strings.Split(s, ",")
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		kv := strings.SplitN(p, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		val := strings.TrimSpace(kv[1])
+		if key != "" && val != "" {
+			res[key] = val
+		}
+	}
+	return res
+}
+
+// Parse "key:a|b|c,key2:x|y" into map[key][]string
+func parseKVList(s string) map[string][]string {
+	res := make(map[string][]string)
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return res
+	}
+	parts := strings.Split(s, ",")
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		kv := strings.SplitN(p, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		raw := strings.TrimSpace(kv[1])
+		list := splitList(raw)
+		if key != "" && len(list) > 0 {
+			res[key] = list
+		}
+	}
+	return res
+}
+
+func splitList(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	items := strings.Split(s, "|")
+	var out []string
+	for _, it := range items {
+		it = strings.TrimSpace(it)
+		if it != "" {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func addColorOverrides(opts *[]multiavatar.Option, part string, raw string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return
+	}
+	colors := splitList(raw)
+	if len(colors) == 0 {
+		return
+	}
+	switch part {
+	case "env":
+		*opts = append(*opts, multiavatar.WithEnvColor(colors[0]))
+	case "clo":
+		*opts = append(*opts, multiavatar.WithClothesColors(colors...))
+	case "mouth":
+		*opts = append(*opts, multiavatar.WithMouthColors(colors...))
+	case "eyes":
+		*opts = append(*opts, multiavatar.WithEyesColors(colors...))
+	case "top":
+		*opts = append(*opts, multiavatar.WithTopColors(colors...))
+	default:
+		*opts = append(*opts, multiavatar.WithPartColors(part, colors))
+	}
+}
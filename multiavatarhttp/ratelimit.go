@@ -0,0 +1,123 @@
+package multiavatarhttp
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMaxNameLength is the longest seed, in bytes, a handler accepts
+// unless WithMaxNameLength says otherwise.
+const DefaultMaxNameLength = 256
+
+// rateSweepInterval is how often idle clients are dropped from a rateLimiter.
+const rateSweepInterval = time.Minute
+
+// WithRateLimit limits each client to rps requests per second on average,
+// with bursts of up to burst requests. Requests over the limit are
+// answered with 429 Too Many Requests and a Retry-After header, before any
+// rendering. Clients are told apart by the host of r.RemoteAddr; behind a
+// reverse proxy, set WithClientIP. A non-positive rps disables the limit.
+func WithRateLimit(rps float64, burst int) HandlerOption {
+	return func(h *Handler) {
+		if rps <= 0 {
+			h.limiter = nil
+			return
+		}
+		h.limiter = newRateLimiter(rps, max(burst, 1))
+	}
+}
+
+// WithClientIP sets how WithRateLimit identifies the client of a request,
+// e.g. from the X-Forwarded-For header set by a trusted proxy.
+func WithClientIP(fn func(r *http.Request) string) HandlerOption {
+	return func(h *Handler) {
+		h.clientIP = fn
+	}
+}
+
+// WithMaxNameLength rejects seeds longer than n bytes with 400 Bad
+// Request; the default is DefaultMaxNameLength. A non-positive n removes
+// the limit.
+func WithMaxNameLength(n int) HandlerOption {
+	return func(h *Handler) {
+		h.maxNameLength = n
+	}
+}
+
+// WithMaxSize rejects requests for images larger than px pixels, such as
+// Gravatar's s=2048, with 400 Bad Request, bounding the work of one
+// request. By default only the limits of each URL scheme apply.
+func WithMaxSize(px int) HandlerOption {
+	return func(h *Handler) {
+		h.maxSize = px
+	}
+}
+
+// remoteIP returns the host of r.RemoteAddr.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimiter is a token bucket per client.
+type rateLimiter struct {
+	rate, burst float64
+
+	mu        sync.Mutex
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rps, burst: float64(burst), clients: make(map[string]*tokenBucket)}
+}
+
+// allow takes a token from client's bucket at now, or reports how long
+// until one is available.
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) >= rateSweepInterval {
+		l.sweep(now)
+	}
+	b := l.clients[client]
+	if b == nil {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops clients whose bucket has refilled, bounding memory to the
+// clients seen recently.
+func (l *rateLimiter) sweep(now time.Time) {
+	for k, b := range l.clients {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.clients, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+// retryAfter formats d as a Retry-After value in whole seconds, at least 1.
+func retryAfter(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
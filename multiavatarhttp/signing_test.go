@@ -0,0 +1,62 @@
+package multiavatarhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/changzee/multiavatar-go/multiavatarhttp"
+)
+
+func TestSignedURLUnderStripPrefix(t *testing.T) {
+	key := []byte("secret")
+	mux := http.NewServeMux()
+	mux.Handle("/avatar/", http.StripPrefix("/avatar", multiavatarhttp.NewGravatarHandler(multiavatarhttp.WithSigningKey(key))))
+	mux.Handle("/names/", http.StripPrefix("/names", multiavatarhttp.NewHandler(multiavatarhttp.WithSigningKey(key))))
+
+	signed := func(raw string) string {
+		u, err := multiavatarhttp.SignURL(key, raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	tests := []struct {
+		name   string
+		target string
+		code   int
+	}{
+		{"gravatar", signed("/avatar/205e460b479e2e5b48aec07710c08d50?s=64"), http.StatusOK},
+		{"path style", signed("/names/alice.svg?theme=B"), http.StatusOK},
+		{"unsigned", "/avatar/205e460b479e2e5b48aec07710c08d50?s=64", http.StatusForbidden},
+		{"tampered query", strings.Replace(signed("/names/alice.svg?theme=B"), "theme=B", "theme=C", 1), http.StatusForbidden},
+		{"stripped path signed", strings.Replace(signed("/alice.svg?theme=B"), "/alice", "/names/alice", 1), http.StatusForbidden},
+		{"other mount", strings.Replace(signed("/names/alice.svg"), "/names/", "/avatar/", 1), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+			if w.Code != tt.code {
+				t.Errorf("GET %s: got status %d, want %d", tt.target, w.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestSignedURLWithoutRequestURI(t *testing.T) {
+	key := []byte("secret")
+	h := multiavatarhttp.NewHandler(multiavatarhttp.WithSigningKey(key))
+	u, err := multiavatarhttp.SignURL(key, "/avatar?name=alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("GET", u, nil)
+	r.RequestURI = ""
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("got status %d, want 200", w.Code)
+	}
+}
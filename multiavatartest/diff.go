@@ -0,0 +1,157 @@
+package multiavatartest
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// node is an element of a parsed SVG document.
+type node struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*node
+}
+
+// Diff compares two SVG documents structurally and describes every
+// difference, located by an element path such as svg/g[1]/path[3]. It
+// ignores attribute order, the order of style declarations and whitespace
+// between elements. An empty result means the documents are equivalent.
+func Diff(want, got string) ([]string, error) {
+	w, err := parse(want)
+	if err != nil {
+		return nil, fmt.Errorf("parse want: %w", err)
+	}
+	g, err := parse(got)
+	if err != nil {
+		return nil, fmt.Errorf("parse got: %w", err)
+	}
+	var diffs []string
+	compare(w.name, w, g, &diffs)
+	return diffs, nil
+}
+
+// parse reads the root element of an SVG document.
+func parse(doc string) (*node, error) {
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	var stack []*node
+	var root *node
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: tok.Name.Local, attrs: make(map[string]string, len(tok.Attr))}
+			for _, a := range tok.Attr {
+				if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+					continue
+				}
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += strings.TrimSpace(string(tok))
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element")
+	}
+	return root, nil
+}
+
+func compare(path string, want, got *node, diffs *[]string) {
+	if want.name != got.name {
+		*diffs = append(*diffs, fmt.Sprintf("%s: want <%s>, got <%s>", path, want.name, got.name))
+		return
+	}
+	for _, k := range attrNames(want, got) {
+		wv, wok := want.attrs[k]
+		gv, gok := got.attrs[k]
+		switch {
+		case !gok:
+			*diffs = append(*diffs, fmt.Sprintf("%s: missing attribute %s=%q", path, k, wv))
+		case !wok:
+			*diffs = append(*diffs, fmt.Sprintf("%s: unexpected attribute %s=%q", path, k, gv))
+		case k == "style" && normalizeStyle(wv) != normalizeStyle(gv),
+			k != "style" && wv != gv:
+			*diffs = append(*diffs, fmt.Sprintf("%s: attribute %s: want %q, got %q", path, k, wv, gv))
+		}
+	}
+	if want.text != got.text {
+		*diffs = append(*diffs, fmt.Sprintf("%s: text: want %q, got %q", path, want.text, got.text))
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < max(len(want.children), len(got.children)); i++ {
+		var name string
+		if i < len(want.children) {
+			name = want.children[i].name
+		} else {
+			name = got.children[i].name
+		}
+		counts[name]++
+		child := path + "/" + name + "[" + strconv.Itoa(counts[name]) + "]"
+		switch {
+		case i >= len(got.children):
+			*diffs = append(*diffs, fmt.Sprintf("%s: missing element", child))
+		case i >= len(want.children):
+			*diffs = append(*diffs, fmt.Sprintf("%s: unexpected element", child))
+		default:
+			compare(child, want.children[i], got.children[i], diffs)
+		}
+	}
+}
+
+// attrNames returns the attribute names of both elements, sorted.
+func attrNames(a, b *node) []string {
+	names := make([]string, 0, len(a.attrs)+len(b.attrs))
+	for k := range a.attrs {
+		names = append(names, k)
+	}
+	for k := range b.attrs {
+		if _, ok := a.attrs[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// normalizeStyle sorts the declarations of a style attribute and drops
+// insignificant whitespace. Of repeated properties only the last applies.
+func normalizeStyle(s string) string {
+	props := make(map[string]string)
+	for _, d := range strings.Split(s, ";") {
+		k, v, ok := strings.Cut(d, ":")
+		if !ok {
+			continue
+		}
+		props[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+	}
+	decls := make([]string, 0, len(props))
+	for k, v := range props {
+		decls = append(decls, k+":"+v)
+	}
+	sort.Strings(decls)
+	return strings.Join(decls, ";")
+}
@@ -0,0 +1,90 @@
+// Package multiavatartest helps applications pin the avatars they generate
+// with golden files, so upgrading multiavatar-go or changing options cannot
+// silently change every user's avatar.
+//
+//	func TestAvatars(t *testing.T) {
+//		multiavatartest.AssertAvatar(t, "alice-dark", "alice",
+//			multiavatar.WithTheme("C"), multiavatar.WithoutBackground())
+//	}
+//
+// Goldens live in testdata/<name>.svg next to the test. Create or refresh
+// them with
+//
+//	go test ./... -multiavatar.update
+//
+// and review the diff before committing. Documents are compared
+// structurally: attribute order, declaration order in style attributes and
+// whitespace between elements do not matter.
+package multiavatartest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+var update = flag.Bool("multiavatar.update", false, "rewrite multiavatartest golden files")
+
+// maxReported bounds the differences listed in a failure.
+const maxReported = 10
+
+// GoldenPath returns the path of the golden file for name.
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".svg")
+}
+
+// AssertAvatar generates the avatar for input with opts and compares it to
+// the golden file for name, like AssertGolden.
+func AssertAvatar(t testing.TB, name, input string, opts ...multiavatar.Option) {
+	t.Helper()
+	svg, err := multiavatar.GenerateContext(t.Context(), input, opts...)
+	if err != nil {
+		t.Fatalf("multiavatartest: generate %q: %v", input, err)
+	}
+	AssertGolden(t, name, svg)
+}
+
+// AssertGolden compares svg to the golden file for name and fails t with
+// the structural differences. With -multiavatar.update it writes svg to
+// the golden file instead.
+func AssertGolden(t testing.TB, name, svg string) {
+	t.Helper()
+	path := GoldenPath(name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("multiavatartest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(svg), 0o644); err != nil {
+			t.Fatalf("multiavatartest: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("multiavatartest: missing golden %s; run go test with -multiavatar.update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("multiavatartest: %v", err)
+	}
+	diffs, err := Diff(string(want), svg)
+	if err != nil {
+		t.Fatalf("multiavatartest: %s: %v", path, err)
+	}
+	if len(diffs) == 0 {
+		return
+	}
+	msg := fmt.Sprintf("avatar differs from golden %s:", path)
+	for i, d := range diffs {
+		if i == maxReported {
+			msg += fmt.Sprintf("\n\t... and %d more", len(diffs)-maxReported)
+			break
+		}
+		msg += "\n\t" + d
+	}
+	t.Errorf("%s\nrun go test with -multiavatar.update to accept the new output", msg)
+}
@@ -0,0 +1,56 @@
+package multiavatar
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization is a Unicode normalization form applied to the input
+// before hashing.
+type Normalization int
+
+const (
+	// NormalizationNFC composes characters canonically, so "é" typed as
+	// one code point or as "e" and a combining acute accent gets the same
+	// avatar. It is the default.
+	NormalizationNFC Normalization = iota
+	// NormalizationNFKC also folds compatibility characters, such as
+	// fullwidth "Ａ" to "A" and the ligature "ﬁ" to "fi".
+	NormalizationNFKC
+	// NormalizationNone hashes the input bytes as given, as releases
+	// before Unicode normalization did.
+	NormalizationNone
+)
+
+// WithUnicodeNormalization sets the Unicode normalization form applied to
+// the input before hashing, so visually identical names in different
+// encodings, as typed on different keyboards and platforms, produce the
+// same avatar. Inputs that are already normalized, including all ASCII
+// inputs, are unaffected. WithCompatV1 and GenerateFromBytes do not
+// normalize. Unknown forms are reported as errors by the error-returning
+// APIs.
+func WithUnicodeNormalization(form Normalization) Option {
+	return func(c *config) {
+		if form < NormalizationNFC || form > NormalizationNone {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown Unicode normalization %d", int(form)))
+			return
+		}
+		c.normalization = form
+	}
+}
+
+// normalizeInput returns input as it is hashed: in the configured Unicode
+// normalization form and, with WithEmailNormalization, as an email address.
+func (cfg *config) normalizeInput(input string) string {
+	switch cfg.normalization {
+	case NormalizationNFC:
+		input = norm.NFC.String(input)
+	case NormalizationNFKC:
+		input = norm.NFKC.String(input)
+	}
+	if cfg.normalizeEmail {
+		input = NormalizeEmail(input)
+	}
+	return input
+}
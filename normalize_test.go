@@ -0,0 +1,54 @@
+package multiavatar_test
+
+import (
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestNormalizationComposedAccents(t *testing.T) {
+	nfc, nfd := "jos\u00e9", "jose\u0301"
+	if multiavatar.Generate(nfc) != multiavatar.Generate(nfd) {
+		t.Error("NFC and NFD spellings differ by default")
+	}
+	none := multiavatar.WithUnicodeNormalization(multiavatar.NormalizationNone)
+	if multiavatar.Generate(nfc, none) == multiavatar.Generate(nfd, none) {
+		t.Error("NFC and NFD spellings match with NormalizationNone")
+	}
+}
+
+func TestNormalizationNFKC(t *testing.T) {
+	nfkc := multiavatar.WithUnicodeNormalization(multiavatar.NormalizationNFKC)
+	if multiavatar.Generate("ａｌｉｃｅ", nfkc) != multiavatar.Generate("alice") {
+		t.Error(`NFKC does not fold "ａｌｉｃｅ" to "alice"`)
+	}
+	if multiavatar.Generate("ａｌｉｃｅ") == multiavatar.Generate("alice") {
+		t.Error(`NFC folds "ａｌｉｃｅ" to "alice"`)
+	}
+}
+
+func TestNormalizationStableInputs(t *testing.T) {
+	inputs := []string{
+		"alice",
+		"李小龙",
+		"さくら",
+		"김민준",
+		"😀",
+		"👩‍👩‍👧‍👦", // family, ZWJ sequence
+		"🏳️‍🌈",    // rainbow flag, ZWJ sequence with a variation selector
+		"👍🏽",      // skin tone modifier
+	}
+	forms := []multiavatar.Normalization{
+		multiavatar.NormalizationNFC,
+		multiavatar.NormalizationNFKC,
+		multiavatar.NormalizationNone,
+	}
+	for _, in := range inputs {
+		want := multiavatar.Generate(in, multiavatar.WithUnicodeNormalization(multiavatar.NormalizationNone))
+		for _, form := range forms {
+			if got := multiavatar.Generate(in, multiavatar.WithUnicodeNormalization(form)); got != want {
+				t.Errorf("Generate(%q) with normalization %d differs from the unnormalized avatar", in, form)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithOpacity draws a part with the given opacity, from 0 (invisible) to 1
+// (opaque), e.g. WithOpacity("env", 0.5) for a semi-transparent background
+// or a faded look for disabled accounts. The part is wrapped in a
+// <g opacity>. Values outside 0..1 are reported as errors by the
+// error-returning APIs, and unknown parts as ErrInvalidPart.
+func WithOpacity(part string, alpha float64) Option {
+	return func(c *config) {
+		pn := strings.TrimSpace(part)
+		if !c.checkPartName(pn) {
+			return
+		}
+		if !(alpha >= 0 && alpha <= 1) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid opacity %v for part %q", alpha, pn))
+			return
+		}
+		if c.partOpacity == nil {
+			c.partOpacity = make(map[string]float64)
+		}
+		c.partOpacity[pn] = alpha
+	}
+}
+
+// fadePart wraps the rendered svg of a part in its configured opacity.
+func (cfg *config) fadePart(name, svg string) string {
+	a, ok := cfg.partOpacity[name]
+	if !ok || a == 1 || svg == "" {
+		return svg
+	}
+	return `<g opacity="` + formatFloat(a) + `">` + svg + `</g>`
+}
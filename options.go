@@ -0,0 +1,200 @@
+package multiavatar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Options is a serializable description of generation options.
+//
+// It lets customizations chosen elsewhere (for example in a frontend avatar
+// editor) be stored as JSON, e.g. in a database column, and replayed later:
+//
+//	opts, err := multiavatar.FromJSON(row.AvatarOptions)
+//	svg := multiavatar.Generate(user.ID, opts...)
+//
+// Map keys are part names: "env", "clo", "head", "mouth", "eyes", "top",
+// and the optional parts enabled by WithParts: "hat" and "accessory".
+// PartVersions values are version codes ("07") or registered names ("afro").
+type Options struct {
+	Algorithm         int                 `json:"algorithm,omitempty"`
+	Style             string              `json:"style,omitempty"`
+	Theme             string              `json:"theme,omitempty"`
+	Gender            string              `json:"gender,omitempty"`
+	Expression        string              `json:"expression,omitempty"`
+	WithoutBackground bool                `json:"withoutBackground,omitempty"`
+	BackgroundShape   string              `json:"backgroundShape,omitempty"`
+	HarmoniousColors  bool                `json:"harmoniousColors,omitempty"`
+	Mirror            bool                `json:"mirror,omitempty"`
+	Rotation          float64             `json:"rotation,omitempty"`
+	Frame             string              `json:"frame,omitempty"`
+	Size              int                 `json:"size,omitempty"`
+	PartVersions      map[string]string   `json:"partVersions,omitempty"`
+	AllowedVersions   map[string][]string `json:"allowedVersions,omitempty"`
+	PartThemes        map[string]string   `json:"partThemes,omitempty"`
+	AllowedThemes     map[string][]string `json:"allowedThemes,omitempty"`
+	Colors            map[string][]string `json:"colors,omitempty"`
+	WithParts         []string            `json:"withParts,omitempty"`
+	WithoutParts      []string            `json:"withoutParts,omitempty"`
+}
+
+// FromJSON decodes an Options document and returns the equivalent option list.
+// Unknown fields, part names and invalid colors are rejected.
+func FromJSON(data []byte) ([]Option, error) {
+	var o Options
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, err
+	}
+	return o.ToOptions(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, rejecting unknown fields and
+// parts, and colors WithPartColors does not accept.
+func (o *Options) UnmarshalJSON(data []byte) error {
+	// alias drops the method set to avoid recursion
+	type alias Options
+	var a alias
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&a); err != nil {
+		return fmt.Errorf("multiavatar: decode options: %w", err)
+	}
+	if err := (*Options)(&a).validateParts(); err != nil {
+		return err
+	}
+	for p, v := range a.PartVersions {
+		if _, ok := PartVersionByName(p, v); !ok && !validPartVersion(p, v) {
+			return ErrInvalidVersion{Part: p, Version: v}
+		}
+	}
+	for _, p := range sortedKeys(a.Colors) {
+		for _, col := range a.Colors[p] {
+			if err := checkColor(strings.TrimSpace(col)); err != nil {
+				return fmt.Errorf("%w for part %q", err, p)
+			}
+		}
+	}
+	if a.Algorithm != 0 && !IsKnownAlgorithm(a.Algorithm) {
+		return fmt.Errorf("multiavatar: unknown algorithm %d", a.Algorithm)
+	}
+	if _, ok := ParseExpression(a.Expression); a.Expression != "" && !ok {
+		return fmt.Errorf("multiavatar: unknown expression %q", a.Expression)
+	}
+	if _, ok := ParseBackgroundShape(a.BackgroundShape); a.BackgroundShape != "" && !ok {
+		return fmt.Errorf("multiavatar: unknown background shape %q", a.BackgroundShape)
+	}
+	*o = Options(a)
+	return nil
+}
+
+// ToOptions converts o into functional options. The gender preset is applied
+// first so explicit versions and themes take precedence over it.
+func (o Options) ToOptions() []Option {
+	var opts []Option
+	if o.Algorithm != 0 {
+		opts = append(opts, WithAlgorithm(Algorithm(o.Algorithm)))
+	}
+	if o.Style != "" {
+		opts = append(opts, WithStyle(o.Style))
+	}
+	if o.Gender != "" {
+		opts = append(opts, WithGender(o.Gender))
+	}
+	if e, ok := ParseExpression(o.Expression); ok {
+		opts = append(opts, WithExpression(e))
+	}
+	if o.Theme != "" {
+		opts = append(opts, WithTheme(o.Theme))
+	}
+	if o.WithoutBackground {
+		opts = append(opts, WithoutBackground())
+	}
+	if sh, ok := ParseBackgroundShape(o.BackgroundShape); ok {
+		opts = append(opts, WithBackgroundShape(sh))
+	}
+	if o.HarmoniousColors {
+		opts = append(opts, WithHarmoniousColors())
+	}
+	if o.Mirror {
+		opts = append(opts, WithMirror())
+	}
+	if o.Rotation != 0 {
+		opts = append(opts, WithRotation(o.Rotation))
+	}
+	if o.Frame != "" {
+		opts = append(opts, WithFrame(o.Frame))
+	}
+	if o.Size > 0 {
+		opts = append(opts, WithSize(o.Size))
+	}
+	for _, p := range sortedKeys(o.AllowedVersions) {
+		opts = append(opts, WithAllowedVersions(p, o.AllowedVersions[p]))
+	}
+	for _, p := range sortedKeys(o.PartVersions) {
+		opts = append(opts, withPartVersionOrName(p, o.PartVersions[p]))
+	}
+	for _, p := range sortedKeys(o.AllowedThemes) {
+		opts = append(opts, WithAllowedThemes(p, o.AllowedThemes[p]))
+	}
+	for _, p := range sortedKeys(o.PartThemes) {
+		opts = append(opts, WithPartTheme(p, o.PartThemes[p]))
+	}
+	for _, p := range sortedKeys(o.Colors) {
+		opts = append(opts, WithPartColors(p, o.Colors[p]))
+	}
+	for _, p := range o.WithParts {
+		opts = append(opts, WithPart(p))
+	}
+	for _, p := range o.WithoutParts {
+		opts = append(opts, WithoutPart(p))
+	}
+	return opts
+}
+
+func (o *Options) validateParts() error {
+	check := func(part string) error {
+		switch part {
+		case "env", "clo", "head", "mouth", "eyes", "top", "hat", "accessory":
+			return nil
+		}
+		return ErrInvalidPart{Name: part}
+	}
+	for _, m := range []map[string]string{o.PartVersions, o.PartThemes} {
+		for p := range m {
+			if err := check(p); err != nil {
+				return err
+			}
+		}
+	}
+	for _, m := range []map[string][]string{o.AllowedVersions, o.AllowedThemes, o.Colors} {
+		for p := range m {
+			if err := check(p); err != nil {
+				return err
+			}
+		}
+	}
+	for _, p := range o.WithoutParts {
+		if err := check(p); err != nil {
+			return err
+		}
+	}
+	for _, p := range o.WithParts {
+		if extraPartByName(p) == nil {
+			return ErrInvalidPart{Name: p}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in a stable order so option lists are reproducible.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,32 @@
+package multiavatar_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestFromJSONColors(t *testing.T) {
+	tests := []struct {
+		doc, bad string
+	}{
+		{`{"colors":{"env":["#ff0000"],"clo":["red","rgb(0,0,255)"]}}`, ""},
+		{`{"colors":{"env":["url(javascript:alert(1))"]}}`, "url(javascript:alert(1))"},
+		{`{"colors":{"top":["#333","#12345"]}}`, "#12345"},
+		{`{"colors":{"head":["\"/><script>"]}}`, `"/><script>`},
+	}
+	for _, tt := range tests {
+		_, err := multiavatar.FromJSON([]byte(tt.doc))
+		if tt.bad == "" {
+			if err != nil {
+				t.Errorf("FromJSON(%s): %v", tt.doc, err)
+			}
+			continue
+		}
+		var cerr multiavatar.ErrInvalidColor
+		if !errors.As(err, &cerr) || cerr.Value != tt.bad {
+			t.Errorf("FromJSON(%s): got %v, want ErrInvalidColor{%q}", tt.doc, err, tt.bad)
+		}
+	}
+}
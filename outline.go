@@ -0,0 +1,83 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// outline is the stroke of WithOutlineStyle.
+type outline struct {
+	color string
+	width float64
+}
+
+// WithOutlineStyle draws every part as an outline of the given color and
+// width (in avatar units) instead of filled shapes, reusing the geometry
+// of the artwork, for line-art avatars in print, coloring-book exports and
+// minimalist UIs. The background is outlined too; leave it out with
+// WithoutBackground for a plain drawing. Borders, frames, overlays and
+// badges keep their look. Invalid colors and widths outside (0, 115.5] are
+// reported as errors by the error-returning APIs.
+func WithOutlineStyle(strokeColor string, width float64) Option {
+	return func(c *config) {
+		strokeColor = strings.TrimSpace(strokeColor)
+		if err := checkColor(strokeColor); err != nil {
+			c.errs = append(c.errs, fmt.Errorf("%w for outline", err))
+			return
+		}
+		if !(width > 0 && width <= canvasSize/2) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid outline width %v", width))
+			return
+		}
+		c.outline = &outline{color: cssColor(strokeColor), width: width}
+	}
+}
+
+// outlineProps are the style properties an outline replaces.
+var outlineProps = map[string]bool{
+	"fill": true, "fill-opacity": true, "stroke": true, "stroke-width": true, "stroke-opacity": true,
+	"stroke-linecap": true, "stroke-linejoin": true, "stroke-dasharray": true,
+}
+
+// write writes svg to b with the fill and stroke of every element replaced
+// by the outline; elements without their own style inherit it from the
+// enclosing group.
+func (o *outline) write(b svgWriter, filter func(string) string, svg string) {
+	decls := "fill:none;stroke:" + safeColor(filter(o.color)) + ";stroke-width:" + formatFloat(o.width) +
+		";stroke-linecap:round;stroke-linejoin:round;"
+	b.WriteString(`<g style="` + decls + `">`)
+	rest := svg
+	for {
+		i := strings.Index(rest, ` style="`)
+		j := strings.Index(rest, ` fill="`)
+		if j >= 0 && (i < 0 || j < i) {
+			// a fill attribute would override the inherited outline
+			end := strings.IndexByte(rest[j+len(` fill="`):], '"')
+			if end < 0 {
+				break
+			}
+			b.WriteString(rest[:j])
+			rest = rest[j+len(` fill="`)+end+1:]
+			continue
+		}
+		if i < 0 {
+			break
+		}
+		start := i + len(` style="`)
+		end := strings.IndexByte(rest[start:], '"')
+		if end < 0 {
+			break
+		}
+		b.WriteString(rest[:start])
+		for _, d := range strings.Split(rest[start:start+end], ";") {
+			prop, _, _ := strings.Cut(d, ":")
+			if prop = strings.TrimSpace(prop); prop != "" && !outlineProps[prop] {
+				b.WriteString(strings.TrimSpace(d) + ";")
+			}
+		}
+		b.WriteString(decls)
+		rest = rest[start+end:]
+	}
+	b.WriteString(rest)
+	b.WriteString(`</g>`)
+}
@@ -0,0 +1,79 @@
+package multiavatar
+
+// Overlay is a decoration drawn above all parts, such as a seasonal hat.
+type Overlay struct {
+	// art is built-in markup in avatar coordinates, with "#1;", "#2;", ...
+	// placeholders for colors
+	art    string
+	colors []string
+	// custom is a sanitized fragment drawn in viewBox, scaled to the avatar
+	custom  string
+	viewBox [4]float64
+	err     error
+}
+
+// Built-in overlays for seasonal campaigns: a Santa hat and a party hat on
+// top of the head, and a jack-o'-lantern in the bottom-left corner, clear
+// of the default badge corner.
+var (
+	OverlaySanta = Overlay{
+		art: `<path d="M56,78C62,34 98,12 140,16C170,20 190,44 198,92L184,96C178,70 168,54 156,46C164,60 172,70 176,78Z" style="fill:#1;"/>` +
+			`<rect x="44" y="68" width="143" height="24" rx="12" style="fill:#2;"/><circle cx="191" cy="100" r="13" style="fill:#2;"/>`,
+		colors: []string{"#d42a2f", "#f4f4f4"},
+	}
+	OverlayPartyHat = Overlay{
+		art: `<path d="M84,68L121,6L150,62Z" style="fill:#1;"/><path d="M93.25,52.5L98.8,43.2L138.4,39.6L142.75,48Z" style="fill:#2;"/>` +
+			`<path d="M106.2,30.8L110.6,23.4L129.1,21.7L132.6,28.4Z" style="fill:#2;"/><circle cx="121" cy="9" r="8" style="fill:#3;"/>`,
+		colors: []string{"#3a8ee6", "#ffd23f", "#ef476f"},
+	}
+	OverlayPumpkin = Overlay{
+		art: `<path d="M48,166Q50,154 58,150L61,154Q55,158 55,166Z" style="fill:#3;"/><ellipse cx="38" cy="192" rx="22" ry="28" style="fill:#1;"/>` +
+			`<ellipse cx="66" cy="192" rx="22" ry="28" style="fill:#1;"/><ellipse cx="52" cy="192" rx="18" ry="30" style="fill:#2;"/>` +
+			`<path d="M36,186L42,176L48,186ZM56,186L62,176L68,186ZM36,198Q52,212 68,198Q60,202 56,200L52,204L48,200Q44,202 36,198Z" style="fill:#4;"/>`,
+		colors: []string{"#f07c14", "#f8922e", "#4f8a2b", "#5a2a0a"},
+	}
+)
+
+// OverlayCustom returns an overlay that draws svgFragment. Like
+// WithClothingLogo, the fragment is sanitized; it is drawn in avatar
+// coordinates (0 0 231 231) unless it is an <svg> element with its own
+// viewBox, which is then scaled to cover the avatar.
+func OverlayCustom(svgFragment string) Overlay {
+	frag, vb, err := sanitizeFragment(svgFragment)
+	if err != nil {
+		return Overlay{err: err}
+	}
+	if vb[2] == 0 {
+		vb = [4]float64{0, 0, canvasSize, canvasSize}
+	}
+	return Overlay{custom: frag, viewBox: vb}
+}
+
+// WithOverlay draws a decoration above all parts and the border and below
+// any badge, at the same place at every size, so seasonal campaigns need no
+// image post-processing. Invalid custom overlays are reported by the
+// error-returning APIs.
+func WithOverlay(o Overlay) Option {
+	return func(c *config) {
+		if o.err != nil {
+			c.errs = append(c.errs, o.err)
+			return
+		}
+		c.overlay = &o
+	}
+}
+
+// render returns the overlay markup in avatar coordinates. filter maps the
+// colors of built-in overlays.
+func (o *Overlay) render(filter func(string) string) string {
+	if o.custom != "" {
+		return `<svg x="0" y="0" width="231" height="231" viewBox="` + formatFloat(o.viewBox[0]) + " " +
+			formatFloat(o.viewBox[1]) + " " + formatFloat(o.viewBox[2]) + " " + formatFloat(o.viewBox[3]) + `">` +
+			o.custom + `</svg>`
+	}
+	colors := make([]string, len(o.colors))
+	for i, c := range o.colors {
+		colors[i] = filter(c)
+	}
+	return fillColors(o.art, colors)
+}
@@ -0,0 +1,84 @@
+package multiavatar
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// WithPalette snaps every resolved color to the nearest color of palette,
+// measured in CIE Lab space (CIE76 distance), so avatars only use the
+// tokens of a design system. Alpha is kept. Colors drawn into the art
+// itself, such as the white of the teeth, are unaffected. An empty palette
+// or an invalid color is reported as an error by the error-returning APIs.
+func WithPalette(palette []string) Option {
+	return func(c *config) {
+		if len(palette) == 0 {
+			c.errs = append(c.errs, errors.New("multiavatar: empty palette"))
+			return
+		}
+		entries := make([]paletteEntry, 0, len(palette))
+		for _, s := range palette {
+			s = strings.TrimSpace(s)
+			if err := checkColor(s); err != nil {
+				c.errs = append(c.errs, fmt.Errorf("%w in palette", err))
+				return
+			}
+			col, _ := parseColor(s)
+			if col.A == 0 {
+				c.errs = append(c.errs, fmt.Errorf("multiavatar: transparent color %q in palette", s))
+				return
+			}
+			col.A = 255
+			entries = append(entries, paletteEntry{c: col, lab: toLab(col)})
+		}
+		c.colorFilters = append(c.colorFilters, snapTo(entries))
+	}
+}
+
+// paletteEntry is a palette color with its precomputed Lab coordinates.
+type paletteEntry struct {
+	c   color.NRGBA
+	lab [3]float64
+}
+
+// snapTo returns a filter that replaces colors with the nearest entry.
+func snapTo(entries []paletteEntry) colorFilter {
+	return func(c color.NRGBA) color.NRGBA {
+		lab := toLab(c)
+		best, bestD := entries[0].c, math.Inf(1)
+		for _, e := range entries {
+			dl, da, db := lab[0]-e.lab[0], lab[1]-e.lab[1], lab[2]-e.lab[2]
+			if d := dl*dl + da*da + db*db; d < bestD {
+				best, bestD = e.c, d
+			}
+		}
+		best.A = c.A
+		return best
+	}
+}
+
+// toLab converts an sRGB color to CIE L*a*b* under the D65 white point.
+func toLab(c color.NRGBA) [3]float64 {
+	lin := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.04045 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	r, g, b := lin(c.R), lin(c.G), lin(c.B)
+	x := (0.4124*r + 0.3576*g + 0.1805*b) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := (0.0193*r + 0.1192*g + 0.9505*b) / 1.08883
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
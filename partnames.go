@@ -0,0 +1,126 @@
+package multiavatar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// versionNames holds the built-in friendly names of part versions, indexed
+// by version. "env" and "head" have no names: their versions differ only in
+// color. The head shapes beyond them are named in headshape.go.
+var versionNames = map[string][16]string{
+	"clo": {
+		"circuit", "crew-neck", "sweater", "v-neck", "t-shirt", "white-tee", "jacket", "tank-top",
+		"hoodie", "collar", "vest", "pendant", "scoop-neck", "overalls", "round-neck", "mesh",
+	},
+	"mouth": {
+		"robot-grin", "cat-mouth", "lips", "open-smile", "mustache", "small-smile", "tooth", "pout",
+		"laugh", "flat", "handlebar", "soul-patch", "smirk", "beard", "goatee", "grin",
+	},
+	"eyes": {
+		"visor", "closed", "sunglasses", "squint", "happy", "eye-patch", "side-eye", "monocle",
+		"sleepy", "pixel-shades", "wink", "dots", "goggles", "ovals", "relaxed", "glance",
+	},
+	"top": {
+		"antenna", "buns", "long-hair", "bob", "fedora", "short-hair", "top-knot", "mohawk",
+		"afro", "long-wavy", "curly", "spiky", "ponytail", "flat-top", "headband", "crew-cut",
+	},
+}
+
+var (
+	aliasesMu sync.RWMutex
+	// aliases maps part -> name -> version code, built-in and registered
+	aliases = make(map[string]map[string]string)
+)
+
+func init() {
+	for part, names := range versionNames {
+		for v, name := range names {
+			RegisterPartAlias(part, name, fmt.Sprintf("%02d", v))
+		}
+	}
+}
+
+// RegisterPartAlias makes name usable in place of version code version
+// ("00".."15", or a head shape) of part, e.g. in WithNamedPart. Names are case-insensitive.
+// It panics if part or version is unknown, or name is empty or already
+// registered for part.
+func RegisterPartAlias(part, name, version string) {
+	if _, ok := partIndex[part]; !ok && extraPartByName(part) == nil {
+		panic("multiavatar: RegisterPartAlias unknown part " + part)
+	}
+	if !validPartVersion(part, version) {
+		panic("multiavatar: RegisterPartAlias invalid version " + version)
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		panic("multiavatar: RegisterPartAlias name is empty")
+	}
+
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	if aliases[part] == nil {
+		aliases[part] = make(map[string]string)
+	}
+	if _, dup := aliases[part][name]; dup {
+		panic("multiavatar: RegisterPartAlias called twice for " + part + " " + name)
+	}
+	aliases[part][name] = version
+}
+
+// PartVersionByName returns the version code registered as name for part.
+func PartVersionByName(part, name string) (string, bool) {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	v, ok := aliases[part][strings.ToLower(strings.TrimSpace(name))]
+	return v, ok
+}
+
+// PartNames returns the names registered for part, sorted by version and
+// then by name, for building pickers in product UIs.
+func PartNames(part string) []string {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	names := make([]string, 0, len(aliases[part]))
+	for name := range aliases[part] {
+		names = append(names, name)
+	}
+	m := aliases[part]
+	sort.Slice(names, func(i, j int) bool {
+		if m[names[i]] != m[names[j]] {
+			return m[names[i]] < m[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// WithNamedPart forces part to the version registered as name, e.g.
+// WithNamedPart("top", "afro"). Unknown names are ignored and reported by
+// the error-returning APIs.
+func WithNamedPart(part, name string) Option {
+	return func(c *config) {
+		v, ok := PartVersionByName(strings.TrimSpace(part), name)
+		if !ok {
+			c.errs = append(c.errs, ErrInvalidVersion{Part: part, Version: name})
+			return
+		}
+		WithPartVersion(part, v)(c)
+	}
+}
+
+// withPartVersionOrName forces part to a version given either as a code
+// ("07") or as a registered name ("afro").
+func withPartVersionOrName(part, value string) Option {
+	if validPartVersion(part, strings.TrimSpace(value)) {
+		return WithPartVersion(part, value)
+	}
+	return WithNamedPart(part, value)
+}
+
+// validVersion reports whether v is a version code "00".."15".
+func validVersion(v string) bool {
+	return len(v) == 2 && (v[0] == '0' && v[1] >= '0' && v[1] <= '9' || v[0] == '1' && v[1] >= '0' && v[1] <= '5')
+}
@@ -0,0 +1,29 @@
+//go:build !tinygo && !multiavatar_minimal
+
+package multiavatar
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"io"
+)
+
+// partsGz is the part art written by build/main.go, gzip-compressed to about
+// a third of its size. It is inflated once, on first use.
+//
+//go:embed parts.svg.gz
+var partsGz []byte
+
+// loadPartsData returns the part templates, one per line.
+func loadPartsData() string {
+	zr, err := gzip.NewReader(bytes.NewReader(partsGz))
+	if err != nil {
+		panic("multiavatar: corrupt embedded art: " + err.Error())
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		panic("multiavatar: corrupt embedded art: " + err.Error())
+	}
+	return string(data)
+}
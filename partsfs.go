@@ -0,0 +1,99 @@
+package multiavatar
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// artSet is art loaded with LoadPartsFromFS, in the layout of the built-in
+// tables.
+type artSet struct {
+	templates [][]partTemplate
+	themes    map[string]map[string]map[string][]string
+}
+
+// loadedArt is the art installed by LoadPartsFromFS; nil uses the embedded art.
+var loadedArt atomic.Pointer[artSet]
+
+// themesFile is the palette file read by LoadPartsFromFS.
+const themesFile = "themes.txt"
+
+// LoadPartsFromFS replaces the built-in art with the part templates and
+// palettes found in fsys, so designers can iterate on the art without
+// recompiling. Templates are read from <part>/<version>.svg, e.g.
+// head/03.svg, in the format of ThemePack.Template. Palettes are read from
+// themes.txt, one line per version, theme and part, with "#" starting a
+// comment line:
+//
+//	# warmer skin tones
+//	03 A head #f4c59e
+//	03 B head #8d5524
+//
+// Parts, versions and themes without a file or line keep the embedded art.
+// Call it again, e.g. when the files change, to reload; the new art
+// applies to avatars generated afterwards. LoadPartsFromFS(nil) restores
+// the embedded art. On error the current art is left in place.
+//
+// The files are trusted like the embedded art: templates are not
+// sanitized.
+func LoadPartsFromFS(fsys fs.FS) error {
+	if fsys == nil {
+		loadedArt.Store(nil)
+		return nil
+	}
+	builtin := builtinTemplates()
+	art := &artSet{
+		templates: make([][]partTemplate, len(builtin)),
+		themes:    maps.Clone(builtinThemes()),
+	}
+	for v, row := range builtin {
+		art.templates[v] = slices.Clone(row)
+		for i, name := range partNames {
+			data, err := fs.ReadFile(fsys, fmt.Sprintf("%s/%02d.svg", name, v))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("multiavatar: load parts: %w", err)
+			}
+			svg := strings.TrimSpace(string(data))
+			art.templates[v][i] = partTemplate{svg: svg, placeholders: findPlaceholders(svg)}
+		}
+	}
+
+	data, err := fs.ReadFile(fsys, themesFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("multiavatar: load parts: %w", err)
+	}
+	for n, line := range strings.Split(string(data), "\n") {
+		f := strings.Fields(line)
+		if len(f) == 0 || strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		if len(f) < 3 {
+			return fmt.Errorf("multiavatar: %s:%d: want <version> <theme> <part> <colors...>", themesFile, n+1)
+		}
+		version, theme, part := f[0], f[1], f[2]
+		if v, err := strconv.Atoi(version); err != nil || v < 0 || v >= len(builtin) || len(version) != 2 {
+			return fmt.Errorf("multiavatar: %s:%d: invalid version %q", themesFile, n+1, version)
+		}
+		if theme != "A" && theme != "B" && theme != "C" {
+			return fmt.Errorf("multiavatar: %s:%d: invalid theme %q", themesFile, n+1, theme)
+		}
+		if _, ok := partIndex[part]; !ok {
+			return fmt.Errorf("multiavatar: %s:%d: unknown part %q", themesFile, n+1, part)
+		}
+		// copy on write: the embedded table stays untouched
+		art.themes[version] = maps.Clone(art.themes[version])
+		art.themes[version][theme] = maps.Clone(art.themes[version][theme])
+		art.themes[version][theme][part] = f[3:]
+	}
+	loadedArt.Store(art)
+	return nil
+}
@@ -0,0 +1,101 @@
+package multiavatar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// partTransform scales and rotates a part about its center, then moves it.
+type partTransform struct {
+	scale, dx, dy, rotate float64
+}
+
+// WithPartTransform draws a part scaled by scale and rotated by rotate
+// degrees (clockwise) about its own center, then moved by dx, dy avatar
+// units, e.g. WithPartTransform("eyes", 1.2, 0, 4, 0) for larger, lower
+// eyes. The part is wrapped in a <g transform>; the embedded art is not
+// edited. A non-positive scale is reported as an error by the
+// error-returning APIs, and unknown parts as ErrInvalidPart.
+func WithPartTransform(part string, scale, dx, dy, rotate float64) Option {
+	return func(c *config) {
+		pn := strings.TrimSpace(part)
+		if !c.checkPartName(pn) {
+			return
+		}
+		for _, v := range []float64{scale, dx, dy, rotate} {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid transform for part %q", pn))
+				return
+			}
+		}
+		if scale <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid scale %v for part %q", scale, pn))
+			return
+		}
+		if c.partTransforms == nil {
+			c.partTransforms = make(map[string]partTransform)
+		}
+		c.partTransforms[pn] = partTransform{scale: scale, dx: dx, dy: dy, rotate: rotate}
+	}
+}
+
+// transformPart wraps the rendered svg of p in its configured transform.
+func (cfg *config) transformPart(p selectedPart, svg string) string {
+	t, ok := cfg.partTransforms[p.name]
+	if !ok || svg == "" {
+		return svg
+	}
+	tmpl, _ := cfg.template(p)
+	c := partCenter(tmpl.svg)
+	attr := "translate(" + formatFloat(c.x+t.dx) + " " + formatFloat(c.y+t.dy) + ")"
+	if t.rotate != 0 {
+		attr += " rotate(" + formatFloat(t.rotate) + ")"
+	}
+	if t.scale != 1 {
+		attr += " scale(" + formatFloat(t.scale) + ")"
+	}
+	attr += " translate(" + formatFloat(-c.x) + " " + formatFloat(-c.y) + ")"
+	return `<g transform="` + attr + `">` + svg + `</g>`
+}
+
+// partCenters caches the center of each part template's bounding box.
+var partCenters sync.Map // template svg -> point
+
+// partCenter returns the center of the bounding box of a part template,
+// or the canvas center if it has no geometry.
+func partCenter(svg string) point {
+	if c, ok := partCenters.Load(svg); ok {
+		return c.(point)
+	}
+	center := point{canvasSize / 2, canvasSize / 2}
+	if root, err := parseSVGTree(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg">` + svg + `</svg>`)); err == nil {
+		bd := &boundsDrawer{b: [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}}
+		drawSVG(root, bd)
+		if bd.b[0] <= bd.b[2] {
+			center = point{(bd.b[0] + bd.b[2]) / 2, (bd.b[1] + bd.b[3]) / 2}
+		}
+	}
+	partCenters.Store(svg, center)
+	return center
+}
+
+// boundsDrawer is a drawer that only accumulates the bounding box of the
+// shapes drawn, using curve control points as a conservative estimate.
+type boundsDrawer struct {
+	b [4]float64
+}
+
+func (d *boundsDrawer) fillPath(p path, m affine, _ *paint, _ bool, _ float64) {
+	d.add(p.transform(m).bounds(), 0)
+}
+
+func (d *boundsDrawer) strokePath(p path, m affine, _ *paint, st strokeStyle, _ float64) {
+	d.add(p.transform(m).bounds(), st.width*m.scale()/2)
+}
+
+func (d *boundsDrawer) add(b [4]float64, pad float64) {
+	d.b[0], d.b[1] = math.Min(d.b[0], b[0]-pad), math.Min(d.b[1], b[1]-pad)
+	d.b[2], d.b[3] = math.Max(d.b[2], b[2]+pad), math.Max(d.b[3], b[3]+pad)
+}
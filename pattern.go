@@ -0,0 +1,192 @@
+package multiavatar
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/changzee/multiavatar-go/multiavatarcolor"
+)
+
+// BackgroundPattern is a decorative pattern drawn over the background.
+type BackgroundPattern int
+
+const (
+	// PatternDots scatters a staggered grid of dots.
+	PatternDots BackgroundPattern = iota + 1
+	// PatternStripes draws diagonal stripes.
+	PatternStripes
+	// PatternConfetti scatters small rotated squares and circles.
+	PatternConfetti
+	// PatternMesh blends soft blobs of the second color into the first,
+	// like a gradient mesh.
+	PatternMesh
+)
+
+// patternNames are the BackgroundPattern names, indexed by value.
+var patternNames = []string{"", "dots", "stripes", "confetti", "mesh"}
+
+// String returns the pattern name, e.g. "dots".
+func (p BackgroundPattern) String() string {
+	if p > 0 && int(p) < len(patternNames) {
+		return patternNames[p]
+	}
+	return fmt.Sprintf("BackgroundPattern(%d)", int(p))
+}
+
+// ParseBackgroundPattern returns the pattern named s, e.g. "stripes".
+func ParseBackgroundPattern(s string) (BackgroundPattern, bool) {
+	for p := PatternDots; int(p) < len(patternNames); p++ {
+		if strings.EqualFold(s, patternNames[p]) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// bgPattern is a pattern with its two colors.
+type bgPattern struct {
+	kind         BackgroundPattern
+	base, accent string
+}
+
+// WithBackgroundPattern fills the background with color1 and draws
+// pattern over it in color2, inside the background circle, or the
+// squircle with WithBackgroundShape. The pattern is part of the
+// background: WithoutBackground removes it as well, and it takes the
+// place of a background gradient. Unknown patterns and invalid colors are
+// reported as errors by the error-returning APIs.
+func WithBackgroundPattern(pattern BackgroundPattern, color1, color2 string) Option {
+	return func(c *config) {
+		if pattern < PatternDots || int(pattern) >= len(patternNames) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown background pattern %d", int(pattern)))
+			return
+		}
+		p := &bgPattern{kind: pattern, base: strings.TrimSpace(color1), accent: strings.TrimSpace(color2)}
+		for _, col := range []string{p.base, p.accent} {
+			if err := checkColor(col); err != nil {
+				c.errs = append(c.errs, fmt.Errorf("%w in background pattern", err))
+				return
+			}
+		}
+		p.base, p.accent = cssColor(p.base), cssColor(p.accent)
+		c.bgPattern = p
+	}
+}
+
+// writePattern writes the shapes of the background pattern, clipped to
+// the background outline. seed varies the confetti between env versions.
+func (cfg *config) writePattern(b svgWriter, seed string) {
+	p := cfg.bgPattern
+	accent := safeColor(cfg.filterColor(p.accent))
+	outline := flatten(parsePathData(cfg.shapePath()), 0.25)[0].pts
+	polygon := func(poly []point, style string) {
+		poly = clipConvex(poly, outline)
+		if len(poly) < 3 {
+			return
+		}
+		b.WriteString(`<path d="M`)
+		for i, pt := range poly {
+			if i > 0 {
+				b.WriteString("L")
+			}
+			b.WriteString(formatFloat(pt.x) + "," + formatFloat(pt.y))
+		}
+		b.WriteString(`Z" style="` + style + `"/>`)
+	}
+	fill := "fill:" + accent + ";"
+
+	switch p.kind {
+	case PatternDots:
+		const step, r = 24.0, 4.5
+		for row := 0; float64(row)*step*0.866 < canvasSize+step; row++ {
+			y := float64(row) * step * 0.866
+			for x := float64(row%2) * step / 2; x < canvasSize+step; x += step {
+				polygon(circlePolygon(point{x, y}, r, 0.1), fill)
+			}
+		}
+	case PatternStripes:
+		const period, width = 24.0, 10.0
+		// stripes run at 45°: each is the band c <= x+y <= c+width
+		for c := -period; c < 2*canvasSize; c += period {
+			polygon([]point{{c, 0}, {c + width, 0}, {c + width - canvasSize, canvasSize}, {c - canvasSize, canvasSize}}, fill)
+		}
+	case PatternConfetti:
+		h := fnv.New64a()
+		h.Write([]byte(seed))
+		rng := rand.New(rand.NewPCG(h.Sum64(), 0))
+		for range 60 {
+			c := point{rng.Float64() * canvasSize, rng.Float64() * canvasSize}
+			if rng.IntN(3) == 0 {
+				polygon(circlePolygon(c, 4.5, 0.1), fill)
+				continue
+			}
+			a, s := rng.Float64()*math.Pi, 6+rng.Float64()*4
+			ca, sa := math.Cos(a)*s, math.Sin(a)*s
+			polygon([]point{{c.x - ca + sa/2, c.y - sa - ca/2}, {c.x + ca + sa/2, c.y + sa - ca/2},
+				{c.x + ca - sa/2, c.y + sa + ca/2}, {c.x - ca - sa/2, c.y - sa + ca/2}}, fill)
+		}
+	case PatternMesh:
+		mid := accent
+		if a, ok := parseColor(accent); ok {
+			if base, ok := parseColor(cfg.filterColor(p.base)); ok {
+				mid = formatColor(multiavatarcolor.Mix(a, base, 0.5))
+			}
+		}
+		blobs := []struct {
+			c   point
+			r   float64
+			col string
+		}{{point{40, 45}, 150, accent}, {point{200, 190}, 130, mid}, {point{190, 30}, 90, accent}}
+		b.WriteString(`<defs>`)
+		ids := make([]string, len(blobs))
+		for i, bl := range blobs {
+			ids[i] = cfg.defID(fmt.Sprintf("mesh-%d", i), p.base+" "+accent)
+			fmt.Fprintf(b, `<radialGradient id="%s" gradientUnits="userSpaceOnUse" cx="%s" cy="%s" r="%s"><stop offset="0" stop-color="%s"/><stop offset="1" stop-color="%s" stop-opacity="0"/></radialGradient>`,
+				ids[i], formatFloat(bl.c.x), formatFloat(bl.c.y), formatFloat(bl.r), bl.col, bl.col)
+		}
+		b.WriteString(`</defs>`)
+		for i, bl := range blobs {
+			polygon(circlePolygon(bl.c, bl.r, 0.25), "fill:url(#"+ids[i]+");")
+		}
+	}
+}
+
+// clipConvex clips subject to the convex polygon clip (Sutherland–Hodgman).
+func clipConvex(subject, clip []point) []point {
+	sign := 1.0
+	if signedArea(clip) < 0 {
+		sign = -1
+	}
+	out := subject
+	for i := range clip {
+		a, b := clip[i], clip[(i+1)%len(clip)]
+		inside := func(p point) bool { return sign*((b.x-a.x)*(p.y-a.y)-(b.y-a.y)*(p.x-a.x)) >= 0 }
+		cross := func(p, q point) point {
+			d1 := (b.x-a.x)*(p.y-a.y) - (b.y-a.y)*(p.x-a.x)
+			d2 := (b.x-a.x)*(q.y-a.y) - (b.y-a.y)*(q.x-a.x)
+			t := d1 / (d1 - d2)
+			return point{p.x + (q.x-p.x)*t, p.y + (q.y-p.y)*t}
+		}
+		in := out
+		out = nil
+		for j, cur := range in {
+			prev := in[(j+len(in)-1)%len(in)]
+			switch {
+			case inside(cur):
+				if !inside(prev) {
+					out = append(out, cross(prev, cur))
+				}
+				out = append(out, cur)
+			case inside(prev):
+				out = append(out, cross(prev, cur))
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+	}
+	return out
+}
@@ -0,0 +1,291 @@
+package multiavatar
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxPDFSize is the largest PDF page side in points, PDF's limit on page dimensions.
+const maxPDFSize = 14400
+
+// GeneratePDF renders the avatar as a single-page PDF of sizePt×sizePt
+// points (1/72 inch), e.g. for certificates and other print workflows.
+// The avatar is embedded as vector paths, so it stays sharp at any print
+// resolution. Gradients become PDF shadings; their transparency, if any,
+// is approximated by one opacity for the whole gradient.
+func GeneratePDF(input string, sizePt float64, opts ...Option) ([]byte, error) {
+	if input == "" {
+		return nil, ErrEmptyInput
+	}
+	if !(sizePt > 0 && sizePt <= maxPDFSize) {
+		return nil, fmt.Errorf("multiavatar: PDF size %vpt out of range (0, %d]", sizePt, maxPDFSize)
+	}
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	cfg.writeSVG(&b, cfg.selectParts(input))
+	root, err := parseSVGTree(strings.NewReader(b.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	// map the viewBox onto the page, centered, with the y axis flipped
+	vb := root.viewBox()
+	s := math.Min(sizePt/vb[2], sizePt/vb[3])
+	ox, oy := (sizePt-vb[2]*s)/2, (sizePt-vb[3]*s)/2
+	d := &pdfDrawer{alphas: make(map[string]string)}
+	d.op(affine{s, 0, 0, -s, ox - vb[0]*s, sizePt - oy + vb[1]*s}, "cm")
+	if err := drawSVGContext(context.Background(), root, d); err != nil {
+		return nil, err
+	}
+	return d.document(sizePt), nil
+}
+
+// pdfDrawer writes the shapes of an SVG document as a PDF content stream.
+type pdfDrawer struct {
+	content bytes.Buffer
+	// alphas names the ExtGState of each opacity, e.g. "0.5" -> "GS0"
+	alphas   map[string]string
+	gstates  []string
+	shadings []string
+}
+
+// op writes an operator with its operands; affine operands are written as
+// their six numbers.
+func (d *pdfDrawer) op(args ...any) {
+	for i, a := range args {
+		if i > 0 {
+			d.content.WriteByte(' ')
+		}
+		switch a := a.(type) {
+		case float64:
+			d.content.WriteString(pdfNum(a))
+		case affine:
+			for j, v := range a {
+				if j > 0 {
+					d.content.WriteByte(' ')
+				}
+				d.content.WriteString(pdfNum(v))
+			}
+		case string:
+			d.content.WriteString(a)
+		}
+	}
+	d.content.WriteByte('\n')
+}
+
+func (d *pdfDrawer) fillPath(p path, m affine, pt *paint, evenOdd bool, opacity float64) {
+	d.op("q")
+	d.op(m, "cm")
+	if pt.grad != nil {
+		d.alpha(gradientAlpha(pt.grad) * opacity)
+	} else {
+		d.alpha(float64(pt.solid.A) / 255 * opacity)
+		d.op(rgb(pt.solid), "rg")
+	}
+	d.path(p)
+	switch {
+	case pt.grad != nil && evenOdd:
+		d.op("W* n")
+	case pt.grad != nil:
+		d.op("W n")
+	case evenOdd:
+		d.op("f*")
+	default:
+		d.op("f")
+	}
+	if pt.grad != nil {
+		d.shade(pt.grad, p.bounds())
+	}
+	d.op("Q")
+}
+
+func (d *pdfDrawer) strokePath(p path, m affine, pt *paint, st strokeStyle, opacity float64) {
+	c := pt.solid
+	if pt.grad != nil {
+		// strokes are thin; their gradient is drawn as its middle color
+		c = pt.grad.at(0.5)
+	}
+	d.op("q")
+	d.op(m, "cm")
+	d.alpha(float64(c.A) / 255 * opacity)
+	d.op(rgb(c), "RG")
+	d.op(st.width, "w")
+	d.op(pdfLineStyle(st.cap, "butt", "round", "square"), "J")
+	d.op(pdfLineStyle(st.join, "miter", "round", "bevel"), "j")
+	d.op(st.miterLimit, "M")
+	d.path(p)
+	d.op("S")
+	d.op("Q")
+}
+
+// pdfLineStyle returns the PDF code of an SVG line cap or join: the index
+// of v in names, or 0 for unknown values.
+func pdfLineStyle(v string, names ...string) string {
+	for i, n := range names {
+		if v == n {
+			return strconv.Itoa(i)
+		}
+	}
+	return "0"
+}
+
+// path writes the construction operators of p.
+func (d *pdfDrawer) path(p path) {
+	for _, o := range p {
+		switch o.kind {
+		case 'M':
+			d.op(o.pts[0].x, o.pts[0].y, "m")
+		case 'L':
+			d.op(o.pts[0].x, o.pts[0].y, "l")
+		case 'C':
+			d.op(o.pts[0].x, o.pts[0].y, o.pts[1].x, o.pts[1].y, o.pts[2].x, o.pts[2].y, "c")
+		case 'Z':
+			d.op("h")
+		}
+	}
+}
+
+// alpha sets the fill and stroke opacity a, when it is not opaque.
+func (d *pdfDrawer) alpha(a float64) {
+	if a >= 1 {
+		return
+	}
+	key := pdfNum(math.Round(a*1000) / 1000)
+	name, ok := d.alphas[key]
+	if !ok {
+		name = "GS" + strconv.Itoa(len(d.gstates))
+		d.alphas[key] = name
+		d.gstates = append(d.gstates, "/"+name+" << /ca "+key+" /CA "+key+" >>")
+	}
+	d.op("/"+name, "gs")
+}
+
+// shade paints g over the current clip; bbox is the user-space bounding
+// box of the painted path, for objectBoundingBox gradients.
+func (d *pdfDrawer) shade(g *gradientPaint, bbox [4]float64) {
+	if !g.userSpace {
+		w, h := bbox[2]-bbox[0], bbox[3]-bbox[1]
+		if w == 0 || h == 0 {
+			return
+		}
+		d.op(affine{w, 0, 0, h, bbox[0], bbox[1]}, "cm")
+	}
+	d.op(g.inverse.invert(), "cm")
+
+	var sh strings.Builder
+	if g.radial {
+		fmt.Fprintf(&sh, "<< /ShadingType 3 /ColorSpace /DeviceRGB /Coords [%s %s 0 %s %s %s]",
+			pdfNum(g.cx), pdfNum(g.cy), pdfNum(g.cx), pdfNum(g.cy), pdfNum(g.r))
+	} else {
+		fmt.Fprintf(&sh, "<< /ShadingType 2 /ColorSpace /DeviceRGB /Coords [%s %s %s %s]",
+			pdfNum(g.x1), pdfNum(g.y1), pdfNum(g.x2), pdfNum(g.y2))
+	}
+	sh.WriteString(" /Extend [true true] /Function " + stopsFunction(g.stops) + " >>")
+	name := "Sh" + strconv.Itoa(len(d.shadings))
+	d.shadings = append(d.shadings, "/"+name+" "+sh.String())
+	d.op("/"+name, "sh")
+}
+
+// stopsFunction returns a PDF function interpolating the gradient stops
+// over [0, 1].
+func stopsFunction(stops []gradientStop) string {
+	if stops[0].offset > 0 {
+		stops = append([]gradientStop{{0, stops[0].color}}, stops...)
+	}
+	if last := stops[len(stops)-1]; last.offset < 1 {
+		stops = append(stops, gradientStop{1, last.color})
+	}
+	interp := func(a, b color.NRGBA) string {
+		return "<< /FunctionType 2 /Domain [0 1] /C0 [" + rgb(a) + "] /C1 [" + rgb(b) + "] /N 1 >>"
+	}
+	if len(stops) == 1 {
+		return interp(stops[0].color, stops[0].color)
+	}
+	if len(stops) == 2 {
+		return interp(stops[0].color, stops[1].color)
+	}
+	var funcs, bounds, encode []string
+	for i := 1; i < len(stops); i++ {
+		funcs = append(funcs, interp(stops[i-1].color, stops[i].color))
+		encode = append(encode, "0 1")
+		if i < len(stops)-1 {
+			bounds = append(bounds, pdfNum(stops[i].offset))
+		}
+	}
+	return "<< /FunctionType 3 /Domain [0 1] /Functions [" + strings.Join(funcs, " ") +
+		"] /Bounds [" + strings.Join(bounds, " ") + "] /Encode [" + strings.Join(encode, " ") + "] >>"
+}
+
+// gradientAlpha is the opacity a gradient is drawn with: that of its most
+// opaque stop.
+func gradientAlpha(g *gradientPaint) float64 {
+	a := uint8(0)
+	for _, s := range g.stops {
+		a = max(a, s.color.A)
+	}
+	return float64(a) / 255
+}
+
+// rgb formats the color channels of c as PDF operands.
+func rgb(c color.NRGBA) string {
+	return pdfNum(float64(c.R)/255) + " " + pdfNum(float64(c.G)/255) + " " + pdfNum(float64(c.B)/255)
+}
+
+// pdfNum formats v with at most four decimals.
+func pdfNum(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 4, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// document assembles the PDF file around the content stream.
+func (d *pdfDrawer) document(size float64) []byte {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(d.content.Bytes())
+	zw.Close()
+
+	resources := "<< "
+	if len(d.gstates) > 0 {
+		resources += "/ExtGState << " + strings.Join(d.gstates, " ") + " >> "
+	}
+	if len(d.shadings) > 0 {
+		resources += "/Shading << " + strings.Join(d.shadings, " ") + " >> "
+	}
+	resources += ">>"
+	dim := pdfNum(size)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 " + dim + " " + dim + "] /Resources " + resources + " /Contents 4 0 R >>",
+		"<< /Length " + strconv.Itoa(z.Len()) + " /Filter /FlateDecode >>\nstream\n" + z.String() + "\nendstream",
+		"<< /Producer (multiavatar-go) >>",
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+	return out.Bytes()
+}
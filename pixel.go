@@ -0,0 +1,88 @@
+package multiavatar
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// StylePixel draws the avatar as 16×16 blocky pixel art made of rects, for
+// retro-themed products. It is the default art downsampled, so the same
+// input and options choose the same parts and colors.
+const StylePixel = "pixel"
+
+func init() {
+	registerComposer(StylePixel, true, writePixel)
+}
+
+const (
+	pixelGrid = 16
+	// pixelSamples is the number of samples per pixel along each axis
+	pixelSamples = 4
+)
+
+// writePixel renders the default art at a small size and draws every
+// pixel in the most common color among its samples.
+func writePixel(cfg *config, b svgWriter, selected []selectedPart) {
+	art := *cfg
+	art.pack = nil
+	var doc strings.Builder
+	doc.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 231 231">`)
+	art.writeLayers(&doc, selected)
+	doc.WriteString(`</svg>`)
+	img, err := rasterizeSVG(doc.String(), pixelGrid*pixelSamples, pixelGrid*pixelSamples)
+	if err != nil {
+		return
+	}
+
+	cell := float64(canvasSize) / pixelGrid
+	b.WriteString(`<g shape-rendering="crispEdges">`)
+	var row [pixelGrid]string
+	for y := 0; y < pixelGrid; y++ {
+		for x := range row {
+			row[x] = pixelColor(img, x, y)
+		}
+		// one rect per run of equal colors
+		for x := 0; x < pixelGrid; {
+			end := x + 1
+			for end < pixelGrid && row[end] == row[x] {
+				end++
+			}
+			if row[x] != "" {
+				b.WriteString(`<rect x="` + formatFloat(float64(x)*cell) + `" y="` + formatFloat(float64(y)*cell) +
+					`" width="` + formatFloat(float64(end-x)*cell) + `" height="` + formatFloat(cell) +
+					`" fill="` + row[x] + `"/>`)
+			}
+			x = end
+		}
+	}
+	b.WriteString(`</g>`)
+}
+
+// pixelColor returns the most common opaque color among the samples of
+// pixel x, y, or "" if most of them are transparent.
+func pixelColor(img *image.RGBA, x, y int) string {
+	counts := make(map[color.NRGBA]int, pixelSamples*pixelSamples)
+	opaque := 0
+	var best color.NRGBA
+	for sy := 0; sy < pixelSamples; sy++ {
+		for sx := 0; sx < pixelSamples; sx++ {
+			i := img.PixOffset(x*pixelSamples+sx, y*pixelSamples+sy)
+			p := img.Pix[i : i+4 : i+4]
+			if p[3] < 128 {
+				continue
+			}
+			opaque++
+			r, g, bl := unpremultiply(p)
+			c := color.NRGBA{r, g, bl, 255}
+			counts[c]++
+			if counts[c] > counts[best] {
+				best = c
+			}
+		}
+	}
+	if opaque*2 < pixelSamples*pixelSamples {
+		return ""
+	}
+	return formatColor(best)
+}
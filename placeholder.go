@@ -0,0 +1,53 @@
+package multiavatar
+
+import (
+	"strings"
+)
+
+// placeholderShapes are the blocks of a placeholder on its 8×8 canvas,
+// bottom to top, and the part whose main color fills each. The paths are
+// left open to save bytes; filling closes them.
+var placeholderShapes = []struct{ part, d string }{
+	{"env", "M0 0h8v8H0"},
+	{"clo", "M1 6h6v2H1"},
+	{"head", "M2 3h4v3H2"},
+	{"top", "M2 1h4v2H2"},
+}
+
+// GeneratePlaceholder returns a tiny, blurred preview of the avatar for
+// input, under 300 bytes, to show as a low-quality image placeholder
+// while the full avatar loads. Its background, clothes, face and hair
+// blocks take the main colors of the parts Generate selects with the same
+// options. Like Generate, it returns "" for an empty input.
+func GeneratePlaceholder(input string, opts ...Option) string {
+	cfg := newConfig(opts)
+	if input == "" {
+		return ""
+	}
+	byName := make(map[string]selectedPart)
+	for _, p := range cfg.selectParts(input) {
+		byName[p.name] = p
+	}
+
+	// every placeholder defines the same filter, so inlined placeholders
+	// may share its short, fixed id
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 8 8"><filter id="lq"><feGaussianBlur stdDeviation="1"/></filter>`)
+	for i, s := range placeholderShapes {
+		if i == 1 {
+			b.WriteString(`<g filter="url(#lq)">`)
+		}
+		p, ok := byName[s.part]
+		if !ok || cfg.disabledParts[s.part] || s.part == "env" && (cfg.withoutBackground || cfg.bgShape == ShapeNone) {
+			continue
+		}
+		c, ok := mainColor(p.colors)
+		if !ok {
+			continue
+		}
+		c.A = 255
+		b.WriteString(`<path fill="` + formatColor(c) + `" d="` + s.d + `"/>`)
+	}
+	b.WriteString(`</g></svg>`)
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package multiavatar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Policy is a tenant-wide restriction of the avatars generated, kept in a
+// YAML or JSON file so platform admins can change it without code changes:
+//
+//	# acme.yaml
+//	allowedVersions:
+//	  top: [00, 03, 07, afro]
+//	  eyes: [01, 02]
+//	allowedThemes:
+//	  head: [A, B]
+//	palette: ["#0b1f3a", "#1f6feb", "#f6f8fa", "#ffb000"]
+//	withoutParts:
+//	  - env
+//
+// Map keys are part names as in Options; versions are codes ("07") or
+// registered names ("afro").
+type Policy struct {
+	// AllowedVersions restricts parts to the listed versions, as WithAllowedVersions.
+	AllowedVersions map[string][]string `json:"allowedVersions,omitempty"`
+	// AllowedThemes restricts parts to the listed themes, as WithAllowedThemes.
+	AllowedThemes map[string][]string `json:"allowedThemes,omitempty"`
+	// Palette snaps every color to the nearest of these, as WithPalette.
+	Palette []string `json:"palette,omitempty"`
+	// WithoutParts are never drawn, as WithoutPart.
+	WithoutParts []string `json:"withoutParts,omitempty"`
+}
+
+// LoadPolicy reads a Policy document and returns the equivalent option
+// list, to be applied before any per-user options. JSON documents start
+// with '{'; anything else is read as YAML, in the block and flow styles of
+// the Policy example: mappings, sequences, plain or quoted strings and
+// comments. Unknown fields, parts, versions, themes and invalid colors are
+// rejected.
+func LoadPolicy(r io.Reader) ([]Option, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("multiavatar: read policy: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		doc, err := parseYAML(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("multiavatar: decode policy: %w", err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("multiavatar: decode policy: %w", err)
+		}
+	}
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("multiavatar: decode policy: %w", err)
+	}
+	opts, err := p.ToOptions()
+	if err != nil {
+		return nil, err
+	}
+	// report invalid colors now rather than at generation time
+	if err := newConfig(opts).err(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// ToOptions validates p and converts it into functional options.
+func (p Policy) ToOptions() ([]Option, error) {
+	o := Options{AllowedVersions: p.AllowedVersions, AllowedThemes: p.AllowedThemes, WithoutParts: p.WithoutParts}
+	if err := o.validateParts(); err != nil {
+		return nil, err
+	}
+	var opts []Option
+	for _, part := range sortedKeys(p.AllowedVersions) {
+		versions := make([]string, 0, len(p.AllowedVersions[part]))
+		for _, v := range p.AllowedVersions[part] {
+			v = strings.TrimSpace(v)
+			if !validPartVersion(part, v) {
+				code, ok := PartVersionByName(part, v)
+				if !ok {
+					return nil, ErrInvalidVersion{Part: part, Version: v}
+				}
+				v = code
+			}
+			versions = append(versions, v)
+		}
+		opts = append(opts, WithAllowedVersions(part, versions))
+	}
+	for _, part := range sortedKeys(p.AllowedThemes) {
+		for _, t := range p.AllowedThemes[part] {
+			if t := strings.ToUpper(strings.TrimSpace(t)); t != "A" && t != "B" && t != "C" {
+				return nil, fmt.Errorf("multiavatar: unknown %s theme %q", part, t)
+			}
+		}
+		opts = append(opts, WithAllowedThemes(part, p.AllowedThemes[part]))
+	}
+	if len(p.Palette) > 0 {
+		opts = append(opts, WithPalette(p.Palette))
+	}
+	for _, part := range p.WithoutParts {
+		opts = append(opts, WithoutPart(part))
+	}
+	return opts, nil
+}
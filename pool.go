@@ -0,0 +1,90 @@
+package multiavatar
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBuffer bounds the capacity of buffers returned to bufferPool, so
+// an occasional huge sheet does not stay pinned in memory.
+const maxPooledBuffer = 64 << 10
+
+// bufferPool holds the scratch buffers documents and parts are assembled
+// in, so busy servers do not regrow a builder on every call.
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// getBuffer returns an empty buffer with room for at least n bytes.
+func getBuffer(n int) *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Grow(n)
+	return buf
+}
+
+// putBuffer resets buf and returns it to the pool.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// writerPool holds the bufio.Writers GenerateTo streams through.
+var writerPool = sync.Pool{New: func() any { return bufio.NewWriterSize(nil, 2048) }}
+
+// getWriter returns a pooled bufio.Writer writing to w.
+func getWriter(w io.Writer) *bufio.Writer {
+	bw := writerPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	return bw
+}
+
+// putWriter detaches bw from its writer and returns it to the pool.
+func putWriter(bw *bufio.Writer) {
+	bw.Reset(nil)
+	writerPool.Put(bw)
+}
+
+// appendWriter appends to a caller's slice, for AppendSVG.
+type appendWriter struct{ b []byte }
+
+func (w *appendWriter) Write(p []byte) (int, error) {
+	w.b = append(w.b, p...)
+	return len(p), nil
+}
+
+func (w *appendWriter) WriteString(s string) (int, error) {
+	w.b = append(w.b, s...)
+	return len(s), nil
+}
+
+// appendPool holds the appendWriters of AppendSVG, so wrapping the
+// caller's slice does not allocate.
+var appendPool = sync.Pool{New: func() any { return new(appendWriter) }}
+
+// documentOverhead approximates the markup around the part templates: the
+// root element, wrappers, and colors longer than their placeholders.
+const documentOverhead = 512
+
+// estimateSize approximates the length of the document for selected, so
+// its buffer is allocated once.
+func (cfg *config) estimateSize(selected []selectedPart) int {
+	n := documentOverhead
+	for _, p := range selected {
+		if tmpl, ok := cfg.template(p); ok {
+			n += len(tmpl.svg)
+		}
+	}
+	return n
+}
+
+// renderSVG assembles the document for input in a pooled buffer.
+func (cfg *config) renderSVG(input string) string {
+	selected := cfg.selectParts(input)
+	buf := getBuffer(cfg.estimateSize(selected))
+	defer putBuffer(buf)
+	cfg.writeSVG(buf, selected)
+	return buf.String()
+}
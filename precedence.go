@@ -0,0 +1,96 @@
+package multiavatar
+
+import (
+	"slices"
+)
+
+// EffectiveConfig is the configuration a list of options resolves to once
+// conflicting options are settled. Options conflict in a fixed order,
+// independent of the order they are given in:
+//
+//   - Theme: WithPartTheme, then WithAllowedThemes, then WithTheme, then
+//     WithDeterministicJitter, then the hash. An allowed list is indexed
+//     by the hash, so it overrides WithTheme for its part.
+//   - Version: WithPartVersion (and WithNamedPart), then
+//     WithAllowedVersions, then the hash.
+//   - Colors: the colors set for the part, e.g. with WithEnvColor or
+//     WithPartColors, then WithMinContrast for the background, then
+//     WithHarmoniousColors, then the theme colors of the version.
+//     WithPalette and the other color filters apply to every color last.
+//   - Background: WithoutBackground and WithBackgroundShape(ShapeNone)
+//     remove it whatever its color; otherwise WithBackgroundPattern, then
+//     WithBackgroundGradient, then the background colors above.
+//   - WithoutPart leaves the part out whatever else is set for it.
+//
+// Repeating an option of the same kind for the same part replaces the
+// earlier one, except for color filters, which apply in turn.
+type EffectiveConfig struct {
+	Algorithm int `json:"algorithm"`
+	// Background reports whether the background is drawn.
+	Background bool `json:"background"`
+	// Parts lists the six original parts and the enabled optional ones.
+	Parts map[string]EffectivePart `json:"parts"`
+}
+
+// EffectivePart is the effective configuration of one part. Empty fields
+// are left to the hash.
+type EffectivePart struct {
+	// Enabled is false for parts left out with WithoutPart, and for env
+	// when the background is removed.
+	Enabled bool `json:"enabled"`
+	// Version is the forced version, or "" if the hash chooses one.
+	Version string `json:"version,omitempty"`
+	// AllowedVersions are the versions the hash chooses from when Version
+	// is unset; nil allows every version.
+	AllowedVersions []string `json:"allowedVersions,omitempty"`
+	// Theme is the forced theme, or "" if the hash chooses one.
+	Theme string `json:"theme,omitempty"`
+	// AllowedThemes are the themes the hash chooses from when Theme is
+	// unset; nil allows every theme.
+	AllowedThemes []string `json:"allowedThemes,omitempty"`
+	// Colors are the colors set for the part, or nil for the theme colors.
+	Colors []string `json:"colors,omitempty"`
+}
+
+// Effective resolves opts to the configuration they produce, following
+// the precedence described on EffectiveConfig, e.g. to check why an option
+// has no effect. Invalid options are ignored as by Generate and reported
+// in the error; the configuration is returned either way.
+func Effective(opts ...Option) (EffectiveConfig, error) {
+	cfg := newConfig(opts)
+	algo := cfg.algorithm
+	if algo == 0 {
+		algo = AlgorithmV1
+	}
+	ec := EffectiveConfig{
+		Algorithm:  int(algo),
+		Background: !cfg.withoutBackground && cfg.bgShape != ShapeNone && !cfg.disabledParts["env"],
+		Parts:      make(map[string]EffectivePart, len(partNames)+len(cfg.extraParts)),
+	}
+	names := slices.Clone(partNames)
+	for _, x := range extraParts {
+		if cfg.extraParts[x.name] {
+			names = append(names, x.name)
+		}
+	}
+	for _, name := range names {
+		p := EffectivePart{Enabled: !cfg.disabledParts[name] && (name != "env" || ec.Background)}
+		if v, ok := cfg.forcePartV[name]; ok && len(v) == 2 {
+			p.Version = v
+		} else if allowed := cfg.allowedVersions[name]; len(allowed) > 0 {
+			p.AllowedVersions = slices.Clone(allowed)
+		}
+		if t, ok := cfg.partTheme[name]; ok {
+			p.Theme = t
+		} else if allowed := cfg.allowedThemes[name]; len(allowed) > 0 {
+			p.AllowedThemes = slices.Clone(allowed)
+		} else if cfg.selectedTheme != nil {
+			p.Theme = *cfg.selectedTheme
+		}
+		if colors := cfg.overrideColors[name]; len(colors) > 0 {
+			p.Colors = slices.Clone(colors)
+		}
+		ec.Parts[name] = p
+	}
+	return ec, cfg.err()
+}
@@ -0,0 +1,254 @@
+// Package prerender renders avatars ahead of time and uploads them to
+// object storage, e.g. to populate a CDN bucket nightly:
+//
+//	p := prerender.Pipeline{Store: bucket, Prefix: "avatars/"}
+//	err := p.Render(ctx, usernames,
+//		[]multiavatar.Format{multiavatar.FormatSVG, multiavatar.FormatWebP},
+//		[]int{64, 128, 256},
+//	)
+//
+// Object keys depend only on the input, format and size, so a rerun
+// overwrites the same objects and clients can compute an avatar's URL
+// without a lookup.
+package prerender
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+const (
+	// defaultRetries is the number of upload retries when Pipeline.Retries is 0.
+	defaultRetries = 3
+	// defaultRetryDelay is the first retry delay when Pipeline.RetryDelay is 0.
+	defaultRetryDelay = 200 * time.Millisecond
+)
+
+// ObjectStore is where rendered avatars are uploaded, typically a thin
+// adapter over an S3, GCS or Azure Blob client. Put must be safe for
+// concurrent use.
+type ObjectStore interface {
+	// Put stores body under key, replacing any existing object. It must
+	// not keep body after returning.
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+}
+
+// StoreFunc adapts a function to ObjectStore.
+type StoreFunc func(ctx context.Context, key string, body []byte, contentType string) error
+
+// Put calls f.
+func (f StoreFunc) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	return f(ctx, key, body, contentType)
+}
+
+// Key returns the default object key of the avatar for input: the hex
+// SHA-256 of input, then the size for raster formats and the extension,
+// e.g. "2bd806c9…-256.png" or "2bd806c9….svg". Hashing keeps keys path
+// safe and does not expose e-mail addresses in URLs.
+func Key(input string, format multiavatar.Format, size int) string {
+	sum := sha256.Sum256([]byte(input))
+	if format == multiavatar.FormatSVG {
+		return hex.EncodeToString(sum[:]) + format.Extension()
+	}
+	return fmt.Sprintf("%s-%d%s", hex.EncodeToString(sum[:]), size, format.Extension())
+}
+
+// Pipeline renders avatars and uploads them to Store.
+type Pipeline struct {
+	// Store receives the rendered avatars.
+	Store ObjectStore
+	// Options are applied to every avatar. They are not part of the key,
+	// so give each set of options its own Prefix.
+	Options []multiavatar.Option
+	// Prefix is prepended to every key, e.g. "avatars/v1/".
+	Prefix string
+	// Key derives the object key from an avatar; nil uses Key. SVG
+	// avatars are passed a size of 0.
+	Key func(input string, format multiavatar.Format, size int) string
+	// Concurrency is the number of avatars rendered and uploaded at once;
+	// 0 means runtime.GOMAXPROCS(0).
+	Concurrency int
+	// Retries is the number of times a failed upload is retried, waiting
+	// RetryDelay before the first retry and twice as long before each
+	// further one. 0 means 3; a negative value disables retries.
+	Retries int
+	// RetryDelay is the wait before the first retry; 0 means 200ms.
+	RetryDelay time.Duration
+	// Logger, if set, receives every upload at Debug level, retries at
+	// Warn, failed objects at Error and the run's totals at Info. Add
+	// multiavatar.WithLogger to Options to log rendering as well.
+	Logger *slog.Logger
+}
+
+// job is one object to render and upload.
+type job struct {
+	input  string
+	format multiavatar.Format
+	size   int
+}
+
+// Render uploads the avatar of every input in every format: SVG once, and
+// raster formats once per size. Empty and repeated inputs are skipped.
+//
+// Failed uploads are retried; objects that still fail do not stop the
+// others, and their errors are returned joined once every object has been
+// tried. A rendering error, e.g. an invalid option or a size too large for
+// its format, affects every object alike, so it stops the pipeline and is
+// returned alone, as is ctx's error once ctx is done.
+func (p *Pipeline) Render(ctx context.Context, inputs []string, formats []multiavatar.Format, sizes []int) error {
+	if p.Store == nil {
+		return errors.New("prerender: no Store")
+	}
+	if len(formats) == 0 {
+		return errors.New("prerender: no formats")
+	}
+	sizes = slices.Compact(slices.Sorted(slices.Values(sizes)))
+	for _, size := range sizes {
+		if size <= 0 {
+			return fmt.Errorf("prerender: invalid size %d", size)
+		}
+	}
+	formats = slices.Compact(slices.Sorted(slices.Values(formats)))
+	for _, f := range formats {
+		if f != multiavatar.FormatSVG && len(sizes) == 0 {
+			return fmt.Errorf("prerender: %v needs at least one size", f)
+		}
+	}
+
+	var jobs []job
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		if input == "" || seen[input] {
+			continue
+		}
+		seen[input] = true
+		for _, f := range formats {
+			if f == multiavatar.FormatSVG {
+				jobs = append(jobs, job{input, f, 0})
+				continue
+			}
+			for _, size := range sizes {
+				jobs = append(jobs, job{input, f, size})
+			}
+		}
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	n := p.Concurrency
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	work := make(chan job)
+	var (
+		mu     sync.Mutex
+		failed []error
+		wg     sync.WaitGroup
+	)
+	for range min(n, len(jobs)) {
+		wg.Go(func() {
+			var buf bytes.Buffer
+			for j := range work {
+				if ctx.Err() != nil {
+					continue
+				}
+				buf.Reset()
+				opts := p.Options
+				if j.format != multiavatar.FormatSVG {
+					opts = append(slices.Clip(opts), multiavatar.WithSize(j.size))
+				}
+				if err := multiavatar.EncodeContext(ctx, &buf, j.input, j.format, opts...); err != nil {
+					cancel(err)
+					continue
+				}
+				key := p.key(j)
+				if err := p.put(ctx, key, buf.Bytes(), j.format.ContentType()); err != nil && ctx.Err() == nil {
+					p.log(ctx, slog.LevelError, "prerender: upload failed", slog.String("key", key), slog.Any("error", err))
+					mu.Lock()
+					failed = append(failed, fmt.Errorf("prerender: put %s: %w", key, err))
+					mu.Unlock()
+				}
+			}
+		})
+	}
+feed:
+	for _, j := range jobs {
+		select {
+		case work <- j:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	err := context.Cause(ctx)
+	if err == nil {
+		err = errors.Join(failed...)
+	}
+	attrs := []slog.Attr{slog.Int("objects", len(jobs)), slog.Int("failed", len(failed)), slog.Duration("duration", time.Since(start))}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	p.log(ctx, slog.LevelInfo, "prerender: done", attrs...)
+	return err
+}
+
+func (p *Pipeline) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if p.Logger != nil {
+		p.Logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
+func (p *Pipeline) key(j job) string {
+	if p.Key != nil {
+		return p.Prefix + p.Key(j.input, j.format, j.size)
+	}
+	return p.Prefix + Key(j.input, j.format, j.size)
+}
+
+// put uploads body, retrying with exponential backoff.
+func (p *Pipeline) put(ctx context.Context, key string, body []byte, contentType string) error {
+	retries := p.Retries
+	switch {
+	case retries == 0:
+		retries = defaultRetries
+	case retries < 0:
+		retries = 0
+	}
+	delay := p.RetryDelay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+	for attempt := 0; ; attempt++ {
+		t0 := time.Now()
+		err := p.Store.Put(ctx, key, body, contentType)
+		if err == nil {
+			p.log(ctx, slog.LevelDebug, "prerender: uploaded", slog.String("key", key),
+				slog.Int("bytes", len(body)), slog.Duration("duration", time.Since(t0)))
+		}
+		if err == nil || attempt == retries {
+			return err
+		}
+		p.log(ctx, slog.LevelWarn, "prerender: retrying upload", slog.String("key", key),
+			slog.Int("attempt", attempt+1), slog.Any("error", err))
+		t := time.NewTimer(delay << attempt)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
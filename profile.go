@@ -0,0 +1,52 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = make(map[string]*CompiledOptions)
+)
+
+// RegisterProfile makes opts available under name, to be selected with
+// WithProfile, so each tenant of a service can have a named, centrally
+// managed avatar style. The options are validated and compiled once, like
+// CompileOptions. Register profiles from an init function or before the
+// first Generate call that uses them. It panics if name is empty or
+// already registered, or opts are invalid.
+func RegisterProfile(name string, opts ...Option) {
+	if name == "" {
+		panic("multiavatar: RegisterProfile name is empty")
+	}
+	co, err := CompileOptions(opts...)
+	if err != nil {
+		panic("multiavatar: RegisterProfile " + name + ": " + err.Error())
+	}
+
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	if _, dup := profiles[name]; dup {
+		panic("multiavatar: RegisterProfile called twice for " + name)
+	}
+	profiles[name] = co
+}
+
+// WithProfile applies the options registered under name with
+// RegisterProfile, in their place among the other options: options after
+// it override the profile's settings. Unknown names are reported as errors
+// by the error-returning APIs.
+func WithProfile(name string) Option {
+	return func(c *config) {
+		profilesMu.RLock()
+		co, ok := profiles[strings.TrimSpace(name)]
+		profilesMu.RUnlock()
+		if !ok {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown profile %q", name))
+			return
+		}
+		co.Option()(c)
+	}
+}
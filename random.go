@@ -0,0 +1,29 @@
+package multiavatar
+
+import "math/rand/v2"
+
+// randomSeedAlphabet and randomSeedLen shape the seeds made by GenerateRandom:
+// 16 lowercase alphanumerics, about 82 bits, so collisions are not a concern.
+const (
+	randomSeedAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	randomSeedLen      = 16
+)
+
+// GenerateRandom picks a random avatar and returns it together with the seed
+// that regenerates it with Generate(seed, opts...). It suits pickers that
+// let a user shuffle until they like the result and then store the seed.
+//
+// Passing the same *rand.Rand state yields the same sequence of avatars;
+// a nil r uses the top-level math/rand/v2 generator.
+func GenerateRandom(r *rand.Rand, opts ...Option) (svg string, seed string) {
+	intN := rand.IntN
+	if r != nil {
+		intN = r.IntN
+	}
+	buf := make([]byte, randomSeedLen)
+	for i := range buf {
+		buf[i] = randomSeedAlphabet[intN(len(randomSeedAlphabet))]
+	}
+	seed = string(buf)
+	return Generate(seed, opts...), seed
+}
@@ -0,0 +1,436 @@
+package multiavatar
+
+import (
+	"image"
+	"math"
+)
+
+// rasterSubsamples is the number of sub-scanlines per pixel row used for
+// vertical anti-aliasing; horizontal coverage is computed exactly.
+const rasterSubsamples = 8
+
+// flattenTolerance is the maximum deviation, in device pixels, allowed when
+// approximating curves with line segments.
+const flattenTolerance = 0.1
+
+// rasterCanvas is a drawer painting onto an RGBA image.
+type rasterCanvas struct {
+	img *image.RGBA
+	// view maps document (viewBox) coordinates to device pixels.
+	view affine
+
+	cov   []float32
+	xs    []crossing
+	edges []edge
+
+	// done, if set, abandons a fill midway once closed
+	done <-chan struct{}
+	// crisp snaps shapes to whole pixels, so abutting shapes leave no
+	// anti-aliased seams
+	crisp bool
+}
+
+// newRasterCanvas returns a canvas of w×h pixels showing viewBox vb,
+// scaled uniformly and centered (preserveAspectRatio="xMidYMid meet").
+func newRasterCanvas(w, h int, vb [4]float64) *rasterCanvas {
+	s := math.Min(float64(w)/vb[2], float64(h)/vb[3])
+	tx := (float64(w)-vb[2]*s)/2 - vb[0]*s
+	ty := (float64(h)-vb[3]*s)/2 - vb[1]*s
+	return &rasterCanvas{
+		img:  image.NewRGBA(image.Rect(0, 0, w, h)),
+		view: affine{s, 0, 0, s, tx, ty},
+		cov:  make([]float32, w+1),
+	}
+}
+
+// polyline is a flattened subpath.
+type polyline struct {
+	pts    []point
+	closed bool
+}
+
+// flatten approximates p with polylines; tol is in the path's own units.
+func flatten(p path, tol float64) []polyline {
+	var out []polyline
+	var cur *polyline
+	var last, start point
+	for _, op := range p {
+		switch op.kind {
+		case 'M':
+			out = append(out, polyline{pts: []point{op.pts[0]}})
+			cur = &out[len(out)-1]
+			last, start = op.pts[0], op.pts[0]
+		case 'L':
+			if cur == nil {
+				out = append(out, polyline{pts: []point{last}})
+				cur = &out[len(out)-1]
+			}
+			cur.pts = append(cur.pts, op.pts[0])
+			last = op.pts[0]
+		case 'C':
+			if cur == nil {
+				out = append(out, polyline{pts: []point{last}})
+				cur = &out[len(out)-1]
+			}
+			p0, p1, p2, p3 := last, op.pts[0], op.pts[1], op.pts[2]
+			dd := math.Max(math.Hypot(p0.x-2*p1.x+p2.x, p0.y-2*p1.y+p2.y), math.Hypot(p1.x-2*p2.x+p3.x, p1.y-2*p2.y+p3.y))
+			n := int(math.Ceil(math.Sqrt(0.75 * dd / tol)))
+			n = max(1, min(n, 256))
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				mt := 1 - t
+				a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+				cur.pts = append(cur.pts, point{
+					a*p0.x + b*p1.x + c*p2.x + d*p3.x,
+					a*p0.y + b*p1.y + c*p2.y + d*p3.y,
+				})
+			}
+			last = p3
+		case 'Z':
+			if cur != nil {
+				cur.closed = true
+				cur = nil
+			}
+			last = start
+		}
+	}
+	return out
+}
+
+func (c *rasterCanvas) setCrisp(crisp bool) { c.crisp = crisp }
+
+func (c *rasterCanvas) fillPath(p path, m affine, pt *paint, evenOdd bool, opacity float64) {
+	dev := c.view.mul(m)
+	lines := flatten(p.transform(dev), flattenTolerance)
+	polys := make([][]point, 0, len(lines))
+	for _, l := range lines {
+		polys = append(polys, l.pts)
+	}
+	c.fill(polys, evenOdd, c.shader(p, m, pt), opacity)
+}
+
+func (c *rasterCanvas) strokePath(p path, m affine, pt *paint, st strokeStyle, opacity float64) {
+	dev := c.view.mul(m)
+	s := dev.scale()
+	if s == 0 {
+		return
+	}
+	polys := strokePolygons(flatten(p, flattenTolerance/s), st, flattenTolerance/s)
+	for i, poly := range polys {
+		for j := range poly {
+			poly[j] = dev.apply(poly[j])
+		}
+		polys[i] = poly
+	}
+	c.fill(polys, false, c.shader(p, m, pt), opacity)
+}
+
+// shader returns the color source for a paint applied to path p.
+func (c *rasterCanvas) shader(p path, m affine, pt *paint) func(x, y float64) (r, g, b, a float64) {
+	if pt.grad == nil {
+		r, g, b, a := float64(pt.solid.R)/255, float64(pt.solid.G)/255, float64(pt.solid.B)/255, float64(pt.solid.A)/255
+		return func(float64, float64) (float64, float64, float64, float64) { return r, g, b, a }
+	}
+	inv := c.view.mul(m).invert()
+	bbox := p.bounds()
+	grad := pt.grad
+	return func(x, y float64) (float64, float64, float64, float64) {
+		col := grad.at(grad.param(inv.apply(point{x, y}), bbox))
+		return float64(col.R) / 255, float64(col.G) / 255, float64(col.B) / 255, float64(col.A) / 255
+	}
+}
+
+// bounds returns the control-point bounding box (minX, minY, maxX, maxY).
+func (p path) bounds() [4]float64 {
+	b := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, op := range p {
+		n := 1
+		if op.kind == 'C' {
+			n = 3
+		} else if op.kind == 'Z' {
+			n = 0
+		}
+		for _, q := range op.pts[:n] {
+			b[0], b[1] = math.Min(b[0], q.x), math.Min(b[1], q.y)
+			b[2], b[3] = math.Max(b[2], q.x), math.Max(b[3], q.y)
+		}
+	}
+	return b
+}
+
+// edge is a non-horizontal polygon edge with y0 < y1.
+type edge struct {
+	x0, y0, y1, dxdy float64
+	dir              int
+}
+
+type crossing struct {
+	x   float64
+	dir int
+}
+
+// fill scan-converts device-space polygons and composites the shader over
+// the covered pixels with source-over blending.
+func (c *rasterCanvas) fill(polys [][]point, evenOdd bool, shade func(x, y float64) (r, g, b, a float64), opacity float64) {
+	bounds := c.img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	c.edges = c.edges[:0]
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, poly := range polys {
+		for i := range poly {
+			a, b := poly[i], poly[(i+1)%len(poly)]
+			if c.crisp {
+				a = point{math.Round(a.x), math.Round(a.y)}
+				b = point{math.Round(b.x), math.Round(b.y)}
+			}
+			if a.y == b.y || math.IsNaN(a.y) || math.IsNaN(b.y) {
+				continue
+			}
+			dir := 1
+			if a.y > b.y {
+				a, b = b, a
+				dir = -1
+			}
+			c.edges = append(c.edges, edge{x0: a.x, y0: a.y, y1: b.y, dxdy: (b.x - a.x) / (b.y - a.y), dir: dir})
+			minY, maxY = math.Min(minY, a.y), math.Max(maxY, b.y)
+		}
+	}
+	if len(c.edges) == 0 {
+		return
+	}
+	y0 := max(0, int(math.Floor(minY)))
+	y1 := min(h, int(math.Ceil(maxY)))
+
+	for py := y0; py < y1; py++ {
+		if py%64 == 0 {
+			select {
+			case <-c.done:
+				return
+			default:
+			}
+		}
+		minX, maxX := w, -1
+		for s := 0; s < rasterSubsamples; s++ {
+			sy := float64(py) + (float64(s)+0.5)/rasterSubsamples
+			c.xs = c.xs[:0]
+			for _, e := range c.edges {
+				if sy >= e.y0 && sy < e.y1 {
+					c.xs = append(c.xs, crossing{x: e.x0 + (sy-e.y0)*e.dxdy, dir: e.dir})
+				}
+			}
+			// insertion sort: crossing lists are short
+			for i := 1; i < len(c.xs); i++ {
+				for j := i; j > 0 && c.xs[j].x < c.xs[j-1].x; j-- {
+					c.xs[j], c.xs[j-1] = c.xs[j-1], c.xs[j]
+				}
+			}
+			wind := 0
+			for i := 0; i+1 < len(c.xs); i++ {
+				wind += c.xs[i].dir
+				inside := wind != 0
+				if evenOdd {
+					inside = wind%2 != 0
+				}
+				if !inside {
+					continue
+				}
+				lo, hi := c.addSpan(c.xs[i].x, c.xs[i+1].x, w)
+				minX, maxX = min(minX, lo), max(maxX, hi)
+			}
+		}
+		for x := minX; x <= maxX; x++ {
+			cv := float64(c.cov[x])
+			c.cov[x] = 0
+			if cv <= 0 {
+				continue
+			}
+			if cv > 1 {
+				cv = 1
+			}
+			r, g, b, a := shade(float64(x)+0.5, float64(py)+0.5)
+			alpha := a * cv * opacity
+			if alpha <= 0 {
+				continue
+			}
+			i := c.img.PixOffset(x, py)
+			pix := c.img.Pix[i : i+4 : i+4]
+			inv := 1 - alpha
+			pix[0] = uint8(r*alpha*255 + float64(pix[0])*inv + 0.5)
+			pix[1] = uint8(g*alpha*255 + float64(pix[1])*inv + 0.5)
+			pix[2] = uint8(b*alpha*255 + float64(pix[2])*inv + 0.5)
+			pix[3] = uint8(alpha*255 + float64(pix[3])*inv + 0.5)
+		}
+	}
+}
+
+// addSpan accumulates the coverage of one sub-scanline span [xa, xb) and
+// returns the range of touched columns.
+func (c *rasterCanvas) addSpan(xa, xb float64, w int) (int, int) {
+	xa, xb = clamp(xa, 0, float64(w)), clamp(xb, 0, float64(w))
+	if xb <= xa {
+		return w, -1
+	}
+	const unit = 1.0 / rasterSubsamples
+	ia, ib := int(xa), int(xb)
+	if ib >= w {
+		ib = w - 1
+	}
+	if ia == ib {
+		c.cov[ia] += float32((xb - xa) * unit)
+		return ia, ib
+	}
+	c.cov[ia] += float32((float64(ia+1) - xa) * unit)
+	for x := ia + 1; x < ib; x++ {
+		c.cov[x] += unit
+	}
+	c.cov[ib] += float32((xb - float64(ib)) * unit)
+	return ia, ib
+}
+
+// strokePolygons outlines polylines as a set of positively oriented polygons
+// whose nonzero union is the stroke area.
+func strokePolygons(lines []polyline, st strokeStyle, tol float64) [][]point {
+	hw := st.width / 2
+	if hw <= 0 {
+		return nil
+	}
+	var polys [][]point
+	add := func(poly []point) {
+		if signedArea(poly) < 0 {
+			for i, j := 0, len(poly)-1; i < j; i, j = i+1, j-1 {
+				poly[i], poly[j] = poly[j], poly[i]
+			}
+		}
+		polys = append(polys, poly)
+	}
+
+	for _, l := range lines {
+		pts := dedupe(l.pts)
+		if l.closed && len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+			pts = pts[:len(pts)-1]
+		}
+		if len(pts) == 1 {
+			// zero-length subpath: only round and square caps paint a dot
+			switch st.cap {
+			case "round":
+				add(circlePolygon(pts[0], hw, tol))
+			case "square":
+				q := pts[0]
+				add([]point{{q.x - hw, q.y - hw}, {q.x + hw, q.y - hw}, {q.x + hw, q.y + hw}, {q.x - hw, q.y + hw}})
+			}
+			continue
+		}
+		n := len(pts)
+		segs := n - 1
+		if l.closed {
+			segs = n
+		}
+		for i := 0; i < segs; i++ {
+			a, b := pts[i], pts[(i+1)%n]
+			d := unit(point{b.x - a.x, b.y - a.y})
+			nrm := point{-d.y * hw, d.x * hw}
+			if !l.closed && st.cap == "square" {
+				if i == 0 {
+					a = point{a.x - d.x*hw, a.y - d.y*hw}
+				}
+				if i == segs-1 {
+					b = point{b.x + d.x*hw, b.y + d.y*hw}
+				}
+			}
+			add([]point{{a.x + nrm.x, a.y + nrm.y}, {b.x + nrm.x, b.y + nrm.y}, {b.x - nrm.x, b.y - nrm.y}, {a.x - nrm.x, a.y - nrm.y}})
+		}
+
+		// joins
+		for i := 0; i < n; i++ {
+			if !l.closed && (i == 0 || i == n-1) {
+				continue
+			}
+			prev, v, next := pts[(i+n-1)%n], pts[i], pts[(i+1)%n]
+			if j := joinPolygon(prev, v, next, hw, st, tol); j != nil {
+				add(j)
+			}
+		}
+
+		// caps
+		if !l.closed && st.cap == "round" {
+			add(circlePolygon(pts[0], hw, tol))
+			add(circlePolygon(pts[n-1], hw, tol))
+		}
+	}
+	return polys
+}
+
+// joinPolygon returns the polygon filling the outer corner at v.
+func joinPolygon(prev, v, next point, hw float64, st strokeStyle, tol float64) []point {
+	d0 := unit(point{v.x - prev.x, v.y - prev.y})
+	d1 := unit(point{next.x - v.x, next.y - v.y})
+	cross := d0.x*d1.y - d0.y*d1.x
+	if st.join == "round" {
+		return circlePolygon(v, hw, tol)
+	}
+	if math.Abs(cross) < 1e-9 {
+		return nil
+	}
+	side := -1.0
+	if cross < 0 {
+		side = 1
+	}
+	n0 := point{-d0.y * hw * side, d0.x * hw * side}
+	n1 := point{-d1.y * hw * side, d1.x * hw * side}
+	a, b := point{v.x + n0.x, v.y + n0.y}, point{v.x + n1.x, v.y + n1.y}
+	if st.join == "bevel" {
+		return []point{v, a, b}
+	}
+	// miter: the tip lies along the bisector at hw / cos(theta/2)
+	cosTheta := d0.x*d1.x + d0.y*d1.y
+	ratio := 1 / math.Sqrt((1-cosTheta)/2) // 1 / sin(phi/2)
+	if st.miterLimit > 0 && ratio > st.miterLimit {
+		return []point{v, a, b}
+	}
+	bis := unit(point{n0.x + n1.x, n0.y + n1.y})
+	l := hw * ratio
+	return []point{v, a, {v.x + bis.x*l, v.y + bis.y*l}, b}
+}
+
+func circlePolygon(c point, r, tol float64) []point {
+	n := 8
+	if r > tol {
+		n = int(math.Ceil(math.Pi / math.Acos(1-tol/r)))
+	}
+	n = max(8, min(n, 256))
+	poly := make([]point, n)
+	for i := range poly {
+		s, co := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		poly[i] = point{c.x + r*co, c.y + r*s}
+	}
+	return poly
+}
+
+func signedArea(poly []point) float64 {
+	var a float64
+	for i := range poly {
+		p, q := poly[i], poly[(i+1)%len(poly)]
+		a += p.x*q.y - q.x*p.y
+	}
+	return a / 2
+}
+
+func unit(p point) point {
+	l := math.Hypot(p.x, p.y)
+	if l == 0 {
+		return point{}
+	}
+	return point{p.x / l, p.y / l}
+}
+
+// dedupe drops consecutive duplicate points.
+func dedupe(pts []point) []point {
+	out := pts[:0:0]
+	for i, p := range pts {
+		if i == 0 || p != pts[i-1] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package multiavatar
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// rasterize renders an SVG body in a 0 0 10 10 viewBox to a 10×10 image.
+func rasterize(t *testing.T, body string) *image.RGBA {
+	t.Helper()
+	img, err := rasterizeSVG(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10">`+body+`</svg>`, 10, 10)
+	if err != nil {
+		t.Fatalf("rasterizeSVG: %v", err)
+	}
+	return img
+}
+
+// checkPixels compares the pixel centres of img against want, which maps
+// "x,y" to the expected colour.
+func checkPixels(t *testing.T, img *image.RGBA, want map[[2]int]color.RGBA) {
+	t.Helper()
+	for p, c := range want {
+		if got := img.RGBAAt(p[0], p[1]); got != c {
+			t.Errorf("pixel (%d, %d) = %v, want %v", p[0], p[1], got, c)
+		}
+	}
+}
+
+var (
+	red         = color.RGBA{0xff, 0, 0, 0xff}
+	blue        = color.RGBA{0, 0, 0xff, 0xff}
+	transparent = color.RGBA{}
+)
+
+func TestRasterizeSize(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {10, 10}, {64, 64}, {7, 13}} {
+		img, err := rasterizeSVG(`<svg viewBox="0 0 10 10"><rect width="10" height="10" fill="red"/></svg>`, size[0], size[1])
+		if err != nil {
+			t.Fatalf("rasterizeSVG: %v", err)
+		}
+		if got := img.Bounds(); got != image.Rect(0, 0, size[0], size[1]) {
+			t.Errorf("rasterizeSVG(%dx%d) bounds = %v", size[0], size[1], got)
+		}
+		if got := img.RGBAAt(size[0]/2, size[1]/2); got != red {
+			t.Errorf("rasterizeSVG(%dx%d) centre = %v, want %v", size[0], size[1], got, red)
+		}
+	}
+}
+
+func TestRasterizeShapes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[[2]int]color.RGBA
+	}{
+		{"rect", `<rect x="2" y="2" width="4" height="4" fill="#f00"/>`,
+			map[[2]int]color.RGBA{{3, 3}: red, {1, 1}: transparent, {6, 6}: transparent}},
+		{"path", `<path d="M2 2h4v4h-4z" fill="#0000ff"/>`,
+			map[[2]int]color.RGBA{{3, 3}: blue, {5, 5}: blue, {7, 7}: transparent}},
+		{"circle", `<circle cx="5" cy="5" r="4" fill="red"/>`,
+			map[[2]int]color.RGBA{{5, 5}: red, {0, 0}: transparent, {9, 9}: transparent}},
+		{"arc path", `<path d="M1 5A4 4 0 0 1 9 5A4 4 0 0 1 1 5Z" fill="red"/>`,
+			map[[2]int]color.RGBA{{5, 5}: red, {5, 2}: red, {0, 0}: transparent, {9, 0}: transparent}},
+		{"polygon", `<polygon points="0,0 10,0 0,10" fill="red"/>`,
+			map[[2]int]color.RGBA{{1, 1}: red, {8, 8}: transparent}},
+		{"later shapes paint over earlier ones", `<rect width="10" height="10" fill="red"/><rect width="5" height="10" fill="blue"/>`,
+			map[[2]int]color.RGBA{{2, 5}: blue, {7, 5}: red}},
+		{"fill none", `<rect width="10" height="10" fill="none"/>`,
+			map[[2]int]color.RGBA{{5, 5}: transparent}},
+		{"style attribute", `<rect width="10" height="10" style="fill:#00f"/>`,
+			map[[2]int]color.RGBA{{5, 5}: blue}},
+		{"group fill is inherited", `<g fill="blue"><rect width="10" height="10"/></g>`,
+			map[[2]int]color.RGBA{{5, 5}: blue}},
+		{"viewBox scales", `<svg viewBox="0 0 2 2" width="10" height="10"><rect width="1" height="1" fill="red"/></svg>`,
+			map[[2]int]color.RGBA{{2, 2}: red, {7, 7}: transparent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPixels(t, rasterize(t, tt.body), tt.want)
+		})
+	}
+}
+
+func TestRasterizeFillRule(t *testing.T) {
+	// two squares wound the same way, and the inner one wound backwards
+	same := "M1 1H9V9H1Z M3 3H7V7H3Z"
+	opposite := "M1 1H9V9H1Z M3 3V7H7V3Z"
+	tests := []struct {
+		name string
+		body string
+		hole bool
+	}{
+		{"nonzero same winding", `<path d="` + same + `" fill="red"/>`, false},
+		{"nonzero is the default", `<path d="` + same + `" fill="red" fill-rule="nonzero"/>`, false},
+		{"nonzero opposite winding", `<path d="` + opposite + `" fill="red"/>`, true},
+		{"evenodd same winding", `<path d="` + same + `" fill="red" fill-rule="evenodd"/>`, true},
+		{"evenodd opposite winding", `<path d="` + opposite + `" fill="red" fill-rule="evenodd"/>`, true},
+		{"evenodd inherited", `<g fill-rule="evenodd"><path d="` + same + `" fill="red"/></g>`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			centre := red
+			if tt.hole {
+				centre = transparent
+			}
+			checkPixels(t, rasterize(t, tt.body), map[[2]int]color.RGBA{{2, 2}: red, {5, 5}: centre, {0, 0}: transparent})
+		})
+	}
+}
+
+func TestRasterizeTransform(t *testing.T) {
+	square := `<rect width="2" height="2" fill="red"`
+	tests := []struct {
+		name string
+		body string
+		want map[[2]int]color.RGBA
+	}{
+		{"translate", square + ` transform="translate(6 6)"/>`,
+			map[[2]int]color.RGBA{{7, 7}: red, {1, 1}: transparent}},
+		{"scale", square + ` transform="scale(3)"/>`,
+			map[[2]int]color.RGBA{{5, 5}: red, {7, 7}: transparent}},
+		{"rotate about a centre", square + ` transform="rotate(180 5 5)"/>`,
+			map[[2]int]color.RGBA{{9, 9}: red, {1, 1}: transparent}},
+		{"matrix", square + ` transform="matrix(1 0 0 1 0 8)"/>`,
+			map[[2]int]color.RGBA{{1, 9}: red, {1, 1}: transparent}},
+		{"group", `<g transform="translate(8 0)">` + square + `/></g>`,
+			map[[2]int]color.RGBA{{9, 1}: red, {1, 1}: transparent}},
+		{"nested groups compose", `<g transform="translate(4 4)"><g transform="scale(2)">` + square + `/></g></g>`,
+			map[[2]int]color.RGBA{{7, 7}: red, {3, 3}: transparent, {8, 8}: transparent}},
+		{"element after group", `<g transform="translate(8 8)">` + square + ` transform="translate(-8 0)"/></g>`,
+			map[[2]int]color.RGBA{{1, 9}: red, {9, 9}: transparent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPixels(t, rasterize(t, tt.body), tt.want)
+		})
+	}
+}
+
+func TestRasterizeOpacity(t *testing.T) {
+	img := rasterize(t, `<rect width="10" height="10" fill="red" opacity="0.5"/>`)
+	if got := img.RGBAAt(5, 5); got.A < 0x7f || got.A > 0x80 || got.R != got.A || got.G != 0 || got.B != 0 {
+		t.Errorf("half-opaque red = %v, want premultiplied red at alpha 0x80", got)
+	}
+}
+
+func TestAvatarPNGGolden(t *testing.T) {
+	a := Resolve("alice", WithoutPart("clo"), WithoutPart("head"), WithoutPart("mouth"),
+		WithoutPart("eyes"), WithoutPart("top"))
+	env := Describe("alice").Parts["env"].Colors[0]
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(env, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		t.Fatalf("env colour %q: %v", env, err)
+	}
+	want := color.NRGBA{r, g, b, 0xff}
+
+	for _, size := range []int{16, 64, 231} {
+		data, err := a.PNG(size)
+		if err != nil {
+			t.Fatalf("PNG(%d): %v", size, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("PNG(%d) does not decode: %v", size, err)
+		}
+		if got := img.Bounds(); got != image.Rect(0, 0, size, size) {
+			t.Errorf("PNG(%d) bounds = %v", size, got)
+		}
+		if got := color.NRGBAModel.Convert(img.At(size/2, size/2)); got != want {
+			t.Errorf("PNG(%d) centre = %v, want the env colour %v", size, got, want)
+		}
+		if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+			t.Errorf("PNG(%d) corner alpha = %d, want transparent", size, a)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package multiavatar
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// Rasterizer renders an SVG document to a width×height image, scaling its
+// viewBox uniformly and centering it, with transparent pixels outside the
+// artwork. Plug in another backend with WithRasterizer, e.g. one calling
+// resvg or librsvg for higher fidelity, or a faster one for thumbnails.
+// Rasterize must be safe for concurrent use and should stop early when ctx
+// is done.
+type Rasterizer interface {
+	Rasterize(ctx context.Context, svg string, width, height int) (image.Image, error)
+}
+
+// RasterizerFunc adapts a function to the Rasterizer interface.
+type RasterizerFunc func(ctx context.Context, svg string, width, height int) (image.Image, error)
+
+// Rasterize calls f.
+func (f RasterizerFunc) Rasterize(ctx context.Context, svg string, width, height int) (image.Image, error) {
+	return f(ctx, svg, width, height)
+}
+
+// builtinRasterizer is the pure-Go rasterizer of this package.
+type builtinRasterizer struct{}
+
+func (builtinRasterizer) Rasterize(ctx context.Context, svg string, width, height int) (image.Image, error) {
+	return rasterizeSVGContext(ctx, svg, width, height)
+}
+
+// DefaultRasterizer returns the built-in pure-Go rasterizer, which needs
+// no cgo or external programs and covers the SVG features the avatars use.
+// Wrap it to fall back to it from another backend.
+func DefaultRasterizer() Rasterizer { return builtinRasterizer{} }
+
+// WithRasterizer renders the raster outputs (GenerateImage, Avatar.PNG,
+// Encode, GIF, WebP, sheet images and ANSI art) with r instead of the
+// built-in rasterizer. The art of StylePixel is always rasterized by the
+// built-in one, so the SVG stays the same across backends. A nil r
+// restores the default.
+//
+//	resvg := multiavatar.RasterizerFunc(func(ctx context.Context, svg string, w, h int) (image.Image, error) {
+//		cmd := exec.CommandContext(ctx, "resvg", "-w", strconv.Itoa(w), "-h", strconv.Itoa(h), "-", "-c")
+//		cmd.Stdin = strings.NewReader(svg)
+//		out, err := cmd.Output()
+//		if err != nil {
+//			return nil, err
+//		}
+//		return png.Decode(bytes.NewReader(out))
+//	})
+func WithRasterizer(r Rasterizer) Option {
+	return func(c *config) { c.rasterizer = r }
+}
+
+// rasterize renders svg with the configured rasterizer and returns the
+// image as RGBA, converting images of other types. An image of the wrong
+// size is an error.
+func (cfg *config) rasterize(ctx context.Context, svg string, w, h int) (*image.RGBA, error) {
+	if cfg.rasterizer == nil {
+		return rasterizeSVGContext(ctx, svg, w, h)
+	}
+	img, err := cfg.rasterizer.Rasterize(ctx, svg, w, h)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return nil, fmt.Errorf("multiavatar: rasterizer returned no image")
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() != w || bounds.Dy() != h {
+		return nil, fmt.Errorf("multiavatar: rasterizer returned a %d×%d image, want %d×%d", bounds.Dx(), bounds.Dy(), w, h)
+	}
+	if rgba, ok := img.(*image.RGBA); ok && bounds.Min == (image.Point{}) {
+		return rgba, nil
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba, nil
+}
+
+// rasterizeAvatar is rasterize for the SVG of a single avatar, adding the
+// texture the built-in rasterizer leaves out.
+func (cfg *config) rasterizeAvatar(ctx context.Context, svg string, w, h int) (*image.RGBA, error) {
+	img, err := cfg.rasterize(ctx, svg, w, h)
+	if err != nil {
+		return nil, err
+	}
+	cfg.drawTexture(img, img.Bounds())
+	return img, nil
+}
@@ -0,0 +1,67 @@
+package multiavatar
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// errInvalidUUID is returned by ParseUUID.
+var errInvalidUUID = errors.New("multiavatar: invalid UUID")
+
+// GenerateFromBytes creates the SVG avatar of a raw binary value, such as
+// a database key, hashing b itself rather than a text rendering of it. It
+// is Generate(string(b), opts...), except that neither Unicode nor email
+// normalization applies; the other APIs give the same avatar for the input
+// string(b) when it is in NFC.
+// An empty b gives an empty result.
+func GenerateFromBytes(b []byte, opts ...Option) string {
+	if len(b) == 0 {
+		return ""
+	}
+	cfg := newConfig(opts)
+	cfg.normalizeEmail, cfg.normalization = false, NormalizationNone
+	return cfg.renderSVG(string(b))
+}
+
+// GenerateFromUint64 creates the SVG avatar of a numeric id, hashing its
+// eight big-endian bytes, so the avatar does not depend on how the id is
+// formatted ("42" or "0042"). For signed ids, pass uint64(id).
+func GenerateFromUint64(n uint64, opts ...Option) string {
+	return GenerateFromBytes(binary.BigEndian.AppendUint64(nil, n), opts...)
+}
+
+// GenerateFromUUID creates the SVG avatar of a UUID, hashing its sixteen
+// bytes, so upper- and lowercase spellings of the UUID get the same
+// avatar. Types such as github.com/google/uuid.UUID can be passed as is;
+// use ParseUUID for UUIDs in text form.
+func GenerateFromUUID(id [16]byte, opts ...Option) string {
+	return GenerateFromBytes(id[:], opts...)
+}
+
+// ParseUUID decodes a UUID in its canonical form
+// "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", in either case, with or without
+// hyphens, braces or a "urn:uuid:" prefix.
+func ParseUUID(s string) ([16]byte, error) {
+	var id [16]byte
+	s = strings.TrimSpace(s)
+	if len(s) > 9 && strings.EqualFold(s[:9], "urn:uuid:") {
+		s = s[9:]
+	} else if len(s) > 2 && s[0] == '{' && s[len(s)-1] == '}' {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return id, errInvalidUUID
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return id, errInvalidUUID
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return id, errInvalidUUID
+	}
+	return id, nil
+}
@@ -0,0 +1,271 @@
+package multiavatar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svgWriter is where SVG markup is assembled: a *strings.Builder for the
+// string APIs or a *bufio.Writer for GenerateTo. Write errors are not
+// checked while writing; bufio.Writer reports the first one on Flush.
+type svgWriter interface {
+	io.Writer
+	io.StringWriter
+}
+
+// writeSVG assembles the final SVG document for the selected parts.
+func (cfg *config) writeSVG(b svgWriter, selected []selectedPart) {
+	cfg.writeSVGStart(b)
+	cfg.writeBody(b, selected)
+	b.WriteString(`</svg>`)
+}
+
+// writeSVGStart writes the <svg> start tag of the avatar.
+func (cfg *config) writeSVGStart(b svgWriter) {
+	if cfg.size > 0 {
+		fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s" width="%d" height="%d"%s>`, cfg.viewBoxAttr(), cfg.size, cfg.size, cfg.rootAttrString())
+	} else {
+		b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="` + cfg.viewBoxAttr() + `"` + cfg.rootAttrString() + `>`)
+	}
+}
+
+// writeBody writes the avatar layers, without the enclosing <svg> element.
+func (cfg *config) writeBody(b svgWriter, selected []selectedPart) {
+	if cfg.precision != nil {
+		buf := getBuffer(0)
+		defer putBuffer(buf)
+		exact := *cfg
+		exact.precision = nil
+		exact.writeBody(buf, selected)
+		writeRounded(b, buf.String(), *cfg.precision)
+		return
+	}
+	if len(cfg.darkColors) > 0 {
+		b.WriteString(`<g class="` + cfg.writeDarkModeStyle(b) + `">`)
+		defer b.WriteString(`</g>`)
+	}
+	if cfg.texture != nil {
+		b.WriteString(`<g filter="url(#` + cfg.writeTextureFilter(b) + `)">`)
+		defer b.WriteString(`</g>`)
+	}
+	if cfg.rotation != 0 {
+		b.WriteString(`<g transform="` + cfg.rotateTransform() + `">`)
+		defer b.WriteString(`</g>`)
+	}
+	if cfg.mirror {
+		b.WriteString(`<g transform="` + mirrorTransform + `">`)
+	}
+	cfg.writeArt(b, selected)
+	if cfg.mirror {
+		b.WriteString(`</g>`)
+	}
+	if cfg.border != nil {
+		cfg.beginLayer(b, "border")
+		b.WriteString(cfg.border.render(cfg.filterColor, cfg.bgShape))
+		cfg.endLayer(b)
+	}
+	if cfg.frame != nil {
+		cfg.beginLayer(b, "frame")
+		b.WriteString(cfg.frame.render(cfg.filterColor))
+		cfg.endLayer(b)
+	}
+	if cfg.overlay != nil {
+		cfg.beginLayer(b, "overlay")
+		b.WriteString(cfg.overlay.render(cfg.filterColor))
+		cfg.endLayer(b)
+	}
+	if cfg.badge != nil {
+		cfg.beginLayer(b, "badge")
+		b.WriteString(cfg.badge.render(cfg.filterColor))
+		cfg.endLayer(b)
+	}
+}
+
+// writeArt draws the artwork of the style, outlined with WithOutlineStyle.
+func (cfg *config) writeArt(b svgWriter, selected []selectedPart) {
+	out := b
+	var buf *bytes.Buffer
+	if cfg.outline != nil {
+		buf = getBuffer(0)
+		defer putBuffer(buf)
+		out = buf
+	}
+	if cfg.pack != nil && cfg.pack.compose != nil {
+		art := cfg
+		if cfg.partStyles != nil {
+			// the style draws every part itself
+			whole := *cfg
+			whole.partStyles = nil
+			art = &whole
+		}
+		cfg.beginLayer(out, "art")
+		cfg.pack.compose(art, out, selected)
+		cfg.endLayer(out)
+	} else {
+		cfg.writeLayers(out, selected)
+	}
+	if cfg.outline != nil {
+		cfg.outline.write(b, cfg.filterColor, buf.String())
+	}
+}
+
+// writeLayers draws the selected parts of a part-based style.
+func (cfg *config) writeLayers(b svgWriter, selected []selectedPart) {
+	byName := make(map[string]selectedPart, len(selected))
+	for _, p := range selected {
+		byName[p.name] = p
+	}
+
+	// 5. Assemble the layers
+	order := cfg.layerOrder
+	if order == nil {
+		order = defaultLayerOrder
+	}
+	for _, name := range order {
+		if !cfg.disabledParts[name] {
+			cfg.beginLayer(b, name)
+			if pack := cfg.partStyles[name]; pack != nil && pack.compose != nil {
+				cfg.composePart(b, pack, selected, name)
+			} else {
+				cfg.writeLayer(b, byName[name])
+			}
+			cfg.endLayer(b)
+		}
+		cfg.writeExtraParts(b, byName, name)
+	}
+	cfg.writeExtraParts(b, byName, "")
+}
+
+// writeLayer draws one of the six original parts.
+func (cfg *config) writeLayer(b svgWriter, p selectedPart) {
+	if cfg.plainPart(p) {
+		cfg.writePart(b, p)
+		if p.name == "clo" && cfg.clothingLogo != nil {
+			b.WriteString(cfg.clothingLogo.render(p.version))
+		}
+		return
+	}
+	var svg string
+	switch p.name {
+	case "env":
+		if cfg.withoutBackground || cfg.bgShape == ShapeNone {
+			b.WriteString(cfg.bgPlaceholder())
+			return
+		}
+		if cfg.bgPattern != nil {
+			p.colors = []string{safeColor(cfg.filterColor(cfg.bgPattern.base))}
+		} else if cfg.bgGradient != nil {
+			b.WriteString(`<defs>`)
+			g := *cfg.bgGradient
+			g.from, g.to = cfg.filterColor(g.from), cfg.filterColor(g.to)
+			id := cfg.defID("bg", g.key())
+			g.writeDef(b, id)
+			b.WriteString(`</defs>`)
+			p.colors = []string{"url(#" + id + ")"}
+		}
+		if cfg.bgShape == ShapeSquircle && len(p.colors) > 0 {
+			svg = cfg.shapeElement(cfg.themedColor("env", 0, p.colors[0]))
+		} else {
+			svg = cfg.renderPart(p)
+		}
+		if cfg.bgPattern != nil {
+			buf := getBuffer(0)
+			cfg.writePattern(buf, p.version)
+			svg += buf.String()
+			putBuffer(buf)
+		}
+	case "clo":
+		svg = cfg.renderPart(p)
+		if cfg.clothingLogo != nil {
+			svg += cfg.clothingLogo.render(p.version)
+		}
+	case "eyes":
+		svg = cfg.blinkEyes(p.version, cfg.renderPart(p))
+	case "mouth":
+		svg = cfg.flipMouth(p.version, cfg.renderPart(p))
+	default:
+		svg = cfg.renderPart(p)
+	}
+	b.WriteString(cfg.fadePart(p.name, cfg.transformPart(p, svg)))
+}
+
+// plainPart reports whether p is drawn as its template alone, without
+// wrappers or rewrites, so writeLayer can write it without building an
+// intermediate string.
+func (cfg *config) plainPart(p selectedPart) bool {
+	switch p.name {
+	case "env":
+		return false
+	case "eyes":
+		if cfg.blink {
+			return false
+		}
+	case "mouth":
+		if len(cfg.flippedMouths) > 0 {
+			return false
+		}
+	}
+	_, transformed := cfg.partTransforms[p.name]
+	_, faded := cfg.partOpacity[p.name]
+	return !transformed && !faded
+}
+
+// renderPart retrieves the raw SVG template for a part and replaces its
+// color placeholders with the resolved colors.
+func (cfg *config) renderPart(p selectedPart) string {
+	if svg, ok := cfg.cachedPart(p); ok {
+		return svg
+	}
+	buf := getBuffer(0)
+	defer putBuffer(buf)
+	cfg.recolor(buf, p)
+	return buf.String()
+}
+
+// writePart writes the template of a part with its color placeholders
+// replaced, from the fragment cache when possible.
+func (cfg *config) writePart(b svgWriter, p selectedPart) {
+	if svg, ok := cfg.cachedPart(p); ok {
+		b.WriteString(svg)
+		return
+	}
+	cfg.recolor(b, p)
+}
+
+// recolor writes the template of a part with its color placeholders like
+// "#01;" replaced in one pass. They are listed in order, so each is the
+// next occurrence of its text.
+func (cfg *config) recolor(b svgWriter, p selectedPart) {
+	tmpl, ok := cfg.template(p)
+	if !ok {
+		return // unknown version or theme
+	}
+	rest := tmpl.svg
+	for i, placeholder := range tmpl.placeholders {
+		if i >= len(p.colors) {
+			break
+		}
+		j := strings.Index(rest, placeholder)
+		if j < 0 {
+			break
+		}
+		b.WriteString(rest[:j])
+		b.WriteString(cfg.themedColor(p.name, i, p.colors[i]))
+		b.WriteString(";")
+		rest = rest[j+len(placeholder):]
+	}
+	b.WriteString(rest)
+}
+
+// formatFloat formats a generated coordinate compactly, with at most four decimals.
+func formatFloat(v float64) string {
+	v = math.Round(v*1e4) / 1e4
+	if v == 0 {
+		v = 0 // normalize -0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,97 @@
+package multiavatar
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// rootAttrNames lists the attributes WithRootAttr accepts besides data-*
+// and aria-* ones. Attributes the renderer sets itself, event handlers
+// and style are left out so options cannot break or script the avatar.
+var rootAttrNames = map[string]bool{
+	"id": true, "role": true, "focusable": true, "tabindex": true,
+	"lang": true, "preserveAspectRatio": true,
+}
+
+// WithRootAttr sets an attribute on the root <svg> element, e.g.
+// WithRootAttr("focusable", "false") for older Internet Explorer and Edge,
+// WithRootAttr("role", "img") or WithRootAttr("data-user", id). Accepted
+// names are data-* and aria-* attributes, id, role, focusable, tabindex,
+// lang and preserveAspectRatio; others are reported as errors by the
+// error-returning APIs. The value is escaped. Setting a name twice keeps
+// the last value. Use WithClass for classes.
+func WithRootAttr(key, value string) Option {
+	return func(c *config) {
+		if !validRootAttr(key) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: root attribute %q not allowed", key))
+			return
+		}
+		i := slices.IndexFunc(c.rootAttrs, func(a [2]string) bool { return a[0] == key })
+		if i >= 0 {
+			c.rootAttrs[i][1] = value
+			return
+		}
+		c.rootAttrs = append(c.rootAttrs, [2]string{key, value})
+	}
+}
+
+// WithClass adds classes to the root <svg> element, e.g.
+// WithClass("avatar", "avatar--small"). Repeated calls accumulate and
+// duplicates are dropped. Class names containing whitespace are reported
+// as errors by the error-returning APIs.
+func WithClass(classes ...string) Option {
+	return func(c *config) {
+		for _, cl := range classes {
+			if cl == "" || strings.ContainsFunc(cl, isSpace) {
+				c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid class %q", cl))
+				continue
+			}
+			if !slices.Contains(c.rootClasses, cl) {
+				c.rootClasses = append(c.rootClasses, cl)
+			}
+		}
+	}
+}
+
+// validRootAttr reports whether WithRootAttr accepts the attribute name.
+func validRootAttr(name string) bool {
+	if rootAttrNames[name] {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(name, "data-")
+	if !ok {
+		suffix, ok = strings.CutPrefix(name, "aria-")
+	}
+	if !ok || suffix == "" {
+		return false
+	}
+	for _, r := range suffix {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// isSpace reports whether r separates class names in a class attribute.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
+// rootAttrString returns the attributes set with WithClass and WithRootAttr,
+// each with a leading space, for the root <svg> start tag.
+func (cfg *config) rootAttrString() string {
+	if len(cfg.rootClasses) == 0 && len(cfg.rootAttrs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	if len(cfg.rootClasses) > 0 {
+		b.WriteString(` class="` + html.EscapeString(strings.Join(cfg.rootClasses, " ")) + `"`)
+	}
+	for _, a := range cfg.rootAttrs {
+		b.WriteString(` ` + a[0] + `="` + html.EscapeString(a[1]) + `"`)
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package multiavatar
+
+import (
+	"fmt"
+	"math"
+)
+
+// WithRotation tilts the avatar by degrees clockwise about its center, for
+// playful layouts and 404 pages. The canvas grows to the bounding box of
+// the rotated avatar, so nothing is clipped. NaN and infinite angles are
+// reported as errors by the error-returning APIs.
+func WithRotation(degrees float64) Option {
+	return func(c *config) {
+		if math.IsNaN(degrees) || math.IsInf(degrees, 0) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid rotation %v", degrees))
+			return
+		}
+		c.rotation = math.Mod(degrees, 360)
+	}
+}
+
+// baseViewBox returns the viewBox before rotation and padding.
+func (cfg *config) baseViewBox() [4]float64 {
+	if cfg.viewBox != nil {
+		return *cfg.viewBox
+	}
+	return [4]float64{0, 0, canvasSize, canvasSize}
+}
+
+// rotateTransform returns the transform of the root group of rotated
+// avatars: a rotation about the center of the viewBox.
+func (cfg *config) rotateTransform() string {
+	vb := cfg.baseViewBox()
+	return "rotate(" + formatFloat(cfg.rotation) + " " + formatFloat(vb[0]+vb[2]/2) + " " + formatFloat(vb[1]+vb[3]/2) + ")"
+}
+
+// rotateBox returns the bounding box of vb rotated by the configured angle
+// about its center.
+func (cfg *config) rotateBox(vb [4]float64) [4]float64 {
+	sin, cos := math.Sincos(cfg.rotation * math.Pi / 180)
+	sin, cos = math.Abs(sin), math.Abs(cos)
+	w := vb[2]*cos + vb[3]*sin
+	h := vb[2]*sin + vb[3]*cos
+	return [4]float64{vb[0] - (w-vb[2])/2, vb[1] - (h-vb[3])/2, w, h}
+}
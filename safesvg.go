@@ -0,0 +1,51 @@
+package multiavatar
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// SafeSVG is an SVG document generated by this package. Its markup is
+// built from the package's own art and from validated colors and
+// attributes, so it can be inlined in HTML, and it converts to the types
+// templates, JSON APIs and databases expect:
+//
+//	tmpl.Execute(w, struct{ Avatar multiavatar.SafeSVG }{multiavatar.GenerateSafe(name)})
+//	// {{.Avatar.HTML}} inlines the SVG, <img src="{{.Avatar.URL}}"> links it
+type SafeSVG string
+
+// GenerateSafe is Generate returning a SafeSVG.
+func GenerateSafe(input string, opts ...Option) SafeSVG {
+	return SafeSVG(Generate(input, opts...))
+}
+
+// SafeSVG returns the SVG document as a SafeSVG.
+func (a *Avatar) SafeSVG() SafeSVG {
+	return SafeSVG(a.SVG())
+}
+
+// String returns the SVG document.
+func (s SafeSVG) String() string {
+	return string(s)
+}
+
+// DataURI returns the SVG document as a base64 data URI, or "" for an
+// empty document.
+func (s SafeSVG) DataURI() string {
+	if s == "" {
+		return ""
+	}
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// MarshalJSON encodes the SVG document as a JSON string holding its data
+// URI, ready for an <img> src on the client.
+func (s SafeSVG) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.DataURI())
+}
+
+// Value implements driver.Valuer, storing the SVG document as text.
+func (s SafeSVG) Value() (driver.Value, error) {
+	return string(s), nil
+}
@@ -0,0 +1,20 @@
+//go:build !tinygo && !multiavatar_minimal
+
+package multiavatar
+
+import "html/template"
+
+// The html/template conversions are left out of minimal builds, which
+// would otherwise link the template engine.
+
+// HTML returns the SVG document as template.HTML, which html/template
+// inlines without escaping.
+func (s SafeSVG) HTML() template.HTML {
+	return template.HTML(s)
+}
+
+// URL returns the data URI as template.URL, which html/template accepts
+// in src and href attributes.
+func (s SafeSVG) URL() template.URL {
+	return template.URL(s.DataURI())
+}
@@ -0,0 +1,136 @@
+package multiavatar
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxFragmentBytes and maxFragmentElements bound user-supplied SVG fragments.
+const (
+	maxFragmentBytes    = 16 << 10
+	maxFragmentElements = 256
+)
+
+// fragmentElements are the drawing elements allowed in user-supplied SVG fragments.
+var fragmentElements = map[string]bool{
+	"g": true, "path": true, "circle": true, "ellipse": true, "rect": true,
+	"line": true, "polyline": true, "polygon": true,
+}
+
+// fragmentAttrs are the geometry and presentation attributes allowed in
+// user-supplied SVG fragments. Event handlers, links and ids are dropped.
+var fragmentAttrs = map[string]bool{
+	"d": true, "cx": true, "cy": true, "r": true, "rx": true, "ry": true,
+	"x": true, "y": true, "width": true, "height": true,
+	"x1": true, "y1": true, "x2": true, "y2": true, "points": true, "transform": true,
+	"fill": true, "fill-rule": true, "fill-opacity": true, "opacity": true,
+	"stroke": true, "stroke-width": true, "stroke-linecap": true, "stroke-linejoin": true,
+	"stroke-miterlimit": true, "stroke-opacity": true, "style": true,
+}
+
+// sanitizeFragment parses an untrusted SVG fragment and re-serializes only
+// allowlisted elements and attributes, so it cannot inject scripts, external
+// references or break out of the surrounding markup. If the fragment is a
+// complete <svg> element, its viewBox is returned as well (zero otherwise).
+func sanitizeFragment(frag string) (string, [4]float64, error) {
+	var vb [4]float64
+	if len(frag) > maxFragmentBytes {
+		return "", vb, fmt.Errorf("multiavatar: SVG fragment exceeds %d bytes", maxFragmentBytes)
+	}
+	dec := xml.NewDecoder(strings.NewReader("<root>" + frag + "</root>"))
+	var b strings.Builder
+	// skip counts nested levels inside a dropped element
+	skip, elements := 0, 0
+	var open []string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", vb, fmt.Errorf("multiavatar: invalid SVG fragment: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			if name == "root" && len(open) == 0 && skip == 0 {
+				open = append(open, "")
+				continue
+			}
+			if name == "svg" && len(open) == 1 && skip == 0 && b.Len() == 0 {
+				for _, a := range t.Attr {
+					if a.Name.Local == "viewBox" {
+						if v := parseNumberList(a.Value); len(v) == 4 && v[2] > 0 && v[3] > 0 {
+							vb = [4]float64{v[0], v[1], v[2], v[3]}
+						}
+					}
+				}
+				open = append(open, "")
+				continue
+			}
+			if skip > 0 || !fragmentElements[name] {
+				skip++
+				continue
+			}
+			elements++
+			if elements > maxFragmentElements {
+				return "", vb, fmt.Errorf("multiavatar: SVG fragment exceeds %d elements", maxFragmentElements)
+			}
+			b.WriteString("<" + name)
+			for _, a := range t.Attr {
+				key := a.Name.Local
+				if a.Name.Space != "" || !fragmentAttrs[key] {
+					continue
+				}
+				val := a.Value
+				if key == "style" {
+					val = sanitizeStyle(val)
+				} else if strings.Contains(strings.ToLower(val), "url(") {
+					continue
+				}
+				b.WriteString(" " + key + `="`)
+				_ = xml.EscapeText(&b, []byte(val))
+				b.WriteString(`"`)
+			}
+			b.WriteString(">")
+			open = append(open, name)
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(open) == 0 {
+				continue
+			}
+			if name := open[len(open)-1]; name != "" {
+				b.WriteString("</" + name + ">")
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if elements == 0 {
+		return "", vb, errors.New("multiavatar: SVG fragment has no drawable elements")
+	}
+	return b.String(), vb, nil
+}
+
+// sanitizeStyle keeps only allowlisted presentation properties without url() references.
+func sanitizeStyle(style string) string {
+	var kept []string
+	for _, decl := range strings.Split(style, ";") {
+		k, v, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+		lv := strings.ToLower(v)
+		if !fragmentAttrs[k] || k == "style" || strings.Contains(lv, "url(") || strings.ContainsAny(v, `<>"\`) {
+			continue
+		}
+		kept = append(kept, k+":"+v)
+	}
+	return strings.Join(kept, ";")
+}
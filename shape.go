@@ -0,0 +1,101 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BackgroundShape is the geometry of the avatar background.
+type BackgroundShape int
+
+const (
+	// ShapeNone draws no background, like WithoutBackground.
+	ShapeNone BackgroundShape = iota + 1
+	// ShapeCircle is the original round background.
+	ShapeCircle
+	// ShapeSquircle is a rounded square that fills square containers.
+	ShapeSquircle
+)
+
+// envCirclePath is the outline of the original background circle.
+const envCirclePath = "M33.83,33.83a115.5,115.5,0,1,1,0,163.34,115.49,115.49,0,0,1,0-163.34Z"
+
+// bgPlaceholderClass is the class of the unfilled background shape drawn
+// with WithoutBackground, for styling with CSS.
+const bgPlaceholderClass = "multiavatar-bg"
+
+// WithBackgroundShape sets the geometry of the background independently of
+// its color. ShapeSquircle swaps the circle for a rounded square in the env
+// color, and borders and identicons follow it.
+//
+// Combined with WithoutBackground, ShapeCircle and ShapeSquircle keep the
+// shape as an unfilled placeholder with class "multiavatar-bg", which a
+// stylesheet can fill, e.g. .multiavatar-bg { fill: var(--surface) }.
+// Unknown shapes are reported as errors by the error-returning APIs.
+func WithBackgroundShape(shape BackgroundShape) Option {
+	return func(c *config) {
+		if shape < ShapeNone || shape > ShapeSquircle {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown background shape %v", shape))
+			return
+		}
+		c.bgShape = shape
+	}
+}
+
+func (s BackgroundShape) String() string {
+	switch s {
+	case ShapeNone:
+		return "none"
+	case ShapeCircle:
+		return "circle"
+	case ShapeSquircle:
+		return "squircle"
+	}
+	return "BackgroundShape(" + strconv.Itoa(int(s)) + ")"
+}
+
+// ParseBackgroundShape returns the shape named s, ignoring case.
+func ParseBackgroundShape(s string) (BackgroundShape, bool) {
+	for sh := ShapeNone; sh <= ShapeSquircle; sh++ {
+		if strings.EqualFold(s, sh.String()) {
+			return sh, true
+		}
+	}
+	return 0, false
+}
+
+// squirclePath returns the outline of the squircle inset by d units from
+// the edge of the canvas.
+func squirclePath(d float64) string {
+	lo, hi, mid := d, canvasSize-d, canvasSize/2.0
+	k := (canvasSize - 2*d) * 20 / canvasSize // corner control offset
+	f := formatFloat
+	return "M" + f(lo) + "," + f(mid) +
+		"C" + f(lo) + "," + f(lo+k) + " " + f(lo+k) + "," + f(lo) + " " + f(mid) + "," + f(lo) +
+		"S" + f(hi) + "," + f(lo+k) + " " + f(hi) + "," + f(mid) +
+		"S" + f(hi-k) + "," + f(hi) + " " + f(mid) + "," + f(hi) +
+		"S" + f(lo) + "," + f(hi-k) + " " + f(lo) + "," + f(mid) + "Z"
+}
+
+// shapePath returns the outline of the configured background shape.
+func (cfg *config) shapePath() string {
+	if cfg.bgShape == ShapeSquircle {
+		return squirclePath(0)
+	}
+	return envCirclePath
+}
+
+// shapeElement returns the background shape filled with fill.
+func (cfg *config) shapeElement(fill string) string {
+	return `<path d="` + cfg.shapePath() + `" style="fill:` + fill + `;"/>`
+}
+
+// bgPlaceholder returns the unfilled background shape drawn in place of
+// the background with WithoutBackground, or "" if no shape was chosen.
+func (cfg *config) bgPlaceholder() string {
+	if !cfg.withoutBackground || cfg.bgShape != ShapeCircle && cfg.bgShape != ShapeSquircle {
+		return ""
+	}
+	return `<path class="` + bgPlaceholderClass + `" d="` + cfg.shapePath() + `" fill="none"/>`
+}
@@ -0,0 +1,130 @@
+package multiavatar
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GenerateSheet renders many avatars into one SVG grid, e.g. for team pages
+// or admin dashboards that would otherwise fetch dozens of images. Avatars
+// are laid out row by row in the given number of columns, each in a
+// cell×cell pixel square. Empty inputs leave their cell blank.
+//
+// GenerateSheet cannot report errors, so it falls back to defaults: a
+// non-positive column count lays every avatar out in one row, a
+// non-positive cell size means 64px, and an empty inputs slice gives an
+// empty result. GenerateSheetTo and GenerateSheetImage report these as
+// errors instead.
+func GenerateSheet(inputs []string, columns int, cell int, opts ...Option) string {
+	cfg := newConfig(opts)
+	if len(inputs) == 0 {
+		return ""
+	}
+	if columns <= 0 {
+		columns = len(inputs)
+	}
+	if cell <= 0 {
+		cell = 64
+	}
+	var b strings.Builder
+	cfg.writeSheet(&b, inputs, columns, cell)
+	return b.String()
+}
+
+// GenerateSheetTo is like GenerateSheet but streams the SVG to w. Unlike
+// GenerateSheet it reports an empty inputs slice, a non-positive column
+// count or cell size, and invalid options as errors.
+func GenerateSheetTo(w io.Writer, inputs []string, columns int, cell int, opts ...Option) error {
+	if err := checkSheet(len(inputs), columns, cell); err != nil {
+		return err
+	}
+	cfg := newConfig(opts)
+	if err := cfg.err(); err != nil {
+		return err
+	}
+	bw := getWriter(w)
+	defer putWriter(bw)
+	cfg.writeSheet(bw, inputs, columns, cell)
+	return bw.Flush()
+}
+
+// GenerateSheetImage is like GenerateSheet but returns the grid as an image.
+// Like GenerateSheetTo, and unlike GenerateSheet, it reports an empty inputs
+// slice and a non-positive column count or cell size as errors.
+func GenerateSheetImage(inputs []string, columns int, cell int, opts ...Option) (image.Image, error) {
+	return GenerateSheetImageContext(context.Background(), inputs, columns, cell, opts...)
+}
+
+// GenerateSheetImageContext is like GenerateSheetImage but stops
+// rasterizing and returns ctx's error once ctx is done.
+func GenerateSheetImageContext(ctx context.Context, inputs []string, columns int, cell int, opts ...Option) (image.Image, error) {
+	cfg := newConfig(opts)
+	if err := cfg.checkOptions(ctx, "GenerateSheetImage"); err != nil {
+		return nil, err
+	}
+	if err := checkSheet(len(inputs), columns, cell); err != nil {
+		return nil, err
+	}
+	columns, rows := sheetGrid(len(inputs), columns)
+	if columns*cell > maxImageSize || rows*cell > maxImageSize {
+		return nil, fmt.Errorf("multiavatar: sheet of %d×%d cells of %dpx exceeds %dpx", columns, rows, cell, maxImageSize)
+	}
+	start := time.Now()
+	var b strings.Builder
+	cfg.writeSheet(&b, inputs, columns, cell)
+	img, err := cfg.rasterize(ctx, b.String(), columns*cell, rows*cell)
+	if err == nil {
+		for i, input := range inputs {
+			if input != "" {
+				x, y := (i%columns)*cell, (i/columns)*cell
+				cfg.drawTexture(img, image.Rect(x, y, x+cell, y+cell))
+			}
+		}
+	}
+	cfg.logBatch(ctx, "GenerateSheetImage", len(inputs), start, err)
+	return img, err
+}
+
+// checkSheet reports an empty sheet of n inputs and a non-positive column
+// count or cell size, which GenerateSheet replaces with defaults.
+func checkSheet(n, columns, cell int) error {
+	switch {
+	case n == 0:
+		return fmt.Errorf("multiavatar: empty sheet")
+	case columns <= 0:
+		return fmt.Errorf("multiavatar: invalid sheet column count %d", columns)
+	case cell <= 0:
+		return fmt.Errorf("multiavatar: invalid sheet cell size %dpx", cell)
+	}
+	return nil
+}
+
+// sheetGrid clamps the positive column count to the n > 0 inputs and
+// returns the grid dimensions.
+func sheetGrid(n, columns int) (int, int) {
+	columns = min(columns, n)
+	return columns, (n + columns - 1) / columns
+}
+
+// writeSheet writes the sheet of inputs, with arguments checkSheet accepts.
+func (cfg *config) writeSheet(b svgWriter, inputs []string, columns, cell int) {
+	columns, rows := sheetGrid(len(inputs), columns)
+	w, h := strconv.Itoa(columns*cell), strconv.Itoa(rows*cell)
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 ` + w + " " + h + `" width="` + w + `" height="` + h + `">`)
+	size := strconv.Itoa(cell)
+	for i, input := range inputs {
+		if input == "" {
+			continue
+		}
+		x, y := strconv.Itoa((i%columns)*cell), strconv.Itoa((i/columns)*cell)
+		b.WriteString(`<svg x="` + x + `" y="` + y + `" width="` + size + `" height="` + size + `" viewBox="` + cfg.viewBoxAttr() + `">`)
+		cfg.writeBody(b, cfg.selectParts(input))
+		b.WriteString(`</svg>`)
+	}
+	b.WriteString(`</svg>`)
+}
@@ -0,0 +1,75 @@
+package multiavatar_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestGenerateSheetEmpty(t *testing.T) {
+	if svg := multiavatar.GenerateSheet(nil, 4, 64); svg != "" {
+		t.Errorf("GenerateSheet(nil) = %q, want empty", svg)
+	}
+}
+
+func TestGenerateSheetLayout(t *testing.T) {
+	inputs := []string{"alice", "bob", "carol"}
+	if svg := multiavatar.GenerateSheet(inputs, 2, 32); !strings.Contains(svg, `viewBox="0 0 64 64"`) {
+		t.Errorf("GenerateSheet with 2 columns of 32px: got %.80s", svg)
+	}
+	if svg := multiavatar.GenerateSheet(inputs, 0, 0); !strings.Contains(svg, `viewBox="0 0 192 64"`) {
+		t.Errorf("GenerateSheet with the default columns and cell: got %.80s", svg)
+	}
+}
+
+func TestGenerateSheetTo(t *testing.T) {
+	inputs := []string{"alice", "", "carol"}
+	var b strings.Builder
+	if err := multiavatar.GenerateSheetTo(&b, inputs, 2, 32); err != nil {
+		t.Fatal(err)
+	}
+	if want := multiavatar.GenerateSheet(inputs, 2, 32); b.String() != want {
+		t.Error("GenerateSheetTo differs from GenerateSheet")
+	}
+}
+
+func TestGenerateSheetInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputs        []string
+		columns, cell int
+		want          string
+	}{
+		{"empty", nil, 2, 32, "empty sheet"},
+		{"columns", []string{"alice"}, 0, 32, "column count 0"},
+		{"cell", []string{"alice"}, 2, 0, "cell size 0px"},
+		{"too large", []string{"alice", "bob"}, 2, 4000, "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := multiavatar.GenerateSheetImage(tt.inputs, tt.columns, tt.cell)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GenerateSheetImage: got error %v, want one containing %q", err, tt.want)
+			}
+			if tt.name == "too large" {
+				return
+			}
+			err = multiavatar.GenerateSheetTo(io.Discard, tt.inputs, tt.columns, tt.cell)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GenerateSheetTo: got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSheetImage(t *testing.T) {
+	img, err := multiavatar.GenerateSheetImage([]string{"alice", "bob", "carol"}, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 32 || b.Dy() != 32 {
+		t.Errorf("GenerateSheetImage: got %v, want 32×32", b)
+	}
+}
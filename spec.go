@@ -0,0 +1,63 @@
+package multiavatar
+
+import "fmt"
+
+// AvatarSpec captures the resolved look of an avatar: the version and theme
+// chosen for every part. Replaying it with ToOptions pins each part, so the
+// avatar stays the same even if the selection algorithm changes.
+type AvatarSpec struct {
+	Seed      string              `json:"seed"`
+	Algorithm int                 `json:"algorithm"`
+	Parts     map[string]PartSpec `json:"parts"`
+}
+
+// PartSpec is the resolved version ("00".."15") and theme ("A", "B", "C") of one part.
+type PartSpec struct {
+	Version string `json:"version"`
+	Theme   string `json:"theme"`
+}
+
+// MigrateSeed captures the avatar that seed produces under algorithm
+// fromAlgo as a spec to be replayed under toAlgo. Store the spec before
+// switching the default algorithm so existing users keep their avatar.
+//
+// Unknown fromAlgo and toAlgo versions are reported as errors.
+func MigrateSeed(seed string, fromAlgo, toAlgo int) (AvatarSpec, error) {
+	for _, v := range []int{fromAlgo, toAlgo} {
+		if !IsKnownAlgorithm(v) {
+			return AvatarSpec{}, fmt.Errorf("multiavatar: unknown algorithm %d", v)
+		}
+	}
+	cfg := newConfig([]Option{WithAlgorithm(Algorithm(fromAlgo))})
+	spec := AvatarSpec{Seed: seed, Algorithm: toAlgo, Parts: make(map[string]PartSpec, len(partNames))}
+	if seed == "" {
+		return spec, nil
+	}
+	for _, p := range cfg.selectParts(seed) {
+		spec.Parts[p.name] = PartSpec{Version: p.version, Theme: p.theme}
+	}
+	return spec, nil
+}
+
+// IsKnownAlgorithm reports whether v is a selection algorithm version implemented by this package.
+func IsKnownAlgorithm(v int) bool {
+	for _, k := range knownAlgorithms {
+		if k == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ToOptions returns options pinning every part to the spec's version and theme.
+func (s AvatarSpec) ToOptions() []Option {
+	opts := make([]Option, 0, 2*len(s.Parts))
+	for _, name := range sortedKeys(s.Parts) {
+		p := s.Parts[name]
+		if extraPartByName(name) != nil {
+			opts = append(opts, WithPart(name))
+		}
+		opts = append(opts, WithPartVersion(name, p.Version), WithPartTheme(name, p.Theme))
+	}
+	return opts
+}
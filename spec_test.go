@@ -0,0 +1,28 @@
+package multiavatar_test
+
+import (
+	"testing"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func TestMigrateSeed(t *testing.T) {
+	spec, err := multiavatar.MigrateSeed("alice", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Algorithm != 2 || len(spec.Parts) != 6 {
+		t.Errorf("MigrateSeed: got algorithm %d with %d parts, want 2 with 6", spec.Algorithm, len(spec.Parts))
+	}
+	if got, want := multiavatar.Generate("alice", spec.ToOptions()...), multiavatar.Generate("alice"); got != want {
+		t.Error("replaying the spec changes the avatar")
+	}
+}
+
+func TestMigrateSeedUnknownAlgorithm(t *testing.T) {
+	for _, algos := range [][2]int{{5, 1}, {1, 9}, {0, 1}} {
+		if _, err := multiavatar.MigrateSeed("a", algos[0], algos[1]); err == nil {
+			t.Errorf("MigrateSeed(%q, %d, %d): got nil error", "a", algos[0], algos[1])
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package multiavatar
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SrcSet is a set of sources of one avatar at several sizes, for serving
+// raster avatars responsively.
+type SrcSet struct {
+	// Sources are ordered by size, smallest first; the first one makes a
+	// good fallback src.
+	Sources []Source
+	// SrcSet is the value of an <img srcset> attribute listing every
+	// source with its width, e.g. "data:image/png;base64,... 64w, ...".
+	SrcSet string
+}
+
+// Source is the avatar at one size.
+type Source struct {
+	// Size is the width and height in pixels.
+	Size int
+	// URL is a data URI, or the URL built by WithSrcSetURL.
+	URL string
+}
+
+// WithSrcSetURL makes GenerateSrcSet list the URL built by fn for each
+// size instead of rendering a data URI, e.g. to point at an avatar CDN.
+// Other APIs ignore it.
+func WithSrcSetURL(fn func(input string, size int, format Format) string) Option {
+	return func(c *config) {
+		c.srcSetURL = fn
+	}
+}
+
+// GenerateSrcSet returns the avatar for input at each of sizes in format,
+// as data URIs or, with WithSrcSetURL, as URLs, together with a ready
+// srcset attribute value. Duplicate sizes are listed once. SVG sources
+// carry the size as their width and height.
+func GenerateSrcSet(input string, sizes []int, format Format, opts ...Option) (*SrcSet, error) {
+	return GenerateSrcSetContext(context.Background(), input, sizes, format, opts...)
+}
+
+// GenerateSrcSetContext is like GenerateSrcSet but stops and returns ctx's
+// error once ctx is done.
+func GenerateSrcSetContext(ctx context.Context, input string, sizes []int, format Format, opts ...Option) (*SrcSet, error) {
+	if input == "" {
+		return nil, ErrEmptyInput
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("multiavatar: no srcset sizes")
+	}
+	limit := maxImageSize
+	if format == FormatGIF {
+		limit = maxGIFSize
+	}
+	sizes = slices.Clone(sizes)
+	slices.Sort(sizes)
+	sizes = slices.Compact(sizes)
+	for _, size := range sizes {
+		if size <= 0 || size > limit {
+			return nil, fmt.Errorf("multiavatar: %v size %d out of range 1..%d", format, size, limit)
+		}
+	}
+	cfg := newConfig(opts)
+	if err := cfg.checkOptions(ctx, "GenerateSrcSet"); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+
+	set := &SrcSet{Sources: make([]Source, 0, len(sizes))}
+	entries := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		var u string
+		if cfg.srcSetURL != nil {
+			u = cfg.srcSetURL(input, size, format)
+		} else {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			sized := *cfg
+			sized.size = size
+			var b strings.Builder
+			t := time.Now()
+			err := sized.encode(ctx, &b, input, format, append(slices.Clip(opts), WithSize(size)))
+			cfg.logRender(ctx, "GenerateSrcSet", input, format, size, t, err)
+			if err != nil {
+				return nil, err
+			}
+			u = "data:" + format.ContentType() + ";base64," + base64.StdEncoding.EncodeToString([]byte(b.String()))
+		}
+		set.Sources = append(set.Sources, Source{Size: size, URL: u})
+		entries = append(entries, u+" "+strconv.Itoa(size)+"w")
+	}
+	set.SrcSet = strings.Join(entries, ", ")
+	cfg.logBatch(ctx, "GenerateSrcSet", len(sizes), start, nil)
+	return set, nil
+}
@@ -0,0 +1,518 @@
+package multiavatar
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svgNode is a parsed SVG element.
+type svgNode struct {
+	name     string
+	attrs    map[string]string
+	children []*svgNode
+}
+
+// parseSVGTree parses an SVG document into an element tree.
+func parseSVGTree(r io.Reader) (*svgNode, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	var root *svgNode
+	var stack []*svgNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &svgNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, a := range t.Attr {
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if root == nil || root.name != "svg" {
+		return nil, errors.New("multiavatar: not an SVG document")
+	}
+	return root, nil
+}
+
+// strokeStyle holds the stroke geometry properties.
+type strokeStyle struct {
+	width      float64
+	cap, join  string
+	miterLimit float64
+}
+
+// paint is a resolved fill or stroke paint: a solid color or a gradient.
+type paint struct {
+	solid color.NRGBA
+	grad  *gradientPaint
+}
+
+// gradientPaint is a resolved linear or radial gradient.
+type gradientPaint struct {
+	radial         bool
+	x1, y1, x2, y2 float64 // linear vector
+	cx, cy, r      float64 // radial circle
+	userSpace      bool    // gradientUnits="userSpaceOnUse"
+	inverse        affine  // inverse of gradientTransform
+	stops          []gradientStop
+}
+
+type gradientStop struct {
+	offset float64
+	color  color.NRGBA
+}
+
+// at returns the color at gradient parameter t, padded outside [0, 1].
+func (g *gradientPaint) at(t float64) color.NRGBA {
+	if len(g.stops) == 0 {
+		return color.NRGBA{}
+	}
+	if t <= g.stops[0].offset {
+		return g.stops[0].color
+	}
+	for i := 1; i < len(g.stops); i++ {
+		a, b := g.stops[i-1], g.stops[i]
+		if t <= b.offset {
+			f := 0.0
+			if b.offset > a.offset {
+				f = (t - a.offset) / (b.offset - a.offset)
+			}
+			lerp := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*f + 0.5) }
+			return color.NRGBA{lerp(a.color.R, b.color.R), lerp(a.color.G, b.color.G), lerp(a.color.B, b.color.B), lerp(a.color.A, b.color.A)}
+		}
+	}
+	return g.stops[len(g.stops)-1].color
+}
+
+// param returns the gradient parameter for a point in user space, given the
+// bounding box of the painted shape.
+func (g *gradientPaint) param(p point, bbox [4]float64) float64 {
+	if !g.userSpace {
+		w, h := bbox[2]-bbox[0], bbox[3]-bbox[1]
+		if w == 0 || h == 0 {
+			return 0
+		}
+		p = point{(p.x - bbox[0]) / w, (p.y - bbox[1]) / h}
+	}
+	p = g.inverse.apply(p)
+	if g.radial {
+		if g.r <= 0 {
+			return 1
+		}
+		return math.Hypot(p.x-g.cx, p.y-g.cy) / g.r
+	}
+	dx, dy := g.x2-g.x1, g.y2-g.y1
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return 0
+	}
+	return ((p.x-g.x1)*dx + (p.y-g.y1)*dy) / l2
+}
+
+// drawer receives the shapes of an SVG document in painting order.
+// Paths are in user space; m maps user space to the document viewBox.
+type drawer interface {
+	fillPath(p path, m affine, pt *paint, evenOdd bool, opacity float64)
+	strokePath(p path, m affine, pt *paint, st strokeStyle, opacity float64)
+}
+
+// drawStyle is the inherited presentation state while walking the tree.
+type drawStyle struct {
+	fill, stroke  string
+	fillRule      string
+	strokeGeom    strokeStyle
+	opacity       float64 // accumulated group opacity
+	fillOpacity   float64
+	strokeOpacity float64
+	color         string
+	// crisp turns off anti-aliasing, for shape-rendering="crispEdges"
+	crisp bool
+}
+
+// svgWalker walks an SVG tree and emits shapes to a drawer.
+type svgWalker struct {
+	ids map[string]*svgNode
+	out drawer
+	// depth guards against <use> cycles
+	depth int
+	// ctx stops the walk between elements once it is done; err records why
+	ctx context.Context
+	err error
+}
+
+// viewBox returns the root viewBox (x, y, w, h), defaulting to the avatar canvas.
+func (n *svgNode) viewBox() [4]float64 {
+	v := parseNumberList(n.attrs["viewBox"])
+	if len(v) == 4 && v[2] > 0 && v[3] > 0 {
+		return [4]float64{v[0], v[1], v[2], v[3]}
+	}
+	w, h := parseLength(n.attrs["width"], 0), parseLength(n.attrs["height"], 0)
+	if w > 0 && h > 0 {
+		return [4]float64{0, 0, w, h}
+	}
+	return [4]float64{0, 0, 231, 231}
+}
+
+// drawSVG walks the document rooted at root, emitting shapes in viewBox coordinates.
+func drawSVG(root *svgNode, out drawer) {
+	drawSVGContext(context.Background(), root, out)
+}
+
+// drawSVGContext is drawSVG stopping early when ctx is done, with its error.
+func drawSVGContext(ctx context.Context, root *svgNode, out drawer) error {
+	w := &svgWalker{ids: make(map[string]*svgNode), out: out, ctx: ctx}
+	w.index(root)
+	st := drawStyle{
+		fill:          "black",
+		stroke:        "none",
+		fillRule:      "nonzero",
+		strokeGeom:    strokeStyle{width: 1, cap: "butt", join: "miter", miterLimit: 4},
+		opacity:       1,
+		fillOpacity:   1,
+		strokeOpacity: 1,
+		color:         "black",
+	}
+	w.children(root, identity, st)
+	return w.err
+}
+
+func (w *svgWalker) index(n *svgNode) {
+	if id := n.attrs["id"]; id != "" {
+		if _, dup := w.ids[id]; !dup {
+			w.ids[id] = n
+		}
+	}
+	for _, c := range n.children {
+		w.index(c)
+	}
+}
+
+func (w *svgWalker) children(n *svgNode, m affine, st drawStyle) {
+	for _, c := range n.children {
+		w.node(c, m, st)
+	}
+}
+
+func (w *svgWalker) node(n *svgNode, m affine, st drawStyle) {
+	if w.err != nil {
+		return
+	}
+	if w.err = w.ctx.Err(); w.err != nil {
+		return
+	}
+	props := n.style()
+	if props["display"] == "none" {
+		return
+	}
+	st = st.apply(props)
+	if t, ok := n.attrs["transform"]; ok {
+		m = m.mul(parseTransform(t))
+	}
+
+	switch n.name {
+	case "g", "a", "switch":
+		w.children(n, m, st)
+	case "svg":
+		vb := n.viewBox()
+		x, y := parseLength(n.attrs["x"], 0), parseLength(n.attrs["y"], 0)
+		width, height := parseLength(n.attrs["width"], vb[2]), parseLength(n.attrs["height"], vb[3])
+		s := math.Min(width/vb[2], height/vb[3])
+		tx := x + (width-vb[2]*s)/2 - vb[0]*s
+		ty := y + (height-vb[3]*s)/2 - vb[1]*s
+		w.children(n, m.mul(affine{s, 0, 0, s, tx, ty}), st)
+	case "use":
+		ref := strings.TrimPrefix(n.attrs["href"], "#")
+		target, ok := w.ids[ref]
+		if !ok || w.depth > 8 {
+			return
+		}
+		m = m.mul(affine{1, 0, 0, 1, parseLength(n.attrs["x"], 0), parseLength(n.attrs["y"], 0)})
+		w.depth++
+		if target.name == "symbol" {
+			w.children(target, m, st)
+		} else {
+			w.node(target, m, st)
+		}
+		w.depth--
+	case "path":
+		w.shape(parsePathData(n.attrs["d"]), m, st, true)
+	case "rect":
+		rx, hasRX := n.attrs["rx"]
+		ry, hasRY := n.attrs["ry"]
+		if !hasRX {
+			rx = ry
+		}
+		if !hasRY {
+			ry = rx
+		}
+		w.shape(rectPath(parseLength(n.attrs["x"], 0), parseLength(n.attrs["y"], 0),
+			parseLength(n.attrs["width"], 0), parseLength(n.attrs["height"], 0),
+			parseLength(rx, 0), parseLength(ry, 0)), m, st, true)
+	case "circle":
+		r := parseLength(n.attrs["r"], 0)
+		w.shape(ellipsePath(parseLength(n.attrs["cx"], 0), parseLength(n.attrs["cy"], 0), r, r), m, st, true)
+	case "ellipse":
+		w.shape(ellipsePath(parseLength(n.attrs["cx"], 0), parseLength(n.attrs["cy"], 0),
+			parseLength(n.attrs["rx"], 0), parseLength(n.attrs["ry"], 0)), m, st, true)
+	case "line":
+		var p path
+		p.moveTo(point{parseLength(n.attrs["x1"], 0), parseLength(n.attrs["y1"], 0)})
+		p.lineTo(point{parseLength(n.attrs["x2"], 0), parseLength(n.attrs["y2"], 0)})
+		w.shape(p, m, st, false)
+	case "polyline":
+		w.shape(polyPath(n.attrs["points"], false), m, st, true)
+	case "polygon":
+		w.shape(polyPath(n.attrs["points"], true), m, st, true)
+	}
+	// everything else (defs, gradients, style, metadata, ...) is not rendered directly
+}
+
+// crispDrawer is implemented by drawers that can turn off anti-aliasing.
+type crispDrawer interface {
+	setCrisp(crisp bool)
+}
+
+func (w *svgWalker) shape(p path, m affine, st drawStyle, fillable bool) {
+	if len(p) == 0 {
+		return
+	}
+	if cd, ok := w.out.(crispDrawer); ok {
+		cd.setCrisp(st.crisp)
+	}
+	if fillable {
+		if pt := w.paint(st.fill, st.color, st.fillOpacity); pt != nil {
+			w.out.fillPath(p, m, pt, st.fillRule == "evenodd", st.opacity)
+		}
+	}
+	if st.strokeGeom.width > 0 {
+		if pt := w.paint(st.stroke, st.color, st.strokeOpacity); pt != nil {
+			w.out.strokePath(p, m, pt, st.strokeGeom, st.opacity)
+		}
+	}
+}
+
+// paint resolves a fill/stroke value; nil means nothing is painted.
+func (w *svgWalker) paint(v, current string, opacity float64) *paint {
+	v = resolveVar(v)
+	if v == "" || v == "none" || opacity <= 0 {
+		return nil
+	}
+	if strings.HasPrefix(v, "url(") {
+		id := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(v, "url("), ")"))
+		id = strings.Trim(id, `'"`)
+		g := w.gradient(strings.TrimPrefix(id, "#"), opacity)
+		if g == nil {
+			return nil
+		}
+		return &paint{grad: g}
+	}
+	if strings.EqualFold(v, "currentColor") {
+		v = resolveVar(current)
+	}
+	c, ok := parseColor(v)
+	if !ok {
+		// unparseable colors paint black, as browsers treat invalid fills as the initial value
+		c = color.NRGBA{A: 255}
+	}
+	c.A = uint8(float64(c.A)*opacity + 0.5)
+	if c.A == 0 {
+		return nil
+	}
+	return &paint{solid: c}
+}
+
+// gradient resolves a gradient element by id.
+func (w *svgWalker) gradient(id string, opacity float64) *gradientPaint {
+	n, ok := w.ids[id]
+	if !ok || (n.name != "linearGradient" && n.name != "radialGradient") {
+		return nil
+	}
+	g := &gradientPaint{
+		radial:    n.name == "radialGradient",
+		userSpace: n.attrs["gradientUnits"] == "userSpaceOnUse",
+		inverse:   parseTransform(n.attrs["gradientTransform"]).invert(),
+	}
+	frac := func(key string, def float64) float64 {
+		v, ok := n.attrs[key]
+		if !ok {
+			return def
+		}
+		if strings.HasSuffix(strings.TrimSpace(v), "%") {
+			f, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "%"), 64)
+			return f / 100
+		}
+		return parseLength(v, def)
+	}
+	if g.radial {
+		g.cx, g.cy, g.r = frac("cx", 0.5), frac("cy", 0.5), frac("r", 0.5)
+	} else {
+		g.x1, g.y1, g.x2, g.y2 = frac("x1", 0), frac("y1", 0), frac("x2", 1), frac("y2", 0)
+	}
+
+	// stops may be inherited through href
+	src := n
+	for i := 0; i < 4 && len(stopNodes(src)) == 0; i++ {
+		ref, ok := w.ids[strings.TrimPrefix(src.attrs["href"], "#")]
+		if !ok {
+			break
+		}
+		src = ref
+	}
+	last := 0.0
+	for _, s := range stopNodes(src) {
+		props := s.style()
+		off := props["offset"]
+		var o float64
+		if strings.HasSuffix(off, "%") {
+			o, _ = strconv.ParseFloat(strings.TrimSuffix(off, "%"), 64)
+			o /= 100
+		} else {
+			o, _ = strconv.ParseFloat(off, 64)
+		}
+		o = math.Max(clamp(o, 0, 1), last)
+		last = o
+		c, ok := parseColor(resolveVar(firstNonEmpty(props["stop-color"], "black")))
+		if !ok {
+			c = color.NRGBA{A: 255}
+		}
+		a := opacity
+		if so, err := strconv.ParseFloat(props["stop-opacity"], 64); err == nil {
+			a *= clamp(so, 0, 1)
+		}
+		c.A = uint8(float64(c.A)*a + 0.5)
+		g.stops = append(g.stops, gradientStop{offset: o, color: c})
+	}
+	if len(g.stops) == 0 {
+		return nil
+	}
+	return g
+}
+
+func stopNodes(n *svgNode) []*svgNode {
+	var out []*svgNode
+	for _, c := range n.children {
+		if c.name == "stop" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// style returns the element's presentation attributes overlaid with its style attribute.
+func (n *svgNode) style() map[string]string {
+	props := make(map[string]string)
+	for _, k := range []string{"fill", "stroke", "stroke-width", "fill-rule", "stroke-linecap", "stroke-linejoin",
+		"stroke-miterlimit", "opacity", "fill-opacity", "stroke-opacity", "display", "visibility", "color",
+		"offset", "stop-color", "stop-opacity", "shape-rendering"} {
+		if v, ok := n.attrs[k]; ok {
+			props[k] = strings.TrimSpace(v)
+		}
+	}
+	for _, decl := range strings.Split(n.attrs["style"], ";") {
+		k, v, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		props[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "!important"))
+	}
+	return props
+}
+
+// apply overlays element properties onto the inherited style.
+func (st drawStyle) apply(props map[string]string) drawStyle {
+	num := func(k string, def float64) float64 {
+		if v, ok := props[k]; ok {
+			return parseLength(v, def)
+		}
+		return def
+	}
+	if v, ok := props["fill"]; ok {
+		st.fill = v
+	}
+	if v, ok := props["stroke"]; ok {
+		st.stroke = v
+	}
+	if v, ok := props["fill-rule"]; ok {
+		st.fillRule = v
+	}
+	if v, ok := props["color"]; ok {
+		st.color = v
+	}
+	if v, ok := props["stroke-linecap"]; ok {
+		st.strokeGeom.cap = v
+	}
+	if v, ok := props["stroke-linejoin"]; ok {
+		st.strokeGeom.join = v
+	}
+	st.strokeGeom.width = num("stroke-width", st.strokeGeom.width)
+	st.strokeGeom.miterLimit = num("stroke-miterlimit", st.strokeGeom.miterLimit)
+	st.opacity *= clamp(num("opacity", 1), 0, 1)
+	st.fillOpacity = clamp(num("fill-opacity", st.fillOpacity), 0, 1)
+	st.strokeOpacity = clamp(num("stroke-opacity", st.strokeOpacity), 0, 1)
+	if props["visibility"] == "hidden" {
+		st.opacity = 0
+	}
+	if v, ok := props["shape-rendering"]; ok {
+		st.crisp = v == "crispEdges" || v == "optimizeSpeed"
+	}
+	return st
+}
+
+// resolveVar replaces a CSS var(--name, fallback) by its fallback.
+func resolveVar(v string) string {
+	v = strings.TrimSpace(v)
+	for strings.HasPrefix(v, "var(") && strings.HasSuffix(v, ")") {
+		inner := v[4 : len(v)-1]
+		_, fallback, ok := strings.Cut(inner, ",")
+		if !ok {
+			return ""
+		}
+		v = strings.TrimSpace(fallback)
+	}
+	return v
+}
+
+// parseLength parses a number with an optional "px" unit.
+func parseLength(s string, def float64) float64 {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "px")
+	if s == "" {
+		return def
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
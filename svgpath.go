@@ -0,0 +1,477 @@
+package multiavatar
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// point is a 2D coordinate.
+type point struct{ x, y float64 }
+
+// affine is a 2D affine transform [a b c d e f] mapping
+// (x, y) to (a*x + c*y + e, b*x + d*y + f), as in SVG's matrix().
+type affine [6]float64
+
+var identity = affine{1, 0, 0, 1, 0, 0}
+
+// mul returns the transform applying n first and then m.
+func (m affine) mul(n affine) affine {
+	return affine{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m affine) apply(p point) point {
+	return point{m[0]*p.x + m[2]*p.y + m[4], m[1]*p.x + m[3]*p.y + m[5]}
+}
+
+// invert returns the inverse transform; singular transforms invert to identity.
+func (m affine) invert() affine {
+	det := m[0]*m[3] - m[1]*m[2]
+	if det == 0 {
+		return identity
+	}
+	return affine{
+		m[3] / det, -m[1] / det, -m[2] / det, m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det, (m[1]*m[4] - m[0]*m[5]) / det,
+	}
+}
+
+// scale returns the average linear scale factor of the transform.
+func (m affine) scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// parseTransform parses an SVG transform list such as
+// "translate(10 20) rotate(45 115.5 115.5) scale(2)".
+func parseTransform(s string) affine {
+	m := identity
+	for {
+		s = strings.TrimLeft(s, " \t\r\n,")
+		open := strings.IndexByte(s, '(')
+		end := strings.IndexByte(s, ')')
+		if open < 0 || end < open {
+			return m
+		}
+		name := strings.TrimSpace(s[:open])
+		args := parseNumberList(s[open+1 : end])
+		s = s[end+1:]
+
+		var t affine
+		switch {
+		case name == "matrix" && len(args) == 6:
+			t = affine{args[0], args[1], args[2], args[3], args[4], args[5]}
+		case name == "translate" && len(args) >= 1:
+			t = affine{1, 0, 0, 1, args[0], 0}
+			if len(args) > 1 {
+				t[5] = args[1]
+			}
+		case name == "scale" && len(args) >= 1:
+			sy := args[0]
+			if len(args) > 1 {
+				sy = args[1]
+			}
+			t = affine{args[0], 0, 0, sy, 0, 0}
+		case name == "rotate" && len(args) >= 1:
+			rad := args[0] * math.Pi / 180
+			sin, cos := math.Sincos(rad)
+			t = affine{cos, sin, -sin, cos, 0, 0}
+			if len(args) == 3 {
+				cx, cy := args[1], args[2]
+				t = affine{1, 0, 0, 1, cx, cy}.mul(t).mul(affine{1, 0, 0, 1, -cx, -cy})
+			}
+		case name == "skewX" && len(args) == 1:
+			t = affine{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}
+		case name == "skewY" && len(args) == 1:
+			t = affine{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			continue
+		}
+		m = m.mul(t)
+	}
+}
+
+// parseNumberList parses whitespace/comma separated numbers, ignoring junk.
+func parseNumberList(s string) []float64 {
+	sc := pathScanner{s: s}
+	var out []float64
+	for {
+		v, ok := sc.number()
+		if !ok {
+			return out
+		}
+		out = append(out, v)
+	}
+}
+
+// pathOp is one segment of a path in absolute coordinates.
+// kind is 'M' (move), 'L' (line), 'C' (cubic Bézier) or 'Z' (close).
+type pathOp struct {
+	kind byte
+	pts  [3]point
+}
+
+// path is a sequence of path segments.
+type path []pathOp
+
+func (p *path) moveTo(a point)        { *p = append(*p, pathOp{kind: 'M', pts: [3]point{a}}) }
+func (p *path) lineTo(a point)        { *p = append(*p, pathOp{kind: 'L', pts: [3]point{a}}) }
+func (p *path) cubicTo(a, b, c point) { *p = append(*p, pathOp{kind: 'C', pts: [3]point{a, b, c}}) }
+func (p *path) close()                { *p = append(*p, pathOp{kind: 'Z'}) }
+func (p *path) quadTo(from, c, to point) {
+	p.cubicTo(
+		point{from.x + 2.0/3*(c.x-from.x), from.y + 2.0/3*(c.y-from.y)},
+		point{to.x + 2.0/3*(c.x-to.x), to.y + 2.0/3*(c.y-to.y)},
+		to,
+	)
+}
+
+// transform returns a copy of the path mapped through m.
+func (p path) transform(m affine) path {
+	out := make(path, len(p))
+	for i, op := range p {
+		out[i].kind = op.kind
+		for j := range op.pts {
+			out[i].pts[j] = m.apply(op.pts[j])
+		}
+	}
+	return out
+}
+
+// pathScanner tokenizes SVG path data.
+type pathScanner struct {
+	s string
+	i int
+}
+
+func (sc *pathScanner) skipSeparators() {
+	for sc.i < len(sc.s) {
+		switch sc.s[sc.i] {
+		case ' ', '\t', '\r', '\n', ',':
+			sc.i++
+		default:
+			return
+		}
+	}
+}
+
+// command returns the next command letter, if the next token is one.
+func (sc *pathScanner) command() (byte, bool) {
+	sc.skipSeparators()
+	if sc.i < len(sc.s) {
+		c := sc.s[sc.i]
+		if (c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') && c != 'e' && c != 'E' {
+			sc.i++
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// number reads the next number, accepting compact forms like "-.5.5" and "1e-3".
+func (sc *pathScanner) number() (float64, bool) {
+	sc.skipSeparators()
+	start := sc.i
+	i := sc.i
+	if i < len(sc.s) && (sc.s[i] == '+' || sc.s[i] == '-') {
+		i++
+	}
+	digits, dot := 0, false
+	for i < len(sc.s) {
+		c := sc.s[i]
+		if c >= '0' && c <= '9' {
+			digits++
+		} else if c == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+		i++
+	}
+	if digits == 0 {
+		return 0, false
+	}
+	if i < len(sc.s) && (sc.s[i] == 'e' || sc.s[i] == 'E') {
+		j := i + 1
+		if j < len(sc.s) && (sc.s[j] == '+' || sc.s[j] == '-') {
+			j++
+		}
+		if j < len(sc.s) && sc.s[j] >= '0' && sc.s[j] <= '9' {
+			for j < len(sc.s) && sc.s[j] >= '0' && sc.s[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	v, err := strconv.ParseFloat(sc.s[start:i], 64)
+	if err != nil {
+		return 0, false
+	}
+	sc.i = i
+	return v, true
+}
+
+// flag reads an arc flag, which may be written without separators ("011").
+func (sc *pathScanner) flag() (bool, bool) {
+	sc.skipSeparators()
+	if sc.i < len(sc.s) && (sc.s[sc.i] == '0' || sc.s[sc.i] == '1') {
+		sc.i++
+		return sc.s[sc.i-1] == '1', true
+	}
+	return false, false
+}
+
+// parsePathData parses the d attribute of a <path> into absolute segments.
+// Parsing stops at the first malformed command, keeping what was read (as browsers do).
+func parsePathData(d string) path {
+	var (
+		p            path
+		sc           = pathScanner{s: d}
+		cur, start   point
+		lastCtrl     point
+		lastCmd, cmd byte
+		haveCmd, ok  bool
+		nums         [7]float64
+	)
+	argCount := map[byte]int{'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2, 'A': 7, 'Z': 0}
+
+	for {
+		if c, isCmd := sc.command(); isCmd {
+			cmd, haveCmd = c, true
+		} else if !haveCmd {
+			return p
+		}
+		upper := cmd &^ 0x20
+		n, known := argCount[upper]
+		if !known {
+			return p
+		}
+		rel := cmd != upper
+
+		if upper == 'Z' {
+			p.close()
+			cur = start
+			lastCmd = 'Z'
+			// Z takes no arguments, so a command letter must follow
+			haveCmd = false
+			continue
+		}
+
+		for k := 0; k < n; k++ {
+			if upper == 'A' && (k == 3 || k == 4) {
+				var f bool
+				f, ok = sc.flag()
+				nums[k] = 0
+				if f {
+					nums[k] = 1
+				}
+			} else {
+				nums[k], ok = sc.number()
+			}
+			if !ok {
+				return p
+			}
+		}
+
+		abs := func(x, y float64) point {
+			if rel {
+				return point{cur.x + x, cur.y + y}
+			}
+			return point{x, y}
+		}
+
+		switch upper {
+		case 'M':
+			cur = abs(nums[0], nums[1])
+			start = cur
+			p.moveTo(cur)
+			// subsequent pairs are implicit line-tos
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L':
+			cur = abs(nums[0], nums[1])
+			p.lineTo(cur)
+		case 'H':
+			if rel {
+				cur.x += nums[0]
+			} else {
+				cur.x = nums[0]
+			}
+			p.lineTo(cur)
+		case 'V':
+			if rel {
+				cur.y += nums[0]
+			} else {
+				cur.y = nums[0]
+			}
+			p.lineTo(cur)
+		case 'C':
+			c1, c2, to := abs(nums[0], nums[1]), abs(nums[2], nums[3]), abs(nums[4], nums[5])
+			p.cubicTo(c1, c2, to)
+			lastCtrl, cur = c2, to
+		case 'S':
+			c1 := cur
+			if lastCmd == 'C' || lastCmd == 'S' {
+				c1 = point{2*cur.x - lastCtrl.x, 2*cur.y - lastCtrl.y}
+			}
+			c2, to := abs(nums[0], nums[1]), abs(nums[2], nums[3])
+			p.cubicTo(c1, c2, to)
+			lastCtrl, cur = c2, to
+		case 'Q':
+			c, to := abs(nums[0], nums[1]), abs(nums[2], nums[3])
+			p.quadTo(cur, c, to)
+			lastCtrl, cur = c, to
+		case 'T':
+			c := cur
+			if lastCmd == 'Q' || lastCmd == 'T' {
+				c = point{2*cur.x - lastCtrl.x, 2*cur.y - lastCtrl.y}
+			}
+			to := abs(nums[0], nums[1])
+			p.quadTo(cur, c, to)
+			lastCtrl, cur = c, to
+		case 'A':
+			to := abs(nums[5], nums[6])
+			arcTo(&p, cur, nums[0], nums[1], nums[2], nums[3] != 0, nums[4] != 0, to)
+			cur = to
+		}
+		lastCmd = upper
+	}
+}
+
+// arcTo appends an SVG elliptical arc as cubic Bézier segments, following the
+// endpoint-to-center conversion of the SVG specification (appendix F.6).
+func arcTo(p *path, from point, rx, ry, angle float64, large, sweep bool, to point) {
+	if from == to {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.lineTo(to)
+		return
+	}
+	sinPhi, cosPhi := math.Sincos(angle * math.Pi / 180)
+	dx, dy := (from.x-to.x)/2, (from.y-to.y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	// scale up radii that are too small
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		s := math.Sqrt(l)
+		rx, ry = rx*s, ry*s
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := 0.0
+	if den != 0 && num > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if large == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (from.x+to.x)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (from.y+to.y)/2
+
+	vecAngle := func(ux, uy, vx, vy float64) float64 {
+		a := math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+		return a
+	}
+	theta1 := vecAngle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := vecAngle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	segs := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	step := delta / float64(segs)
+	k := 4.0 / 3 * math.Tan(step/4)
+	ellipse := func(t float64) (point, point) {
+		sinT, cosT := math.Sincos(t)
+		pt := point{cx + rx*cosT*cosPhi - ry*sinT*sinPhi, cy + rx*cosT*sinPhi + ry*sinT*cosPhi}
+		deriv := point{-rx*sinT*cosPhi - ry*cosT*sinPhi, -rx*sinT*sinPhi + ry*cosT*cosPhi}
+		return pt, deriv
+	}
+	t := theta1
+	p0, d0 := ellipse(t)
+	for i := 0; i < segs; i++ {
+		p3, d3 := ellipse(t + step)
+		if i == segs-1 {
+			p3 = to
+		}
+		p.cubicTo(point{p0.x + k*d0.x, p0.y + k*d0.y}, point{p3.x - k*d3.x, p3.y - k*d3.y}, p3)
+		t += step
+		p0, d0 = p3, d3
+	}
+}
+
+// ellipsePath returns a closed path for an ellipse.
+func ellipsePath(cx, cy, rx, ry float64) path {
+	var p path
+	if rx <= 0 || ry <= 0 {
+		return p
+	}
+	p.moveTo(point{cx + rx, cy})
+	arcTo(&p, point{cx + rx, cy}, rx, ry, 0, false, true, point{cx - rx, cy})
+	arcTo(&p, point{cx - rx, cy}, rx, ry, 0, false, true, point{cx + rx, cy})
+	p.close()
+	return p
+}
+
+// rectPath returns a closed path for a (possibly rounded) rectangle.
+func rectPath(x, y, w, h, rx, ry float64) path {
+	var p path
+	if w <= 0 || h <= 0 {
+		return p
+	}
+	rx, ry = math.Min(rx, w/2), math.Min(ry, h/2)
+	if rx <= 0 || ry <= 0 {
+		p.moveTo(point{x, y})
+		p.lineTo(point{x + w, y})
+		p.lineTo(point{x + w, y + h})
+		p.lineTo(point{x, y + h})
+		p.close()
+		return p
+	}
+	p.moveTo(point{x + rx, y})
+	p.lineTo(point{x + w - rx, y})
+	arcTo(&p, point{x + w - rx, y}, rx, ry, 0, false, true, point{x + w, y + ry})
+	p.lineTo(point{x + w, y + h - ry})
+	arcTo(&p, point{x + w, y + h - ry}, rx, ry, 0, false, true, point{x + w - rx, y + h})
+	p.lineTo(point{x + rx, y + h})
+	arcTo(&p, point{x + rx, y + h}, rx, ry, 0, false, true, point{x, y + h - ry})
+	p.lineTo(point{x, y + ry})
+	arcTo(&p, point{x, y + ry}, rx, ry, 0, false, true, point{x + rx, y})
+	p.close()
+	return p
+}
+
+// polyPath returns a path through the given coordinates ("x1,y1 x2,y2 ...").
+func polyPath(points string, closed bool) path {
+	nums := parseNumberList(points)
+	var p path
+	for i := 0; i+1 < len(nums); i += 2 {
+		pt := point{nums[i], nums[i+1]}
+		if i == 0 {
+			p.moveTo(pt)
+		} else {
+			p.lineTo(pt)
+		}
+	}
+	if closed && len(p) > 0 {
+		p.close()
+	}
+	return p
+}
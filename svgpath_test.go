@@ -0,0 +1,184 @@
+package multiavatar
+
+import (
+	"math"
+	"testing"
+)
+
+// near reports whether a and b are within 1e-6 of each other.
+func near(a, b point) bool {
+	return math.Abs(a.x-b.x) < 1e-6 && math.Abs(a.y-b.y) < 1e-6
+}
+
+// endpoints returns the kind and end point of every segment of p.
+func endpoints(p path) (string, []point) {
+	kinds := make([]byte, len(p))
+	pts := make([]point, 0, len(p))
+	for i, op := range p {
+		kinds[i] = op.kind
+		switch op.kind {
+		case 'C':
+			pts = append(pts, op.pts[2])
+		case 'Z':
+		default:
+			pts = append(pts, op.pts[0])
+		}
+	}
+	return string(kinds), pts
+}
+
+func TestParsePathData(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     string
+		kinds string
+		pts   []point
+	}{
+		{"absolute", "M10 20 L30 40 H50 V60 Z", "MLLLZ",
+			[]point{{10, 20}, {30, 40}, {50, 40}, {50, 60}}},
+		{"relative", "m10,20 l20,20 h20 v20 z", "MLLLZ",
+			[]point{{10, 20}, {30, 40}, {50, 40}, {50, 60}}},
+		{"relative after close", "M10 10 h10 z m5 5 h10", "MLZML",
+			[]point{{10, 10}, {20, 10}, {15, 15}, {25, 15}}},
+		{"implicit line-tos after M", "M0 0 10 0 10 10", "MLL",
+			[]point{{0, 0}, {10, 0}, {10, 10}}},
+		{"implicit line-tos after m", "m1 1 10 0 0 10", "MLL",
+			[]point{{1, 1}, {11, 1}, {11, 11}}},
+		{"implicit repeats", "M0 0 L1 1 2 2 h1 1 v1 1", "MLLLLLL",
+			[]point{{0, 0}, {1, 1}, {2, 2}, {3, 2}, {4, 2}, {4, 3}, {4, 4}}},
+		{"compact numbers", "M-.5.5L1e1-1.5e0", "ML",
+			[]point{{-0.5, 0.5}, {10, -1.5}}},
+		{"separators", "M 1,2\n\tL3 , 4", "ML",
+			[]point{{1, 2}, {3, 4}}},
+		{"cubic and smooth", "M0 0 C0 10 10 10 10 0 S20 -10 20 0 s10 10 10 0", "MCCC",
+			[]point{{0, 0}, {10, 0}, {20, 0}, {30, 0}}},
+		{"quadratic and smooth", "M0 0 Q5 10 10 0 T20 0 t10 0", "MCCC",
+			[]point{{0, 0}, {10, 0}, {20, 0}, {30, 0}}},
+		{"malformed keeps the prefix", "M0 0 L10 10 L20", "ML",
+			[]point{{0, 0}, {10, 10}}},
+		{"unknown command", "M0 0 L10 10 X5 5 L20 20", "ML",
+			[]point{{0, 0}, {10, 10}}},
+		{"no command", "10 10", "", []point{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds, pts := endpoints(parsePathData(tt.d))
+			if kinds != tt.kinds {
+				t.Fatalf("parsePathData(%q) kinds = %q, want %q", tt.d, kinds, tt.kinds)
+			}
+			for i := range tt.pts {
+				if !near(pts[i], tt.pts[i]) {
+					t.Errorf("parsePathData(%q) point %d = %v, want %v", tt.d, i, pts[i], tt.pts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParsePathDataSmoothControlPoints(t *testing.T) {
+	p := parsePathData("M0 0 C0 10 10 10 10 0 S20 -10 20 0")
+	// S reflects the second control point of the previous curve
+	if got, want := p[2].pts[0], (point{10, -10}); !near(got, want) {
+		t.Errorf("S control point = %v, want %v", got, want)
+	}
+	p = parsePathData("M0 0 L10 0 S20 10 20 0")
+	// without a preceding curve the first control point is the current point
+	if got, want := p[2].pts[0], (point{10, 0}); !near(got, want) {
+		t.Errorf("S control point after L = %v, want %v", got, want)
+	}
+}
+
+func TestParsePathDataArcs(t *testing.T) {
+	tests := []struct {
+		name string
+		d    string
+		end  point
+		// mid is a point the arc passes through
+		mid point
+	}{
+		{"upper semicircle", "M0 0 A10 10 0 0 1 20 0", point{20, 0}, point{10, -10}},
+		{"lower semicircle", "M0 0 A10 10 0 0 0 20 0", point{20, 0}, point{10, 10}},
+		{"relative", "M5 5 a10 10 0 0 1 20 0", point{25, 5}, point{15, -5}},
+		{"compact flags", "M0 0a10 10 0 0120 0", point{20, 0}, point{10, -10}},
+		{"radii scaled up", "M0 0 A1 1 0 0 1 20 0", point{20, 0}, point{10, -10}},
+		{"large quarter", "M10 0 A10 10 0 1 1 0 10", point{0, 10}, point{20, 10}},
+		{"rotated ellipse", "M0 0 A20 10 90 0 1 0 40", point{0, 40}, point{10, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parsePathData(tt.d)
+			kinds, pts := endpoints(p)
+			if kinds[0] != 'M' || len(kinds) < 3 {
+				t.Fatalf("parsePathData(%q) kinds = %q, want M and cubic segments", tt.d, kinds)
+			}
+			for _, k := range kinds[1:] {
+				if k != 'C' {
+					t.Fatalf("parsePathData(%q) kinds = %q, want only cubic segments after M", tt.d, kinds)
+				}
+			}
+			if end := pts[len(pts)-1]; !near(end, tt.end) {
+				t.Errorf("arc ends at %v, want %v", end, tt.end)
+			}
+			found := false
+			for _, l := range flatten(p, 0.01) {
+				for _, q := range l.pts {
+					if math.Hypot(q.x-tt.mid.x, q.y-tt.mid.y) < 0.05 {
+						found = true
+					}
+				}
+			}
+			if !found {
+				t.Errorf("arc does not pass through %v", tt.mid)
+			}
+		})
+	}
+}
+
+func TestParsePathDataDegenerateArcs(t *testing.T) {
+	// zero radii draw a straight line
+	if kinds, pts := endpoints(parsePathData("M0 0 A0 10 0 0 1 20 0")); kinds != "ML" || !near(pts[1], point{20, 0}) {
+		t.Errorf("arc with a zero radius = %q %v, want a line to (20, 0)", kinds, pts)
+	}
+	// an arc to the current point is omitted
+	if kinds, _ := endpoints(parsePathData("M5 5 A10 10 0 0 1 5 5")); kinds != "M" {
+		t.Errorf("arc to the current point = %q, want nothing after M", kinds)
+	}
+}
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		s       string
+		in, out point
+	}{
+		{"", point{3, 4}, point{3, 4}},
+		{"translate(10 20)", point{1, 1}, point{11, 21}},
+		{"translate(10)", point{1, 1}, point{11, 1}},
+		{"scale(2)", point{3, 4}, point{6, 8}},
+		{"scale(2, -1)", point{3, 4}, point{6, -4}},
+		{"rotate(90)", point{1, 0}, point{0, 1}},
+		{"rotate(180 10 10)", point{0, 0}, point{20, 20}},
+		{"matrix(1 0 0 1 5 6)", point{1, 1}, point{6, 7}},
+		{"skewX(45)", point{0, 10}, point{10, 10}},
+		{"skewY(45)", point{10, 0}, point{10, 10}},
+		// applied right to left: scale first, then translate
+		{"translate(10,0) scale(2)", point{1, 1}, point{12, 2}},
+		{"scale(2) translate(10,0)", point{1, 1}, point{22, 2}},
+		{"bogus(1) translate(1 1)", point{0, 0}, point{1, 1}},
+	}
+	for _, tt := range tests {
+		if got := parseTransform(tt.s).apply(tt.in); !near(got, tt.out) {
+			t.Errorf("parseTransform(%q) maps %v to %v, want %v", tt.s, tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestAffineInvert(t *testing.T) {
+	m := parseTransform("translate(5 7) rotate(30) scale(2 3)")
+	q := point{11, -4}
+	if got := m.invert().apply(m.apply(q)); !near(got, q) {
+		t.Errorf("invert(m)(m(%v)) = %v", q, got)
+	}
+	if got := (affine{0, 0, 0, 0, 1, 1}).invert(); got != identity {
+		t.Errorf("singular transform inverts to %v, want identity", got)
+	}
+}
@@ -0,0 +1,56 @@
+package multiavatar
+
+import (
+	"strings"
+	"sync"
+)
+
+// partTemplate is a part SVG together with its color placeholders,
+// tokenized once so rendering needs no pattern matching.
+type partTemplate struct {
+	svg          string
+	placeholders []string
+}
+
+var (
+	templatesOnce sync.Once
+	templates     [][]partTemplate
+)
+
+// partTemplates returns the tokenized templates of the active art, indexed
+// by [version][partIndex].
+func partTemplates() [][]partTemplate {
+	if art := loadedArt.Load(); art != nil {
+		return art.templates
+	}
+	return builtinTemplates()
+}
+
+// builtinTemplates returns the tokenized embedded templates.
+func builtinTemplates() [][]partTemplate {
+	templatesOnce.Do(func() {
+		// the art is one line per part, six parts per version
+		lines := strings.Split(strings.TrimSpace(loadPartsData()), "\n")
+		templates = make([][]partTemplate, len(lines)/len(partNames))
+		for i, svg := range lines {
+			v := i / len(partNames)
+			if templates[v] == nil {
+				templates[v] = make([]partTemplate, len(partNames))
+			}
+			templates[v][i%len(partNames)] = partTemplate{svg: svg, placeholders: findPlaceholders(svg)}
+		}
+	})
+	return templates
+}
+
+// stripNonDigits removes every byte that is not an ASCII digit,
+// like the JS original's replace(/\D/g, "").
+func stripNonDigits(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			b = append(b, s[i])
+		}
+	}
+	return string(b)
+}
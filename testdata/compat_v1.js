@@ -0,0 +1,18 @@
+// Renders the golden corpus compat_test.go replays, compat_v1.ndjson, with
+// the reference JavaScript library:
+//
+//	npm install @multiavatar/multiavatar@1
+//	node testdata/compat_v1.js > testdata/compat_v1.ndjson
+const multiavatar = require('@multiavatar/multiavatar')
+
+const inputs = [
+  'alice', 'bob', 'Binx Bond', 'Starcrasher', 'bob@example.com',
+  '0', '42', '123456789012', 'ffffffffffff', 'x',
+  'A very long input that goes on and on past any reasonable name length',
+  'josé', '李小龙', 'さくら', '👩‍👩‍👧‍👦', ' spaces ', 'tab\there', '<script>',
+]
+for (const input of inputs) {
+  for (const sansEnv of [false, true]) {
+    console.log(JSON.stringify({input, sansEnv, svg: multiavatar(input, sansEnv)}))
+  }
+}
@@ -0,0 +1,213 @@
+package multiavatar
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// Texture is a surface texture layered over the avatar with WithTexture.
+type Texture int
+
+const (
+	// TextureGrain adds fine film grain.
+	TextureGrain Texture = iota + 1
+	// TexturePaper adds the soft, blotchy tone of paper.
+	TexturePaper
+	// TextureHalftone adds a grid of halftone dots.
+	TextureHalftone
+)
+
+// textureNames are the Texture names, indexed by value.
+var textureNames = []string{"", "grain", "paper", "halftone"}
+
+// String returns the texture name, e.g. "grain".
+func (t Texture) String() string {
+	if t > 0 && int(t) < len(textureNames) {
+		return textureNames[t]
+	}
+	return fmt.Sprintf("Texture(%d)", int(t))
+}
+
+// ParseTexture returns the texture named s, e.g. "paper".
+func ParseTexture(s string) (Texture, bool) {
+	for t := TextureGrain; int(t) < len(textureNames); t++ {
+		if strings.EqualFold(s, textureNames[t]) {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// texture is a texture with its strength.
+type texture struct {
+	kind    Texture
+	opacity float64
+}
+
+// halftoneStep and halftoneRadius are the spacing and size of halftone
+// dots, in avatar units.
+const halftoneStep, halftoneRadius = 5.0, 1.2
+
+// WithTexture layers a subtle texture over the avatar, with opacity from
+// 0 (none) to 1, for a less flat look; around 0.2 to 0.4 suits most
+// designs. The texture is an SVG filter, so the SVG stays small; the
+// built-in rasterizer draws a close approximation of it. Unknown textures
+// and opacities outside (0, 1] are reported as errors by the
+// error-returning APIs.
+func WithTexture(t Texture, opacity float64) Option {
+	return func(c *config) {
+		if t < TextureGrain || int(t) >= len(textureNames) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown texture %d", int(t)))
+			return
+		}
+		if !(opacity > 0 && opacity <= 1) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid texture opacity %v", opacity))
+			return
+		}
+		c.texture = &texture{kind: t, opacity: opacity}
+	}
+}
+
+// writeTextureFilter writes the filter drawing the texture over its element and
+// returns its id.
+func (cfg *config) writeTextureFilter(b svgWriter) string {
+	t := cfg.texture
+	o := formatFloat(t.opacity)
+	id := cfg.defID("texture", t.kind.String()+" "+o)
+	b.WriteString(`<defs><filter id="` + id + `">`)
+	switch t.kind {
+	case TextureGrain, TexturePaper:
+		noise := `baseFrequency="0.9" numOctaves="2"`
+		if t.kind == TexturePaper {
+			noise = `baseFrequency="0.04" numOctaves="4"`
+		}
+		// gray noise, blended in with soft-light so mid-gray leaves the
+		// colors unchanged
+		b.WriteString(`<feTurbulence type="fractalNoise" ` + noise + ` seed="7"/>` +
+			`<feColorMatrix values="1 0 0 0 0 1 0 0 0 0 1 0 0 0 0 0 0 0 0 ` + o + `"/>` +
+			`<feBlend in2="SourceGraphic" mode="soft-light"/>`)
+	case TextureHalftone:
+		s, r := formatFloat(halftoneStep), formatFloat(halftoneRadius)
+		b.WriteString(`<feImage href="data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' width='` + s + `' height='` + s +
+			`'%3E%3Ccircle cx='` + formatFloat(halftoneStep/2) + `' cy='` + formatFloat(halftoneStep/2) + `' r='` + r +
+			`'/%3E%3C/svg%3E" x="0" y="0" width="` + s + `" height="` + s + `"/>` +
+			`<feTile/><feColorMatrix values="0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 ` + o + ` 0"/>` +
+			`<feComposite in2="SourceGraphic"/>`)
+	}
+	// keep the avatar's own outline
+	b.WriteString(`<feComposite in2="SourceGraphic" operator="in"/></filter></defs>`)
+	return id
+}
+
+// apply approximates the texture filter on the pixels of r, which show the
+// avatar viewBox vb.
+func (t *texture) apply(img *image.RGBA, r image.Rectangle, vb [4]float64) {
+	s := math.Min(float64(r.Dx())/vb[2], float64(r.Dy())/vb[3])
+	ox := float64(r.Min.X) + (float64(r.Dx())-vb[2]*s)/2 - vb[0]*s
+	oy := float64(r.Min.Y) + (float64(r.Dy())-vb[3]*s)/2 - vb[1]*s
+	r = r.Intersect(img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			i := img.PixOffset(px, py)
+			pix := img.Pix[i : i+4 : i+4]
+			if pix[3] == 0 {
+				continue
+			}
+			// the pixel center in avatar units
+			x, y := (float64(px)+0.5-ox)/s, (float64(py)+0.5-oy)/s
+			a := float64(pix[3]) / 255
+			for c := range 3 {
+				v := float64(pix[c]) / 255 / a
+				switch t.kind {
+				case TextureGrain:
+					v = softLight(v, valueNoise(x*0.9, y*0.9, 7), t.opacity)
+				case TexturePaper:
+					v = softLight(v, fractalNoise(x*0.04, y*0.04, 4), t.opacity)
+				case TextureHalftone:
+					v *= 1 - t.opacity*halftoneCoverage(x, y, s)
+				}
+				pix[c] = uint8(clamp(v, 0, 1)*a*255 + 0.5)
+			}
+		}
+	}
+}
+
+// softLight blends the gray source s over the backdrop b with the
+// soft-light mode at the given opacity.
+func softLight(b, s, opacity float64) float64 {
+	var mixed float64
+	if s <= 0.5 {
+		mixed = b - (1-2*s)*b*(1-b)
+	} else {
+		d := math.Sqrt(b)
+		if b <= 0.25 {
+			d = ((16*b-12)*b + 4) * b
+		}
+		mixed = b + (2*s-1)*(d-b)
+	}
+	return b + (mixed-b)*opacity
+}
+
+// halftoneCoverage returns how much of the pixel at avatar point (x, y),
+// scale pixels per unit, a halftone dot covers.
+func halftoneCoverage(x, y, scale float64) float64 {
+	dx := math.Mod(x, halftoneStep) - halftoneStep/2
+	dy := math.Mod(y, halftoneStep) - halftoneStep/2
+	if dx < -halftoneStep/2 {
+		dx += halftoneStep
+	}
+	if dy < -halftoneStep/2 {
+		dy += halftoneStep
+	}
+	return clamp((halftoneRadius-math.Hypot(dx, dy))*scale+0.5, 0, 1)
+}
+
+// fractalNoise sums octaves of valueNoise, halving the amplitude and
+// doubling the frequency each time, normalized to 0..1.
+func fractalNoise(x, y float64, octaves int) float64 {
+	sum, amp, total := 0.0, 1.0, 0.0
+	for o := range octaves {
+		sum += valueNoise(x, y, uint32(o)) * amp
+		total += amp
+		x, y, amp = x*2, y*2, amp/2
+	}
+	return sum / total
+}
+
+// valueNoise returns smooth noise in 0..1 interpolated between random
+// values at the integer lattice points.
+func valueNoise(x, y float64, seed uint32) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	fx, fy = fx*fx*(3-2*fx), fy*fy*(3-2*fy)
+	ix, iy := int32(x0), int32(y0)
+	v00, v10 := latticeValue(ix, iy, seed), latticeValue(ix+1, iy, seed)
+	v01, v11 := latticeValue(ix, iy+1, seed), latticeValue(ix+1, iy+1, seed)
+	top := v00 + (v10-v00)*fx
+	bottom := v01 + (v11-v01)*fx
+	return top + (bottom-top)*fy
+}
+
+// latticeValue hashes a lattice point to a value in 0..1.
+func latticeValue(x, y int32, seed uint32) float64 {
+	h := uint32(x)*0x27d4eb2d ^ uint32(y)*0x165667b1 ^ seed*0x9e3779b9
+	h ^= h >> 15
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	return float64(h&0xffffff) / 0xffffff
+}
+
+// drawTexture draws the texture over the avatar filling r of img, when the
+// built-in rasterizer, which skips SVG filters, rendered it.
+func (cfg *config) drawTexture(img *image.RGBA, r image.Rectangle) {
+	if cfg.texture == nil || cfg.rasterizer != nil {
+		return
+	}
+	v := parseNumberList(cfg.viewBoxAttr())
+	if len(v) != 4 || v[2] <= 0 || v[3] <= 0 {
+		return
+	}
+	cfg.texture.apply(img, r, [4]float64{v[0], v[1], v[2], v[3]})
+}
@@ -0,0 +1,159 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ThemePack is an alternative art set. It plugs into the same deterministic
+// selection as the built-in art: every part has 16 versions ("00".."15"),
+// each drawn in themes "A", "B" and "C", and the hash of the input picks
+// one version and theme per part.
+type ThemePack interface {
+	// Template returns the SVG markup of version 0..15 of a part, drawn on
+	// the 231×231 avatar canvas. Every "#...;" token, e.g. the "#skin;" in
+	// style="fill:#skin;", is a color placeholder, filled in order with the
+	// theme's colors. An empty string leaves the part out. Templates are read
+	// once, when the pack is registered.
+	Template(part string, version int) string
+	// Colors returns the colors of a part version in theme "A", "B" or "C",
+	// one per placeholder of its template.
+	Colors(part string, version int, theme string) []string
+}
+
+// themePack is a registered pack with its templates tokenized.
+type themePack struct {
+	ThemePack
+	// templates is indexed by [version][partIndex], like partTemplates
+	templates [][]partTemplate
+	// compose, if set, draws the whole avatar for a built-in style that is
+	// not made of parts; ThemePack and templates are then unused.
+	compose func(cfg *config, b svgWriter, selected []selectedPart)
+	// pieces reports whether compose draws a piece per part, skipping
+	// disabled parts, so parts can be drawn alone; see WithPartStyle
+	pieces bool
+}
+
+var (
+	packsMu sync.RWMutex
+	packs   = make(map[string]*themePack)
+)
+
+// RegisterThemePack makes an art set available under name, to be selected
+// with WithStyle. Register packs from an init function or before the first
+// Generate call that uses them. It panics if tp is nil, name is empty or a
+// pack is already registered under name.
+func RegisterThemePack(name string, tp ThemePack) {
+	if tp == nil {
+		panic("multiavatar: RegisterThemePack pack is nil")
+	}
+	if name == "" {
+		panic("multiavatar: RegisterThemePack name is empty")
+	}
+	pack := &themePack{ThemePack: tp, templates: make([][]partTemplate, 16)}
+	for v := range pack.templates {
+		pack.templates[v] = make([]partTemplate, len(partNames))
+		for _, name := range partNames {
+			svg := tp.Template(name, v)
+			pack.templates[v][partIndex[name]] = partTemplate{svg: svg, placeholders: findPlaceholders(svg)}
+		}
+	}
+
+	packsMu.Lock()
+	defer packsMu.Unlock()
+	if _, dup := packs[name]; dup {
+		panic("multiavatar: RegisterThemePack called twice for " + name)
+	}
+	packs[name] = pack
+}
+
+// registerComposer registers a built-in style drawn by compose. Part
+// selection and colors follow the built-in art. pieces reports whether
+// compose draws a piece per part.
+func registerComposer(name string, pieces bool, compose func(cfg *config, b svgWriter, selected []selectedPart)) {
+	packsMu.Lock()
+	defer packsMu.Unlock()
+	packs[name] = &themePack{compose: compose, pieces: pieces}
+}
+
+// lookupPack returns the style registered under name.
+func lookupPack(name string) (*themePack, bool) {
+	packsMu.RLock()
+	defer packsMu.RUnlock()
+	pack, ok := packs[name]
+	return pack, ok
+}
+
+// WithStyle selects a built-in style such as StyleIdenticon, or a theme
+// pack registered under name. The empty string selects the built-in art. Unknown names fall back to the built-in art and
+// are reported as errors by the error-returning APIs.
+func WithStyle(name string) Option {
+	return func(c *config) {
+		if name == "" {
+			c.pack = nil
+			return
+		}
+		pack, ok := lookupPack(name)
+		if !ok {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown style %q", name))
+			return
+		}
+		c.pack = pack
+	}
+}
+
+// partIndex is the position of each part in a template row.
+var partIndex = map[string]int{"env": 0, "clo": 1, "head": 2, "mouth": 3, "eyes": 4, "top": 5}
+
+// partColors returns the theme colors of a part version in the active art set.
+func (cfg *config) partColors(name, version, theme string) []string {
+	if x := extraPartByName(name); x != nil {
+		ev, _ := x.version(version)
+		return ev.themes[theme]
+	}
+	pack := cfg.packFor(name)
+	if pack == nil || pack.ThemePack == nil {
+		return themeTable()[version][theme][name]
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil || v < 0 || v >= len(pack.templates) {
+		return nil
+	}
+	colors := pack.Colors(name, v, theme)
+	safe := make([]string, len(colors))
+	for i, c := range colors {
+		safe[i] = safeColor(c)
+	}
+	return safe
+}
+
+// template returns the tokenized template of a selected part, or false if
+// the version or theme does not exist in the active art set.
+func (cfg *config) template(p selectedPart) (partTemplate, bool) {
+	if p.theme != "A" && p.theme != "B" && p.theme != "C" {
+		return partTemplate{}, false
+	}
+	if x := extraPartByName(p.name); x != nil {
+		ev, ok := x.version(p.version)
+		return ev.tmpl, ok
+	}
+	v, err := strconv.Atoi(p.version)
+	if err != nil || v < 0 {
+		return partTemplate{}, false
+	}
+	pack := cfg.packFor(p.name)
+	if tmpl, ok := headShape(p.version); ok && p.name == "head" && pack == nil {
+		return tmpl, true
+	}
+	table := partTemplates()
+	if pack != nil {
+		table = pack.templates
+	} else if _, ok := themeTable()[p.version][p.theme][p.name]; !ok {
+		return partTemplate{}, false
+	}
+	if v >= len(table) {
+		return partTemplate{}, false
+	}
+	return table[v][partIndex[p.name]], true
+}
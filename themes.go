@@ -1,422 +1,353 @@
 package multiavatar
 
-// themes holds the color variations for different avatar parts.
-// It's a direct port from the original JavaScript library.
-var themes = map[string]map[string]map[string][]string{
-	"00": {
-		"A": {
-			"env":   {"#ff2f2b"},
-			"clo":   {"#fff", "#000"},
-			"head":  {"#fff"},
-			"mouth": {"#fff", "#000", "#000"},
-			"eyes":  {"#000", "none", "#00FFFF"},
-			"top":   {"#fff", "#fff"},
-		},
-		"B": {
-			"env":   {"#ff1ec1"},
-			"clo":   {"#000", "#fff"},
-			"head":  {"#ffc1c1"},
-			"mouth": {"#fff", "#000", "#000"},
-			"eyes":  {"#FF2D00", "#fff", "none"},
-			"top":   {"#a21d00", "#fff"},
-		},
-		"C": {
-			"env":   {"#0079b1"},
-			"clo":   {"#0e00b1", "#d1fffe"},
-			"head":  {"#f5aa77"},
-			"mouth": {"#fff", "#000", "#000"},
-			"eyes":  {"#0c00de", "#fff", "none"},
-			"top":   {"#acfffd", "#acfffd"},
-		},
-	},
-	"01": {
-		"A": {
-			"env":   {"#a50000"},
-			"clo":   {"#f06", "#8e0039"},
-			"head":  {"#85492C"},
-			"mouth": {"#000"},
-			"eyes":  {"#000", "#ff9809"},
-			"top":   {"#ff9809", "#ff9809", "none", "none"},
-		},
-		"B": {
-			"env":   {"#40E83B"},
-			"clo":   {"#00650b", "#62ce5a"},
-			"head":  {"#f7c1a6"},
-			"mouth": {"#6e1c1c"},
-			"eyes":  {"#000", "#ff833b"},
-			"top":   {"#67FFCC", "none", "none", "#ecff3b"},
-		},
-		"C": {
-			"env":   {"#ff2c2c"},
-			"clo":   {"#fff", "#000"},
-			"head":  {"#ffce8b"},
-			"mouth": {"#000"},
-			"eyes":  {"#000", "#0072ff"},
-			"top":   {"#ff9809", "none", "#ffc809", "none"},
-		},
-	},
-	"02": {
-		"A": {
-			"env":   {"#ff7520"},
-			"clo":   {"#d12823"},
-			"head":  {"#fee3c5"},
-			"mouth": {"#d12823"},
-			"eyes":  {"#000", "none"},
-			"top":   {"#000", "none", "none", "#FFCC00", "red"},
-		},
-		"B": {
-			"env":   {"#ff9700"},
-			"clo":   {"#000"},
-			"head":  {"#d2ad6d"},
-			"mouth": {"#000"},
-			"eyes":  {"#000", "#00ffdc"},
-			"top":   {"#fdff00", "#fdff00", "none", "none", "none"},
-		},
-		"C": {
-			"env":   {"#26a7ff"},
-			"clo":   {"#d85cd7"},
-			"head":  {"#542e02"},
-			"mouth": {"#f70014"},
-			"eyes":  {"#000", "magenta"},
-			"top":   {"#FFCC00", "#FFCC00", "#FFCC00", "#ff0000", "yellow"},
-		},
-	},
-	"03": {
-		"A": {
-			"env":   {"#6FC30E"},
-			"clo":   {"#b4e1fa", "#5b5d6e", "#515262", "#a0d2f0", "#a0d2f0"},
-			"head":  {"#fae3b9"},
-			"mouth": {"#fff", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#8eff45", "#8eff45", "none", "none"},
-		},
-		"B": {
-			"env":   {"#00a58c"},
-			"clo":   {"#000", "none", "none", "none", "none"},
-			"head":  {"#FAD2B9"},
-			"mouth": {"#fff", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#FFC600", "none", "#FFC600", "none"},
-		},
-		"C": {
-			"env":   {"#ff501f"},
-			"clo":   {"#000", "#ff0000", "#ff0000", "#7d7d7d", "#7d7d7d"},
-			"head":  {"#fff3dc"},
-			"mouth": {"#d2001b", "none"},
-			"eyes":  {"#000"},
-			"top":   {"#D2001B", "none", "none", "#D2001B"},
-		},
-	},
-	"04": {
-		"A": {
-			"env":   {"#fc0"},
-			"clo":   {"#901e0e", "#ffbe1e", "#ffbe1e", "#c55f54"},
-			"head":  {"#f8d9ad"},
-			"mouth": {"#000", "none", "#000", "none"},
-			"eyes":  {"#000"},
-			"top":   {"#583D00", "#AF892E", "#462D00", "#a0a0a0"},
-		},
-		"B": {
-			"env":   {"#386465"},
-			"clo":   {"#fff", "#333", "#333", "#333"},
-			"head":  {"#FFD79D"},
-			"mouth": {"#000", "#000", "#000", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#27363C", "#5DCAD4", "#314652", "#333"},
-		},
-		"C": {
-			"env":   {"#DFFF00"},
-			"clo":   {"#304267", "#aab0b1", "#aab0b1", "#aab0b1"},
-			"head":  {"#e6b876"},
-			"mouth": {"#50230a", "#50230a", "#50230a", "#50230a"},
-			"eyes":  {"#000"},
-			"top":   {"#333", "#afafaf", "#222", "#6d3a1d"},
-		},
-	},
-	"05": {
-		"A": {
-			"env":   {"#a09300"},
-			"clo":   {"#c7d4e2", "#435363", "#435363", "#141720", "#141720", "#e7ecf2", "#e7ecf2"},
-			"head":  {"#f5d4a6"},
-			"mouth": {"#000", "#cf9f76"},
-			"eyes":  {"#000", "#000", "#000", "#000", "#000", "#000", "#fff", "#fff", "#fff", "#fff", "#000", "#000"},
-			"top":   {"none", "#fdff00"},
-		},
-		"B": {
-			"env":   {"#b3003e"},
-			"clo":   {"#000", "#435363", "#435363", "#000", "none", "#e7ecf2", "#e7ecf2"},
-			"head":  {"#f5d4a6"},
-			"mouth": {"#000", "#af9f94"},
-			"eyes":  {"#9ff3ff;opacity:0.96", "#000", "#9ff3ff;opacity:0.96", "#000", "#2f508a", "#000", "#000", "#000", "none", "none", "none", "none"},
-			"top":   {"#ff9a00", "#ff9a00"},
-		},
-		"C": {
-			"env":   {"#884f00"},
-			"clo":   {"#ff0000", "#fff", "#fff", "#141720", "#141720", "#e7ecf2", "#e7ecf2"},
-			"head":  {"#c57b14"},
-			"mouth": {"#000", "#cf9f76"},
-			"eyes":  {"none", "#000", "none", "#000", "#5a0000", "#000", "#000", "#000", "none", "none", "none", "none"},
-			"top":   {"#efefef", "none"},
-		},
-	},
-	"06": {
-		"A": {
-			"env":   {"#8acf00"},
-			"clo":   {"#ee2829", "#ff0"},
-			"head":  {"#ffce73"},
-			"mouth": {"#fff", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#000", "#000", "none", "#000", "#ff4e4e", "#000"},
-		},
-		"B": {
-			"env":   {"#00d2a3"},
-			"clo":   {"#0D0046", "#ffce73"},
-			"head":  {"#ffce73"},
-			"mouth": {"#000", "none"},
-			"eyes":  {"#000"},
-			"top":   {"#000", "#000", "#000", "none", "#ffb358", "#000", "none", "none"},
-		},
-		"C": {
-			"env":   {"#ff184e"},
-			"clo":   {"#000", "none"},
-			"head":  {"#ffce73"},
-			"mouth": {"#ff0000", "none"},
-			"eyes":  {"#000"},
-			"top":   {"none", "none", "none", "none", "none", "#ffc107", "none", "none"},
-		},
-	},
-	"07": {
-		"A": {
-			"env":   {"#00deae"},
-			"clo":   {"#ff0000"},
-			"head":  {"#ffce94"},
-			"mouth": {"#f73b6c", "#000"},
-			"eyes":  {"#e91e63", "#000", "#e91e63", "#000", "#000", "#000"},
-			"top":   {"#dd104f", "#dd104f", "#f73b6c", "#dd104f"},
-		},
-		"B": {
-			"env":   {"#181284"},
-			"clo":   {"#491f49", "#ff9809", "#491f49"},
-			"head":  {"#f6ba97"},
-			"mouth": {"#ff9809", "#000"},
-			"eyes":  {"#c4ffe4", "#000", "#c4ffe4", "#000", "#000", "#000"},
-			"top":   {"none", "none", "#d6f740", "#516303"},
-		},
-		"C": {
-			"env":   {"#bcf700"},
-			"clo":   {"#ff14e4", "#000", "#14fffd"},
-			"head":  {"#7b401e"},
-			"mouth": {"#666", "#000"},
-			"eyes":  {"#00b5b4", "#000", "#00b5b4", "#000", "#000", "#000"},
-			"top":   {"#14fffd", "#14fffd", "#14fffd", "#0d3a62"},
-		},
-	},
-	"08": {
-		"A": {
-			"env":   {"#0df"},
-			"clo":   {"#571e57", "#ff0"},
-			"head":  {"#f2c280"},
-			"eyes":  {"#795548", "#000"},
-			"mouth": {"#ff0000"},
-			"top":   {"#de3b00", "none"},
-		},
-		"B": {
-			"env":   {"#B400C2"},
-			"clo":   {"#0D204A", "#00ffdf"},
-			"head":  {"#ca8628"},
-			"eyes":  {"#cbbdaf", "#000"},
-			"mouth": {"#1a1a1a"},
-			"top":   {"#000", "#000"},
-		},
-		"C": {
-			"env":   {"#ffe926"},
-			"clo":   {"#00d6af", "#000"},
-			"head":  {"#8c5100"},
-			"eyes":  {"none", "#000"},
-			"mouth": {"#7d0000"},
-			"top":   {"#f7f7f7", "none"},
-		},
-	},
-	"09": {
-		"A": {
-			"env":   {"#4aff0c"},
-			"clo":   {"#101010", "#fff", "#fff"},
-			"head":  {"#dbbc7f"},
-			"mouth": {"#000"},
-			"eyes":  {"#000", "none", "none"},
-			"top":   {"#531148", "#531148", "#531148", "none"},
-		},
-		"B": {
-			"env":   {"#FFC107"},
-			"clo":   {"#033c58", "#fff", "#fff"},
-			"head":  {"#dbc97f"},
-			"mouth": {"#000"},
-			"eyes":  {"none", "#fff", "#000"},
-			"top":   {"#FFEB3B", "#FFEB3B", "none", "#FFEB3B"},
-		},
-		"C": {
-			"env":   {"#FF9800"},
-			"clo":   {"#b40000", "#fff", "#fff"},
-			"head":  {"#E2AF6B"},
-			"mouth": {"#000"},
-			"eyes":  {"none", "#fff", "#000"},
-			"top":   {"#ec0000", "#ec0000", "none", "none"},
-		},
-	},
-	"10": {
-		"A": {
-			"env":   {"#104c8c"},
-			"clo":   {"#354B65", "#3D8EBB", "#89D0DA", "#00FFFD"},
-			"head":  {"#cc9a5c"},
-			"mouth": {"#222", "#fff"},
-			"eyes":  {"#000", "#000"},
-			"top":   {"#fff", "#fff", "none"},
-		},
-		"B": {
-			"env":   {"#0DC15C"},
-			"clo":   {"#212121", "#fff", "#212121", "#fff"},
-			"head":  {"#dca45f"},
-			"mouth": {"#111", "#633b1d"},
-			"eyes":  {"#000", "#000"},
-			"top":   {"none", "#792B74", "#792B74"},
-		},
-		"C": {
-			"env":   {"#ffe500"},
-			"clo":   {"#1e5e80", "#fff", "#1e5e80", "#fff"},
-			"head":  {"#e8bc86"},
-			"mouth": {"#111", "none"},
-			"eyes":  {"#000", "#000"},
-			"top":   {"none", "none", "#633b1d"},
-		},
-	},
-	"11": {
-		"A": {
-			"env":   {"#4a3f73"},
-			"clo":   {"#e6e9ee", "#f1543f", "#ff7058", "#fff", "#fff"},
-			"head":  {"#b27e5b"},
-			"mouth": {"#191919", "#191919"},
-			"eyes":  {"#000", "#000", "#57FFFD"},
-			"top":   {"#ffc", "#ffc", "#ffc"},
-		},
-		"B": {
-			"env":   {"#00a08d"},
-			"clo":   {"#FFBA32", "#484848", "#4e4e4e", "#fff", "#fff"},
-			"head":  {"#ab5f2c"},
-			"mouth": {"#191919", "#191919"},
-			"eyes":  {"#000", "#ff23fa;opacity:0.39", "#000"},
-			"top":   {"#ff90f4", "#ff90f4", "#ff90f4"},
-		},
-		"C": {
-			"env":   {"#22535d"},
-			"clo":   {"#000", "#ff2500", "#ff2500", "#fff", "#fff"},
-			"head":  {"#a76c44"},
-			"mouth": {"#191919", "#191919"},
-			"eyes":  {"#000", "none", "#000"},
-			"top":   {"none", "#00efff", "none"},
-		},
-	},
-	"12": {
-		"A": {
-			"env":   {"#2668DC"},
-			"clo":   {"#2385c6", "#b8d0e0", "#b8d0e0"},
-			"head":  {"#ad8a60"},
-			"mouth": {"#000", "#4d4d4d"},
-			"eyes":  {"#7fb5a2", "#d1eddf", "#301e19"},
-			"top":   {"#fff510", "#fff510"},
-		},
-		"B": {
-			"env":   {"#643869"},
-			"clo":   {"#D67D1B", "#b8d0e0", "#b8d0e0"},
-			"head":  {"#CC985A", "none0000"},
-			"mouth": {"#000", "#ececec"},
-			"eyes":  {"#1f2644", "#9b97ce", "#301e19"},
-			"top":   {"#00eaff", "none"},
-		},
-		"C": {
-			"env":   {"#F599FF"},
-			"clo":   {"#2823C6", "#b8d0e0", "#b8d0e0"},
-			"head":  {"#C7873A"},
-			"mouth": {"#000", "#4d4d4d"},
-			"eyes":  {"#581b1b", "#FF8B8B", "#000"},
-			"top":   {"none", "#9c0092"},
-		},
-	},
-	"13": {
-		"A": {
-			"env":   {"#d10084"},
-			"clo":   {"#efedee", "#00a1e0", "#00a1e0", "#efedee", "#ffce1c"},
-			"head":  {"#b35f49"},
-			"mouth": {"#3a484a", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#000", "none", "#000", "none"},
-		},
-		"B": {
-			"env":   {"#E6C117"},
-			"clo":   {"#efedee", "#ec0033", "#ec0033", "#efedee", "#f2ff05"},
-			"head":  {"#ffc016"},
-			"mouth": {"#4a3737", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#ffe900", "#ffe900", "none", "#ffe900"},
-		},
-		"C": {
-			"env":   {"#1d8c00"},
-			"clo":   {"#e000cb", "#fff", "#fff", "#e000cb", "#ffce1c"},
-			"head":  {"#b96438"},
-			"mouth": {"#000", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#53ffff", "#53ffff", "none", "none"},
-		},
-	},
-	"14": {
-		"A": {
-			"env":   {"#fc0065"},
-			"clo":   {"#708913", "#fdea14", "#708913", "#fdea14", "#708913"},
-			"head":  {"#DEA561"},
-			"mouth": {"#444", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f", "#32393f"},
-		},
-		"B": {
-			"env":   {"#81f72e"},
-			"clo":   {"#ff0000", "#ffc107", "#ff0000", "#ffc107", "#ff0000"},
-			"head":  {"#ef9831"},
-			"mouth": {"#6b0000", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "#FFFAAD", "none", "none", "none", "none"},
-		},
-		"C": {
-			"env":   {"#00D872"},
-			"clo":   {"#590D00", "#FD1336", "#590D00", "#FD1336", "#590D00"},
-			"head":  {"#c36c00"},
-			"mouth": {"#56442b", "#000"},
-			"eyes":  {"#000"},
-			"top":   {"#004E4C", "#004E4C", "#004E4C", "#004E4C", "#004E4C", "#004E4C", "#004E4C", "#004E4C", "#004E4C", "none", "none", "none", "none", "none", "none", "none", "none"},
-		},
-	},
-	"15": {
-		"A": {
-			"env":   {"#111"},
-			"clo":   {"#000", "#00FFFF"},
-			"head":  {"#755227"},
-			"mouth": {"#fff", "#000"},
-			"eyes":  {"black", "#008;opacity:0.67", "aqua"},
-			"top":   {"#fff", "#fff", "#fff", "#fff", "#fff"},
-		},
-		"B": {
-			"env":   {"#00D0D4"},
-			"clo":   {"#000", "#fff"},
-			"head":  {"#755227"},
-			"mouth": {"#fff", "#000"},
-			"eyes":  {"black", "#1df7ff;opacity:0.64", "#fcff2c"},
-			"top":   {"#fff539", "none", "#fff539", "none", "#fff539"},
-		},
-		"C": {
-			"env":   {"#DC75FF"},
-			"clo":   {"#000", "#FFBDEC"},
-			"head":  {"#997549"},
-			"mouth": {"#fff", "#000"},
-			"eyes":  {"black", "black", "aqua"},
-			"top":   {"#00fffd", "none", "none", "none", "none"},
-		},
-	},
+import (
+	"strings"
+	"sync"
+)
+
+// themesData holds the color variations for different avatar parts.
+// It's a direct port from the original JavaScript library, one line per
+// version, theme and part: "<version> <theme> <part> <colors...>". Keeping
+// it as text instead of a map literal spares every binary that imports the
+// package the map's initialization code, and the allocations at startup.
+const themesData = `
+00 A env #ff2f2b
+00 A clo #fff #000
+00 A head #fff
+00 A mouth #fff #000 #000
+00 A eyes #000 none #00FFFF
+00 A top #fff #fff
+00 B env #ff1ec1
+00 B clo #000 #fff
+00 B head #ffc1c1
+00 B mouth #fff #000 #000
+00 B eyes #FF2D00 #fff none
+00 B top #a21d00 #fff
+00 C env #0079b1
+00 C clo #0e00b1 #d1fffe
+00 C head #f5aa77
+00 C mouth #fff #000 #000
+00 C eyes #0c00de #fff none
+00 C top #acfffd #acfffd
+
+01 A env #a50000
+01 A clo #f06 #8e0039
+01 A head #85492C
+01 A mouth #000
+01 A eyes #000 #ff9809
+01 A top #ff9809 #ff9809 none none
+01 B env #40E83B
+01 B clo #00650b #62ce5a
+01 B head #f7c1a6
+01 B mouth #6e1c1c
+01 B eyes #000 #ff833b
+01 B top #67FFCC none none #ecff3b
+01 C env #ff2c2c
+01 C clo #fff #000
+01 C head #ffce8b
+01 C mouth #000
+01 C eyes #000 #0072ff
+01 C top #ff9809 none #ffc809 none
+
+02 A env #ff7520
+02 A clo #d12823
+02 A head #fee3c5
+02 A mouth #d12823
+02 A eyes #000 none
+02 A top #000 none none #FFCC00 red
+02 B env #ff9700
+02 B clo #000
+02 B head #d2ad6d
+02 B mouth #000
+02 B eyes #000 #00ffdc
+02 B top #fdff00 #fdff00 none none none
+02 C env #26a7ff
+02 C clo #d85cd7
+02 C head #542e02
+02 C mouth #f70014
+02 C eyes #000 magenta
+02 C top #FFCC00 #FFCC00 #FFCC00 #ff0000 yellow
+
+03 A env #6FC30E
+03 A clo #b4e1fa #5b5d6e #515262 #a0d2f0 #a0d2f0
+03 A head #fae3b9
+03 A mouth #fff #000
+03 A eyes #000
+03 A top #8eff45 #8eff45 none none
+03 B env #00a58c
+03 B clo #000 none none none none
+03 B head #FAD2B9
+03 B mouth #fff #000
+03 B eyes #000
+03 B top #FFC600 none #FFC600 none
+03 C env #ff501f
+03 C clo #000 #ff0000 #ff0000 #7d7d7d #7d7d7d
+03 C head #fff3dc
+03 C mouth #d2001b none
+03 C eyes #000
+03 C top #D2001B none none #D2001B
+
+04 A env #fc0
+04 A clo #901e0e #ffbe1e #ffbe1e #c55f54
+04 A head #f8d9ad
+04 A mouth #000 none #000 none
+04 A eyes #000
+04 A top #583D00 #AF892E #462D00 #a0a0a0
+04 B env #386465
+04 B clo #fff #333 #333 #333
+04 B head #FFD79D
+04 B mouth #000 #000 #000 #000
+04 B eyes #000
+04 B top #27363C #5DCAD4 #314652 #333
+04 C env #DFFF00
+04 C clo #304267 #aab0b1 #aab0b1 #aab0b1
+04 C head #e6b876
+04 C mouth #50230a #50230a #50230a #50230a
+04 C eyes #000
+04 C top #333 #afafaf #222 #6d3a1d
+
+05 A env #a09300
+05 A clo #c7d4e2 #435363 #435363 #141720 #141720 #e7ecf2 #e7ecf2
+05 A head #f5d4a6
+05 A mouth #000 #cf9f76
+05 A eyes #000 #000 #000 #000 #000 #000 #fff #fff #fff #fff #000 #000
+05 A top none #fdff00
+05 B env #b3003e
+05 B clo #000 #435363 #435363 #000 none #e7ecf2 #e7ecf2
+05 B head #f5d4a6
+05 B mouth #000 #af9f94
+05 B eyes #9ff3ff;opacity:0.96 #000 #9ff3ff;opacity:0.96 #000 #2f508a #000 #000 #000 none none none none
+05 B top #ff9a00 #ff9a00
+05 C env #884f00
+05 C clo #ff0000 #fff #fff #141720 #141720 #e7ecf2 #e7ecf2
+05 C head #c57b14
+05 C mouth #000 #cf9f76
+05 C eyes none #000 none #000 #5a0000 #000 #000 #000 none none none none
+05 C top #efefef none
+
+06 A env #8acf00
+06 A clo #ee2829 #ff0
+06 A head #ffce73
+06 A mouth #fff #000
+06 A eyes #000
+06 A top #000 #000 none #000 #ff4e4e #000
+06 B env #00d2a3
+06 B clo #0D0046 #ffce73
+06 B head #ffce73
+06 B mouth #000 none
+06 B eyes #000
+06 B top #000 #000 #000 none #ffb358 #000 none none
+06 C env #ff184e
+06 C clo #000 none
+06 C head #ffce73
+06 C mouth #ff0000 none
+06 C eyes #000
+06 C top none none none none none #ffc107 none none
+
+07 A env #00deae
+07 A clo #ff0000
+07 A head #ffce94
+07 A mouth #f73b6c #000
+07 A eyes #e91e63 #000 #e91e63 #000 #000 #000
+07 A top #dd104f #dd104f #f73b6c #dd104f
+07 B env #181284
+07 B clo #491f49 #ff9809 #491f49
+07 B head #f6ba97
+07 B mouth #ff9809 #000
+07 B eyes #c4ffe4 #000 #c4ffe4 #000 #000 #000
+07 B top none none #d6f740 #516303
+07 C env #bcf700
+07 C clo #ff14e4 #000 #14fffd
+07 C head #7b401e
+07 C mouth #666 #000
+07 C eyes #00b5b4 #000 #00b5b4 #000 #000 #000
+07 C top #14fffd #14fffd #14fffd #0d3a62
+
+08 A env #0df
+08 A clo #571e57 #ff0
+08 A head #f2c280
+08 A eyes #795548 #000
+08 A mouth #ff0000
+08 A top #de3b00 none
+08 B env #B400C2
+08 B clo #0D204A #00ffdf
+08 B head #ca8628
+08 B eyes #cbbdaf #000
+08 B mouth #1a1a1a
+08 B top #000 #000
+08 C env #ffe926
+08 C clo #00d6af #000
+08 C head #8c5100
+08 C eyes none #000
+08 C mouth #7d0000
+08 C top #f7f7f7 none
+
+09 A env #4aff0c
+09 A clo #101010 #fff #fff
+09 A head #dbbc7f
+09 A mouth #000
+09 A eyes #000 none none
+09 A top #531148 #531148 #531148 none
+09 B env #FFC107
+09 B clo #033c58 #fff #fff
+09 B head #dbc97f
+09 B mouth #000
+09 B eyes none #fff #000
+09 B top #FFEB3B #FFEB3B none #FFEB3B
+09 C env #FF9800
+09 C clo #b40000 #fff #fff
+09 C head #E2AF6B
+09 C mouth #000
+09 C eyes none #fff #000
+09 C top #ec0000 #ec0000 none none
+
+10 A env #104c8c
+10 A clo #354B65 #3D8EBB #89D0DA #00FFFD
+10 A head #cc9a5c
+10 A mouth #222 #fff
+10 A eyes #000 #000
+10 A top #fff #fff none
+10 B env #0DC15C
+10 B clo #212121 #fff #212121 #fff
+10 B head #dca45f
+10 B mouth #111 #633b1d
+10 B eyes #000 #000
+10 B top none #792B74 #792B74
+10 C env #ffe500
+10 C clo #1e5e80 #fff #1e5e80 #fff
+10 C head #e8bc86
+10 C mouth #111 none
+10 C eyes #000 #000
+10 C top none none #633b1d
+
+11 A env #4a3f73
+11 A clo #e6e9ee #f1543f #ff7058 #fff #fff
+11 A head #b27e5b
+11 A mouth #191919 #191919
+11 A eyes #000 #000 #57FFFD
+11 A top #ffc #ffc #ffc
+11 B env #00a08d
+11 B clo #FFBA32 #484848 #4e4e4e #fff #fff
+11 B head #ab5f2c
+11 B mouth #191919 #191919
+11 B eyes #000 #ff23fa;opacity:0.39 #000
+11 B top #ff90f4 #ff90f4 #ff90f4
+11 C env #22535d
+11 C clo #000 #ff2500 #ff2500 #fff #fff
+11 C head #a76c44
+11 C mouth #191919 #191919
+11 C eyes #000 none #000
+11 C top none #00efff none
+
+12 A env #2668DC
+12 A clo #2385c6 #b8d0e0 #b8d0e0
+12 A head #ad8a60
+12 A mouth #000 #4d4d4d
+12 A eyes #7fb5a2 #d1eddf #301e19
+12 A top #fff510 #fff510
+12 B env #643869
+12 B clo #D67D1B #b8d0e0 #b8d0e0
+12 B head #CC985A none0000
+12 B mouth #000 #ececec
+12 B eyes #1f2644 #9b97ce #301e19
+12 B top #00eaff none
+12 C env #F599FF
+12 C clo #2823C6 #b8d0e0 #b8d0e0
+12 C head #C7873A
+12 C mouth #000 #4d4d4d
+12 C eyes #581b1b #FF8B8B #000
+12 C top none #9c0092
+
+13 A env #d10084
+13 A clo #efedee #00a1e0 #00a1e0 #efedee #ffce1c
+13 A head #b35f49
+13 A mouth #3a484a #000
+13 A eyes #000
+13 A top #000 none #000 none
+13 B env #E6C117
+13 B clo #efedee #ec0033 #ec0033 #efedee #f2ff05
+13 B head #ffc016
+13 B mouth #4a3737 #000
+13 B eyes #000
+13 B top #ffe900 #ffe900 none #ffe900
+13 C env #1d8c00
+13 C clo #e000cb #fff #fff #e000cb #ffce1c
+13 C head #b96438
+13 C mouth #000 #000
+13 C eyes #000
+13 C top #53ffff #53ffff none none
+
+14 A env #fc0065
+14 A clo #708913 #fdea14 #708913 #fdea14 #708913
+14 A head #DEA561
+14 A mouth #444 #000
+14 A eyes #000
+14 A top #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f #32393f
+14 B env #81f72e
+14 B clo #ff0000 #ffc107 #ff0000 #ffc107 #ff0000
+14 B head #ef9831
+14 B mouth #6b0000 #000
+14 B eyes #000
+14 B top #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD #FFFAAD none none none none
+14 C env #00D872
+14 C clo #590D00 #FD1336 #590D00 #FD1336 #590D00
+14 C head #c36c00
+14 C mouth #56442b #000
+14 C eyes #000
+14 C top #004E4C #004E4C #004E4C #004E4C #004E4C #004E4C #004E4C #004E4C #004E4C none none none none none none none none
+
+15 A env #111
+15 A clo #000 #00FFFF
+15 A head #755227
+15 A mouth #fff #000
+15 A eyes black #008;opacity:0.67 aqua
+15 A top #fff #fff #fff #fff #fff
+15 B env #00D0D4
+15 B clo #000 #fff
+15 B head #755227
+15 B mouth #fff #000
+15 B eyes black #1df7ff;opacity:0.64 #fcff2c
+15 B top #fff539 none #fff539 none #fff539
+15 C env #DC75FF
+15 C clo #000 #FFBDEC
+15 C head #997549
+15 C mouth #fff #000
+15 C eyes black black aqua
+15 C top #00fffd none none none none
+`
+
+var (
+	themesOnce sync.Once
+	// themes maps version -> theme -> part -> colors
+	themes map[string]map[string]map[string][]string
+)
+
+// themeTable returns the themes of the active art.
+func themeTable() map[string]map[string]map[string][]string {
+	if art := loadedArt.Load(); art != nil {
+		return art.themes
+	}
+	return builtinThemes()
+}
+
+// builtinThemes returns the built-in themes, parsed on first use.
+func builtinThemes() map[string]map[string]map[string][]string {
+	themesOnce.Do(func() {
+		themes = make(map[string]map[string]map[string][]string, 16)
+		for _, line := range strings.Split(themesData, "\n") {
+			f := strings.Fields(line)
+			if len(f) < 3 {
+				continue
+			}
+			version, theme, part := f[0], f[1], f[2]
+			if themes[version] == nil {
+				themes[version] = make(map[string]map[string][]string, 3)
+			}
+			if themes[version][theme] == nil {
+				themes[version][theme] = make(map[string][]string, len(partNames))
+			}
+			themes[version][theme][part] = f[3:]
+		}
+	})
+	return themes
 }
@@ -0,0 +1,13 @@
+//go:build !tinygo && !multiavatar_minimal
+
+package multiavatar
+
+import "regexp"
+
+// placeholderRe matches color placeholders like "#01;" (same pattern as the JS original).
+var placeholderRe = regexp.MustCompile(`#(.*?);`)
+
+// findPlaceholders returns the color placeholders of a part template in order.
+func findPlaceholders(svg string) []string {
+	return placeholderRe.FindAllString(svg, -1)
+}
@@ -0,0 +1,31 @@
+//go:build tinygo || multiavatar_minimal
+
+package multiavatar
+
+import "strings"
+
+// findPlaceholders returns the color placeholders of a part template in order.
+//
+// It is a hand-written equivalent of the regexp `#(.*?);` for builds without
+// the regexp package (TinyGo and other minimal targets): each match runs from
+// a '#' to the nearest following ';' and scanning resumes after it.
+func findPlaceholders(svg string) []string {
+	var out []string
+	for {
+		start := strings.IndexByte(svg, '#')
+		if start < 0 {
+			return out
+		}
+		end := strings.IndexByte(svg[start:], ';')
+		if end < 0 {
+			return out
+		}
+		// '.' does not match newlines in the regexp version
+		if strings.IndexByte(svg[start:start+end], '\n') >= 0 {
+			svg = svg[start+1:]
+			continue
+		}
+		out = append(out, svg[start:start+end+1])
+		svg = svg[start+end+1:]
+	}
+}
@@ -0,0 +1,223 @@
+package multiavatar
+
+import "fmt"
+
+// Part names a part for the typed options such as WithPartVersionT, so a
+// misspelled part is a compile error rather than an error reported at
+// generation time.
+type Part int
+
+const (
+	// PartEnv is the background.
+	PartEnv Part = iota
+	// PartClothes is the clothing.
+	PartClothes
+	// PartHead is the head, with neck and shoulders.
+	PartHead
+	// PartMouth is the mouth.
+	PartMouth
+	// PartEyes is the eyes, with glasses or visors.
+	PartEyes
+	// PartTop is the hair or headwear.
+	PartTop
+	// PartHat is the optional hat; see WithPart.
+	PartHat
+	// PartAccessory is the optional accessory; see WithPart.
+	PartAccessory
+)
+
+// typedPartNames are the part names of the Part values, indexed by value.
+var typedPartNames = []string{"env", "clo", "head", "mouth", "eyes", "top", "hat", "accessory"}
+
+// String returns the part name the string-based options take, e.g. "clo".
+func (p Part) String() string {
+	if p >= 0 && int(p) < len(typedPartNames) {
+		return typedPartNames[p]
+	}
+	return fmt.Sprintf("Part(%d)", int(p))
+}
+
+// ParsePart returns the Part named s, e.g. "eyes".
+func ParsePart(s string) (Part, bool) {
+	for i, name := range typedPartNames {
+		if s == name {
+			return Part(i), true
+		}
+	}
+	return 0, false
+}
+
+// Theme is one of the three color themes of a part version.
+type Theme int
+
+// The themes, "A" to "C".
+const (
+	ThemeA Theme = iota
+	ThemeB
+	ThemeC
+)
+
+// String returns the theme letter, e.g. "B".
+func (t Theme) String() string {
+	if t >= ThemeA && t <= ThemeC {
+		return string(rune('A' + t))
+	}
+	return fmt.Sprintf("Theme(%d)", int(t))
+}
+
+// Version is a part version. V00 to V15 are the versions of every part;
+// the head also has the head shapes 16 to 19, better chosen by name with
+// WithNamedPart.
+type Version int
+
+// The versions of every part, "00" to "15".
+const (
+	V00 Version = iota
+	V01
+	V02
+	V03
+	V04
+	V05
+	V06
+	V07
+	V08
+	V09
+	V10
+	V11
+	V12
+	V13
+	V14
+	V15
+)
+
+// String returns the two-digit version code, e.g. "07".
+func (v Version) String() string {
+	if v >= 0 && v < 100 {
+		return fmt.Sprintf("%02d", int(v))
+	}
+	return fmt.Sprintf("Version(%d)", int(v))
+}
+
+// checkPart reports whether p is a known part, recording an error if not.
+func (c *config) checkPart(p Part) bool {
+	if p < 0 || int(p) >= len(typedPartNames) {
+		c.errs = append(c.errs, ErrInvalidPart{Name: p.String()})
+		return false
+	}
+	return true
+}
+
+// checkTheme reports whether t is a known theme, recording an error if not.
+func (c *config) checkTheme(t Theme) bool {
+	if t < ThemeA || t > ThemeC {
+		c.errs = append(c.errs, fmt.Errorf("multiavatar: unknown theme %d", int(t)))
+		return false
+	}
+	return true
+}
+
+// checkVersion reports whether v is a version of p, recording an error if
+// not.
+func (c *config) checkVersion(p Part, v Version) bool {
+	return c.checkPartVersion(p.String(), v.String())
+}
+
+// checkPartName reports whether name is a known part, recording an error
+// if not.
+func (c *config) checkPartName(name string) bool {
+	if _, ok := ParsePart(name); !ok {
+		c.errs = append(c.errs, ErrInvalidPart{Name: name})
+		return false
+	}
+	return true
+}
+
+// checkPartVersion reports whether v is a version code of the known part
+// name, recording an error if not.
+func (c *config) checkPartVersion(name, v string) bool {
+	ok := validPartVersion(name, v)
+	if x := extraPartByName(name); x != nil {
+		_, ok = x.version(v)
+	}
+	if !ok {
+		c.errs = append(c.errs, ErrInvalidVersion{Part: name, Version: v})
+		return false
+	}
+	return true
+}
+
+// WithPartVersionT is WithPartVersion with typed arguments, e.g.
+// WithPartVersionT(PartEyes, V11).
+func WithPartVersionT(p Part, v Version) Option {
+	return func(c *config) {
+		if c.checkPart(p) && c.checkVersion(p, v) {
+			WithPartVersion(p.String(), v.String())(c)
+		}
+	}
+}
+
+// WithAllowedVersionsT is WithAllowedVersions with typed arguments,
+// reporting invalid values as errors.
+func WithAllowedVersionsT(p Part, versions ...Version) Option {
+	return func(c *config) {
+		if !c.checkPart(p) {
+			return
+		}
+		codes := make([]string, len(versions))
+		for i, v := range versions {
+			if !c.checkVersion(p, v) {
+				return
+			}
+			codes[i] = v.String()
+		}
+		WithAllowedVersions(p.String(), codes)(c)
+	}
+}
+
+// WithThemeT is WithTheme with a typed argument, reporting an invalid
+// theme as an error.
+func WithThemeT(t Theme) Option {
+	return func(c *config) {
+		if c.checkTheme(t) {
+			WithTheme(t.String())(c)
+		}
+	}
+}
+
+// WithPartThemeT is WithPartTheme with typed arguments, reporting invalid
+// values as errors.
+func WithPartThemeT(p Part, t Theme) Option {
+	return func(c *config) {
+		if c.checkPart(p) && c.checkTheme(t) {
+			WithPartTheme(p.String(), t.String())(c)
+		}
+	}
+}
+
+// WithAllowedThemesT is WithAllowedThemes with typed arguments, reporting
+// invalid values as errors.
+func WithAllowedThemesT(p Part, themes ...Theme) Option {
+	return func(c *config) {
+		if !c.checkPart(p) {
+			return
+		}
+		letters := make([]string, len(themes))
+		for i, t := range themes {
+			if !c.checkTheme(t) {
+				return
+			}
+			letters[i] = t.String()
+		}
+		WithAllowedThemes(p.String(), letters)(c)
+	}
+}
+
+// WithoutPartT is WithoutPart with a typed argument, reporting an unknown
+// part as an error.
+func WithoutPartT(p Part) Option {
+	return func(c *config) {
+		if c.checkPart(p) {
+			WithoutPart(p.String())(c)
+		}
+	}
+}
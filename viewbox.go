@@ -0,0 +1,60 @@
+package multiavatar
+
+import (
+	"fmt"
+	"math"
+)
+
+// canvasSize is the width and height of the artwork's own coordinate space.
+const canvasSize = 231
+
+// WithPadding insets the avatar by units on every side, measured in the
+// 231-unit artwork coordinates. The canvas grows instead of the artwork
+// shrinking, leaving a transparent margin for rings, borders or badges.
+// Negative values are reported as errors by the error-returning APIs.
+func WithPadding(units float64) Option {
+	return func(c *config) {
+		if units < 0 || math.IsNaN(units) || math.IsInf(units, 0) {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid padding %v", units))
+			return
+		}
+		c.padding = units
+	}
+}
+
+// WithViewBox sets the viewBox of the root <svg> element, e.g. to crop the
+// avatar to the face with WithViewBox(40, 20, 150, 150). Coordinates are in
+// the 231-unit artwork space. WithPadding, when also given, is applied
+// around this box. Non-positive widths or heights are reported as errors.
+func WithViewBox(x, y, w, h float64) Option {
+	return func(c *config) {
+		for _, v := range []float64{x, y, w, h} {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid viewBox %v %v %v %v", x, y, w, h))
+				return
+			}
+		}
+		if w <= 0 || h <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("multiavatar: invalid viewBox %v %v %v %v", x, y, w, h))
+			return
+		}
+		c.viewBox = &[4]float64{x, y, w, h}
+	}
+}
+
+// viewBoxAttr returns the viewBox attribute value for the avatar.
+func (cfg *config) viewBoxAttr() string {
+	if cfg.viewBox == nil && cfg.padding == 0 && cfg.rotation == 0 {
+		return "0 0 231 231"
+	}
+	vb := cfg.baseViewBox()
+	if cfg.rotation != 0 {
+		vb = cfg.rotateBox(vb)
+	}
+	p := cfg.padding
+	attr := formatFloat(vb[0]-p) + " " + formatFloat(vb[1]-p) + " " + formatFloat(vb[2]+2*p) + " " + formatFloat(vb[3]+2*p)
+	if cfg.precision != nil {
+		attr = roundNumbers(attr, *cfg.precision, false)
+	}
+	return attr
+}
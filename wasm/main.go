@@ -0,0 +1,58 @@
+//go:build js && wasm
+
+// Command wasm exposes the avatar generator to JavaScript, so frontends can
+// render avatars client-side with the same code, and the same bytes, as a
+// Go server. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o multiavatar.wasm ./wasm
+//
+// and load it with the wasm_exec.js shipped in $(go env GOROOT)/lib/wasm:
+//
+//	const go = new Go();
+//	const { instance } = await WebAssembly.instantiateStreaming(fetch("multiavatar.wasm"), go.importObject);
+//	go.run(instance);
+//	const svg = multiavatar.generate("alice", '{"theme":"B"}');
+//
+// generate takes the name and, optionally, options as the JSON of
+// multiavatar.Options. It returns the SVG, or an Error for an empty name
+// or invalid options.
+package main
+
+import (
+	"strings"
+	"syscall/js"
+
+	"github.com/changzee/multiavatar-go"
+)
+
+func main() {
+	js.Global().Set("multiavatar", js.ValueOf(map[string]any{
+		"generate": js.FuncOf(generate),
+	}))
+	// keep the exported functions alive
+	select {}
+}
+
+// generate implements multiavatar.generate(name, optionsJSON).
+func generate(_ js.Value, args []js.Value) any {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return jsError("multiavatar: generate expects a name string")
+	}
+	var opts []multiavatar.Option
+	if len(args) > 1 && args[1].Type() == js.TypeString && args[1].String() != "" {
+		var err error
+		if opts, err = multiavatar.FromJSON([]byte(args[1].String())); err != nil {
+			return jsError(err.Error())
+		}
+	}
+	var b strings.Builder
+	if err := multiavatar.GenerateTo(&b, args[0].String(), opts...); err != nil {
+		return jsError(err.Error())
+	}
+	return b.String()
+}
+
+// jsError returns a JavaScript Error with message msg.
+func jsError(msg string) js.Value {
+	return js.Global().Get("Error").New(msg)
+}
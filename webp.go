@@ -0,0 +1,402 @@
+package multiavatar
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"image"
+	"io"
+)
+
+// encodeWebP writes img as a lossless WebP (VP8L) image. The encoder is
+// deliberately small: no transforms and no color cache, only literals and
+// backward references to the pixel on the left or above, which is where
+// the flat fills of an avatar repeat. That keeps files close to PNG size.
+func encodeWebP(w io.Writer, img *image.RGBA) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	argb := make([]uint32, 0, width*height)
+	alpha := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < width; x++ {
+			p := row[4*x : 4*x+4]
+			r, g, b := unpremultiply(p)
+			if p[3] == 0 {
+				r, g, b = 0, 0, 0
+			}
+			alpha = alpha || p[3] != 255
+			argb = append(argb, uint32(p[3])<<24|uint32(r)<<16|uint32(g)<<8|uint32(b))
+		}
+	}
+
+	var bw webpBitWriter
+	bw.writeBits(0x2f, 8) // VP8L signature
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	bw.writeBits(boolBit(alpha), 1)
+	bw.writeBits(0, 3) // version
+	bw.writeBits(0, 1) // no transform
+	bw.writeBits(0, 1) // no color cache
+	bw.writeBits(0, 1) // a single group of prefix codes
+
+	tokens := webpTokens(argb, width)
+	var hist [5][]int
+	hist[0] = make([]int, 256+24) // green and length prefixes
+	for i := 1; i < 4; i++ {
+		hist[i] = make([]int, 256)
+	}
+	hist[4] = make([]int, 40)
+	for _, t := range tokens {
+		if t.length == 0 {
+			hist[0][t.argb>>8&0xff]++
+			hist[1][t.argb>>16&0xff]++
+			hist[2][t.argb&0xff]++
+			hist[3][t.argb>>24]++
+			continue
+		}
+		lc, _, _ := webpPrefix(t.length)
+		dc, _, _ := webpPrefix(t.dist)
+		hist[0][256+lc]++
+		hist[4][dc]++
+	}
+	var codes [5]webpCode
+	for i := range codes {
+		codes[i] = bw.writeCode(hist[i])
+	}
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			codes[0].write(&bw, int(t.argb>>8&0xff))
+			codes[1].write(&bw, int(t.argb>>16&0xff))
+			codes[2].write(&bw, int(t.argb&0xff))
+			codes[3].write(&bw, int(t.argb>>24))
+			continue
+		}
+		lc, ln, lx := webpPrefix(t.length)
+		codes[0].write(&bw, 256+lc)
+		bw.writeBits(lx, ln)
+		dc, dn, dx := webpPrefix(t.dist)
+		codes[4].write(&bw, dc)
+		bw.writeBits(dx, dn)
+	}
+	data := bw.flush()
+
+	chunk := len(data) + len(data)&1
+	var hdr [20]byte
+	copy(hdr[0:], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(4+8+chunk))
+	copy(hdr[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(hdr[16:], uint32(len(data)))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+	if len(data)&1 == 1 {
+		data = append(data, 0)
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// webpToken is a literal pixel, or a copy of length pixels from dist
+// pixels back when length is non-zero. dist is already a VP8L distance
+// code: 1 is the pixel above and 2 the pixel on the left.
+type webpToken struct {
+	argb         uint32
+	length, dist int
+}
+
+// webpMaxCopy is the longest backward reference VP8L allows.
+const webpMaxCopy = 4096
+
+// webpTokens greedily splits pixels into literals and copies of the left
+// or upper neighbours.
+func webpTokens(argb []uint32, width int) []webpToken {
+	var tokens []webpToken
+	run := func(i, back int) int {
+		n := 0
+		for i+n < len(argb) && n < webpMaxCopy && argb[i+n] == argb[i+n-back] {
+			n++
+		}
+		return n
+	}
+	for i := 0; i < len(argb); {
+		left, up := 0, 0
+		if i >= 1 {
+			left = run(i, 1)
+		}
+		if i >= width {
+			up = run(i, width)
+		}
+		switch {
+		case left >= 3 && left >= up:
+			tokens = append(tokens, webpToken{length: left, dist: 2})
+			i += left
+		case up >= 3:
+			tokens = append(tokens, webpToken{length: up, dist: 1})
+			i += up
+		default:
+			tokens = append(tokens, webpToken{argb: argb[i]})
+			i++
+		}
+	}
+	return tokens
+}
+
+// webpPrefix splits a length or distance code v >= 1 into its prefix
+// symbol and extra bits.
+func webpPrefix(v int) (code int, nbits uint, extra uint32) {
+	d := v - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	h := 0
+	for d>>(h+1) != 0 {
+		h++
+	}
+	second := d >> (h - 1) & 1
+	nbits = uint(h - 1)
+	return 2*h + second, nbits, uint32(d & (1<<nbits - 1))
+}
+
+// webpBitWriter packs bits least significant first.
+type webpBitWriter struct {
+	buf   bytes.Buffer
+	acc   uint64
+	nbits uint
+}
+
+func (bw *webpBitWriter) writeBits(v uint32, n uint) {
+	bw.acc |= uint64(v) << bw.nbits
+	bw.nbits += n
+	for bw.nbits >= 8 {
+		bw.buf.WriteByte(byte(bw.acc))
+		bw.acc >>= 8
+		bw.nbits -= 8
+	}
+}
+
+func (bw *webpBitWriter) flush() []byte {
+	if bw.nbits > 0 {
+		bw.buf.WriteByte(byte(bw.acc))
+		bw.acc, bw.nbits = 0, 0
+	}
+	return bw.buf.Bytes()
+}
+
+func boolBit(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// webpCode is a canonical prefix code, with each code stored bit-reversed
+// so it can be written least significant bit first.
+type webpCode struct {
+	lengths []uint8
+	codes   []uint32
+}
+
+func (c webpCode) write(bw *webpBitWriter, sym int) {
+	bw.writeBits(c.codes[sym], uint(c.lengths[sym]))
+}
+
+// webpCodeLengthOrder is the order in which code length code lengths are stored.
+var webpCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// writeCode writes the prefix code for the symbol counts hist and returns it.
+func (bw *webpBitWriter) writeCode(hist []int) webpCode {
+	var used []int
+	for s, n := range hist {
+		if n > 0 {
+			used = append(used, s)
+		}
+	}
+	if len(used) <= 2 && (len(used) == 0 || used[len(used)-1] < 256) {
+		// simple code: one or two 8-bit symbols
+		c := webpCode{lengths: make([]uint8, len(hist)), codes: make([]uint32, len(hist))}
+		if len(used) == 0 {
+			used = []int{0}
+		}
+		bw.writeBits(1, 1)
+		bw.writeBits(uint32(len(used)-1), 1)
+		bw.writeBits(1, 1) // 8-bit first symbol
+		bw.writeBits(uint32(used[0]), 8)
+		if len(used) == 2 {
+			bw.writeBits(uint32(used[1]), 8)
+			c.lengths[used[0]], c.lengths[used[1]] = 1, 1
+			c.codes[used[1]] = 1
+		}
+		return c
+	}
+
+	lengths := huffmanLengths(hist, 15)
+	// Code lengths are written as literals 0..15, with runs of zeros
+	// shortened by symbols 17 (3..10) and 18 (11..138).
+	type clToken struct{ sym, extra int }
+	var tokens []clToken
+	clHist := make([]int, 19)
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, clToken{int(lengths[i]), 0})
+			clHist[lengths[i]]++
+			i++
+			continue
+		}
+		n := 0
+		for i+n < len(lengths) && lengths[i+n] == 0 && n < 138 {
+			n++
+		}
+		switch {
+		case n >= 11:
+			tokens = append(tokens, clToken{18, n - 11})
+			clHist[18]++
+		case n >= 3:
+			tokens = append(tokens, clToken{17, n - 3})
+			clHist[17]++
+		default:
+			for range n {
+				tokens = append(tokens, clToken{0, 0})
+			}
+			clHist[0] += n
+		}
+		i += n
+	}
+	clLengths := huffmanLengths(clHist, 7)
+	num := 19
+	for num > 4 && clLengths[webpCodeLengthOrder[num-1]] == 0 {
+		num--
+	}
+	bw.writeBits(0, 1) // normal code
+	bw.writeBits(uint32(num-4), 4)
+	for _, s := range webpCodeLengthOrder[:num] {
+		bw.writeBits(uint32(clLengths[s]), 3)
+	}
+	bw.writeBits(0, 1) // every symbol has a length
+	cl := canonicalCode(clLengths)
+	for _, t := range tokens {
+		cl.write(bw, t.sym)
+		switch t.sym {
+		case 17:
+			bw.writeBits(uint32(t.extra), 3)
+		case 18:
+			bw.writeBits(uint32(t.extra), 7)
+		}
+	}
+	return canonicalCode(lengths)
+}
+
+// canonicalCode assigns canonical, bit-reversed codes to lengths.
+func canonicalCode(lengths []uint8) webpCode {
+	c := webpCode{lengths: lengths, codes: make([]uint32, len(lengths))}
+	var count [16]uint32
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+	var next [16]uint32
+	code := uint32(0)
+	for l := 1; l < 16; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		v := next[l]
+		next[l]++
+		r := uint32(0)
+		for range l {
+			r = r<<1 | v&1
+			v >>= 1
+		}
+		c.codes[s] = r
+	}
+	return c
+}
+
+// huffmanLengths returns Huffman code lengths of at most limit bits for
+// the symbol counts hist, which must have at least two non-zero counts
+// for every symbol to get a length of at least one bit. Counts are halved
+// until the tree fits the limit.
+func huffmanLengths(hist []int, limit uint8) []uint8 {
+	counts := append([]int(nil), hist...)
+	for {
+		lengths, ok := tryHuffmanLengths(counts, limit)
+		if ok {
+			return lengths
+		}
+		for i, n := range counts {
+			if n > 0 {
+				counts[i] = max(1, n/2)
+			}
+		}
+	}
+}
+
+type huffNode struct {
+	count       int
+	sym         int // leaf symbol, or -1
+	left, right *huffNode
+}
+
+type huffHeap []*huffNode
+
+func (h huffHeap) Len() int { return len(h) }
+func (h huffHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].sym > h[j].sym
+}
+func (h huffHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *huffHeap) Push(x any)   { *h = append(*h, x.(*huffNode)) }
+func (h *huffHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+func tryHuffmanLengths(counts []int, limit uint8) ([]uint8, bool) {
+	lengths := make([]uint8, len(counts))
+	var h huffHeap
+	for s, n := range counts {
+		if n > 0 {
+			h = append(h, &huffNode{count: n, sym: s})
+		}
+	}
+	switch len(h) {
+	case 0:
+		return lengths, true
+	case 1:
+		// a lone symbol still needs a one-bit code; pair it with a neighbour
+		other := 0
+		if h[0].sym == 0 {
+			other = 1
+		}
+		lengths[h[0].sym], lengths[other] = 1, 1
+		return lengths, true
+	}
+	heap.Init(&h)
+	for h.Len() > 1 {
+		a := heap.Pop(&h).(*huffNode)
+		b := heap.Pop(&h).(*huffNode)
+		heap.Push(&h, &huffNode{count: a.count + b.count, sym: -1, left: a, right: b})
+	}
+	ok := true
+	var walk func(n *huffNode, depth uint8)
+	walk = func(n *huffNode, depth uint8) {
+		if n.sym >= 0 {
+			lengths[n.sym] = depth
+			ok = ok && depth <= limit
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	walk(h[0], 0)
+	return lengths, ok
+}
@@ -0,0 +1,107 @@
+package multiavatar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebPRoundTrip(t *testing.T) {
+	avatar := func(w, h int) *image.RGBA {
+		img, err := rasterizeSVG(Generate("alice"), w, h)
+		if err != nil {
+			t.Fatalf("rasterizeSVG: %v", err)
+		}
+		return img
+	}
+	noise := func(w, h int) *image.RGBA {
+		rng := rand.New(rand.NewPCG(uint64(w), uint64(h)))
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		for y := range h {
+			for x := range w {
+				a := uint8(rng.IntN(256))
+				img.SetRGBA(x, y, color.RGBA{
+					uint8(rng.IntN(int(a) + 1)), uint8(rng.IntN(int(a) + 1)), uint8(rng.IntN(int(a) + 1)), a,
+				})
+			}
+		}
+		return img
+	}
+	opaque := image.NewRGBA(image.Rect(0, 0, 5, 3))
+	for i := range opaque.Pix {
+		opaque.Pix[i] = uint8(i * 37)
+		if i%4 == 3 {
+			opaque.Pix[i] = 0xff
+		}
+	}
+
+	tests := []struct {
+		name  string
+		img   *image.RGBA
+		alpha bool
+	}{
+		{"single pixel", noise(1, 1), true},
+		{"opaque", opaque, false},
+		{"avatar with transparent corners", avatar(64, 64), true},
+		{"avatar with odd size", avatar(37, 23), true},
+		{"noise with odd size", noise(51, 17), true},
+		{"sub-image", avatar(40, 40).SubImage(image.Rect(3, 5, 34, 28)).(*image.RGBA), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := encodeWebP(&buf, tt.img); err != nil {
+				t.Fatalf("encodeWebP: %v", err)
+			}
+			got, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("webp.Decode: %v", err)
+			}
+			b := tt.img.Bounds()
+			if got.Bounds().Dx() != b.Dx() || got.Bounds().Dy() != b.Dy() {
+				t.Fatalf("decoded bounds = %v, want %dx%d", got.Bounds(), b.Dx(), b.Dy())
+			}
+			cfg, err := webp.DecodeConfig(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("webp.DecodeConfig: %v", err)
+			}
+			if hasAlpha := cfg.ColorModel == color.NRGBAModel; tt.alpha && !hasAlpha {
+				t.Errorf("colour model = %v, want NRGBA for an image with transparency", cfg.ColorModel)
+			}
+			for y := 0; y < b.Dy(); y++ {
+				for x := 0; x < b.Dx(); x++ {
+					want := tt.img.RGBAAt(b.Min.X+x, b.Min.Y+y)
+					if !sameWebPPixel(want, color.NRGBAModel.Convert(got.At(x, y)).(color.NRGBA)) {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got.At(x, y), want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// sameWebPPixel reports whether the straight-alpha pixel got decodes from
+// the premultiplied source pixel want. Alpha and the colour of opaque pixels
+// must match exactly; translucent colour channels lose precision when they
+// are unpremultiplied, so they may round down by one once premultiplied
+// again.
+func sameWebPPixel(want color.RGBA, got color.NRGBA) bool {
+	if got.A != want.A {
+		return false
+	}
+	if want.A == 0 {
+		return true
+	}
+	if want.A == 0xff {
+		return got.R == want.R && got.G == want.G && got.B == want.B
+	}
+	within := func(w, g uint8) bool {
+		p := int(g) * int(want.A) / 0xff
+		return p == int(w) || p == int(w)-1
+	}
+	return within(want.R, got.R) && within(want.G, got.G) && within(want.B, got.B)
+}
@@ -0,0 +1,218 @@
+package multiavatar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a significant line of a YAML document.
+type yamlLine struct {
+	n      int // 1-based line number
+	indent int
+	text   string
+}
+
+// parseYAML parses the subset of YAML used by configuration files such as
+// policies: block mappings and sequences, flow sequences ([a, b]), plain,
+// single- and double-quoted strings, and comments. Every scalar is a
+// string. It returns nil for an empty document.
+func parseYAML(doc string) (any, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(doc, "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		body := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(body, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		text := strings.TrimSpace(stripYAMLComment(body))
+		if text == "" || (len(lines) == 0 && text == "---") {
+			continue
+		}
+		lines = append(lines, yamlLine{n: i + 1, indent: len(raw) - len(body), text: text})
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	v, next, err := parseYAMLBlock(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[next].n)
+	}
+	return v, nil
+}
+
+// stripYAMLComment drops a "#" comment that starts a line or follows a
+// space, outside quotes.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// parseYAMLBlock parses the mapping or sequence whose lines start at
+// lines[i] with the given indentation, and returns the index of the first
+// line after it.
+func parseYAMLBlock(lines []yamlLine, i, indent int) (any, int, error) {
+	if isYAMLItem(lines[i].text) {
+		var seq []any
+		for i < len(lines) && lines[i].indent == indent && isYAMLItem(lines[i].text) {
+			item := strings.TrimSpace(strings.TrimPrefix(lines[i].text, "-"))
+			if i+1 < len(lines) && lines[i+1].indent > indent {
+				return nil, 0, fmt.Errorf("line %d: nested blocks in sequence items are not supported", lines[i+1].n)
+			}
+			v, err := parseYAMLValue(item, lines[i].n)
+			if err != nil {
+				return nil, 0, err
+			}
+			seq = append(seq, v)
+			i++
+		}
+		return seq, i, nil
+	}
+
+	m := make(map[string]any)
+	for i < len(lines) && lines[i].indent == indent && !isYAMLItem(lines[i].text) {
+		line := lines[i]
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, 0, fmt.Errorf("line %d: expected key: value", line.n)
+		}
+		k, err := parseYAMLScalar(key, line.n)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, dup := m[k]; dup {
+			return nil, 0, fmt.Errorf("line %d: duplicate key %q", line.n, k)
+		}
+		i++
+		switch {
+		case rest != "":
+			if m[k], err = parseYAMLValue(rest, line.n); err != nil {
+				return nil, 0, err
+			}
+		case i < len(lines) && (lines[i].indent > indent || lines[i].indent == indent && isYAMLItem(lines[i].text)):
+			// a nested block, or a sequence at the key's own indentation
+			if m[k], i, err = parseYAMLBlock(lines, i, lines[i].indent); err != nil {
+				return nil, 0, err
+			}
+		default:
+			m[k] = nil
+		}
+	}
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, 0, fmt.Errorf("line %d: unexpected indentation", lines[i].n)
+	}
+	return m, i, nil
+}
+
+func isYAMLItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits "key: value" at the first ": " or trailing ":"
+// outside quotes.
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// parseYAMLValue parses an inline value: a flow sequence or a scalar.
+func parseYAMLValue(s string, n int) (any, error) {
+	if !strings.HasPrefix(s, "[") {
+		return parseYAMLScalar(s, n)
+	}
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("line %d: unterminated flow sequence", n)
+	}
+	seq := []any{}
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if inner == "" {
+		return seq, nil
+	}
+	var quote byte
+	start := 0
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			c := inner[i]
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c == '[' || c == '{':
+				return nil, fmt.Errorf("line %d: nested flow collections are not supported", n)
+			case c != ',':
+				continue
+			}
+		}
+		item := strings.TrimSpace(inner[start:i])
+		if item == "" {
+			if i == len(inner) {
+				break // a trailing comma
+			}
+			return nil, fmt.Errorf("line %d: empty flow sequence entry", n)
+		}
+		v, err := parseYAMLScalar(item, n)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+		start = i + 1
+	}
+	return seq, nil
+}
+
+// parseYAMLScalar unquotes a single- or double-quoted string and returns
+// plain scalars as they are.
+func parseYAMLScalar(s string, n int) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("line %d: invalid double-quoted string %s", n, s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("line %d: invalid single-quoted string %s", n, s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.HasPrefix(s, "{"), strings.HasPrefix(s, "&"), strings.HasPrefix(s, "*"),
+		strings.HasPrefix(s, "|"), strings.HasPrefix(s, ">"):
+		return "", fmt.Errorf("line %d: unsupported YAML syntax %q", n, s)
+	}
+	return s, nil
+}
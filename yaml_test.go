@@ -0,0 +1,85 @@
+package multiavatar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want any
+	}{
+		{"empty", "", nil},
+		{"only comments", "# policy\n\n   # nothing here\n", nil},
+		{"document marker", "---\nname: acme\n", map[string]any{"name": "acme"}},
+		{"comments", "name: acme # the tenant\n# full line\ntheme: B\n", map[string]any{"name": "acme", "theme": "B"}},
+		{"hash inside value", "color: '#ff0000'\nurl: a#b\n", map[string]any{"color": "#ff0000", "url": "a#b"}},
+		{"double-quoted", `title: "say \"hi\"\t# not a comment"`, map[string]any{"title": "say \"hi\"\t# not a comment"}},
+		{"single-quoted", "title: 'it''s: fine'", map[string]any{"title": "it's: fine"}},
+		{"quoted key", `"top: hair": short`, map[string]any{"top: hair": "short"}},
+		{"empty value", "theme:\n", map[string]any{"theme": nil}},
+		{"CRLF", "a: 1\r\nb: 2\r\n", map[string]any{"a": "1", "b": "2"}},
+		{"nested maps", "parts:\n  top:\n    version: \"07\"\n  eyes:\n    theme: C\n",
+			map[string]any{"parts": map[string]any{
+				"top":  map[string]any{"version": "07"},
+				"eyes": map[string]any{"theme": "C"},
+			}}},
+		{"block sequence", "without:\n  - top\n  - 'eyes'\n", map[string]any{"without": []any{"top", "eyes"}}},
+		{"sequence at key indentation", "without:\n- top\n- env\nsize: 64\n",
+			map[string]any{"without": []any{"top", "env"}, "size": "64"}},
+		{"flow sequence", `allowed: [01, "03", 'a,b', ]`, map[string]any{"allowed": []any{"01", "03", "a,b"}}},
+		{"empty flow sequence", "allowed: []", map[string]any{"allowed": []any{}}},
+		{"top-level sequence", "- a\n- b\n", []any{"a", "b"}},
+		{"lists in nested maps", "allowedVersions:\n  eyes: [03, 11]\n  top:\n    - \"01\"\n    - \"07\"\n",
+			map[string]any{"allowedVersions": map[string]any{
+				"eyes": []any{"03", "11"},
+				"top":  []any{"01", "07"},
+			}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(tt.doc)
+			if err != nil {
+				t.Fatalf("parseYAML: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name, doc string
+	}{
+		{"tab indentation", "parts:\n\ttop: a\n"},
+		{"missing colon", "name: acme\njust text\n"},
+		{"duplicate key", "theme: A\ntheme: B\n"},
+		{"unterminated flow sequence", "allowed: [01, 03\n"},
+		{"empty flow sequence entry", "allowed: [01,, 03]\n"},
+		{"only a comma", "allowed: [,]\n"},
+		{"nested flow sequence", "allowed: [[01], 03]\n"},
+		{"flow mapping", "parts: {top: a}\n"},
+		{"unterminated double quote", `title: "abc`},
+		{"unterminated single quote", "title: 'abc\n"},
+		{"trailing text after quote", `title: "a" b`},
+		{"anchor", "a: &x b\n"},
+		{"alias", "a: *x\n"},
+		{"block scalar", "a: |\n  text\n"},
+		{"indented after scalar", "a: 1\n  b: 2\n"},
+		{"dedent into nothing", "a:\n    b: 1\n  c: 2\n"},
+		{"nested block in item", "- a\n  - b\n"},
+		{"mixed mapping and sequence", "a: 1\n- b\n"},
+		{"unexpected indentation at end", "a:\n  b: 1\n c: 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v, err := parseYAML(tt.doc); err == nil {
+				t.Errorf("parseYAML(%q) = %#v, want an error", tt.doc, v)
+			}
+		})
+	}
+}
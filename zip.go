@@ -0,0 +1,93 @@
+package multiavatar
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportZip streams a zip archive with one file per input to w, e.g. for a
+// "download all team avatars" button. Files are named after their input,
+// reduced to letters, digits and ._@- and made unique with a numeric
+// suffix; empty inputs are skipped. Raster files are WithSize pixels
+// wide, 256 by default.
+func ExportZip(w io.Writer, inputs []string, format Format, opts ...Option) error {
+	return ExportZipContext(context.Background(), w, inputs, format, opts...)
+}
+
+// ExportZipContext is like ExportZip but stops and returns ctx's error once
+// ctx is done; the archive written so far is left unfinished.
+func ExportZipContext(ctx context.Context, w io.Writer, inputs []string, format Format, opts ...Option) error {
+	cfg := newConfig(opts)
+	if err := cfg.checkOptions(ctx, "ExportZip"); err != nil {
+		return err
+	}
+	if err := cfg.checkFormat(format); err != nil {
+		return err
+	}
+	start, n := time.Now(), 0
+
+	zw := zip.NewWriter(w)
+	used := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		if input == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := zipName(input, format, used)
+		method := zip.Deflate
+		if format != FormatSVG {
+			method = zip.Store // already compressed
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		if err != nil {
+			return err
+		}
+		bw := bufio.NewWriter(fw)
+		t := time.Now()
+		err = cfg.encode(ctx, bw, input, format, opts)
+		cfg.logRender(ctx, "ExportZip", input, format, cfg.size, t, err)
+		if err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		n++
+	}
+	err := zw.Close()
+	cfg.logBatch(ctx, "ExportZip", n, start, err)
+	return err
+}
+
+// zipName derives a unique, path-safe file name for input.
+func zipName(input string, format Format, used map[string]bool) string {
+	var b strings.Builder
+	for _, r := range input {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '@', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+		if b.Len() >= 100 {
+			break
+		}
+	}
+	base := strings.TrimLeft(b.String(), ".")
+	if base == "" {
+		base = "avatar"
+	}
+	name := base + format.Extension()
+	for i := 2; used[name]; i++ {
+		name = base + "-" + strconv.Itoa(i) + format.Extension()
+	}
+	used[name] = true
+	return name
+}